@@ -2,17 +2,22 @@ package coinbase
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
+// credentialsTTL is how long the credentials returned by a CredentialProvider
+// are used for before they are requested again.
+const credentialsTTL = time.Minute
+
 var errInvalidRoundTripArgs = fmt.Errorf("invalid auth arguments")
 
 // roundTripper is an HTTP round tripper that acts as a middleware to add
@@ -26,41 +31,72 @@ func (rtripper *roundTripper) RoundTrip(req *http.Request) (*http.Response, erro
 	return rtripper.roundTrip(req)
 }
 
+// signConfig configures how requests are signed and sent, from the client's
+// options.
+type signConfig struct {
+	// transport sends the signed requests, nil meaning the default HTTP
+	// transport.
+	transport http.RoundTripper
+
+	// signingPath transforms the request path before it is signed, nil
+	// meaning the path is signed as is.
+	signingPath func(string) string
+
+	// inspect is called with each signed message, if it is non-nil.
+	inspect func(method, path, signedMessage string)
+
+	// resolution is the resolution of the signed timestamp.
+	resolution TimestampResolution
+
+	// omitQuery is set when the query string is left out of the signed
+	// path.
+	omitQuery bool
+}
+
 // newRoundTrip signs the given HTTP request with the provided Coinbase API
-// key and secret, and sends the request using the default HTTP transport. The
-// signed request includes the current timestamp, HTTP method, request path, and
-// request body (if present). The function returns the HTTP response and any
-// error that occurred during the request. If an error occurs during the
+// key and secret, and sends the request using the config's transport. The
+// signed request includes the current timestamp, HTTP method, request path,
+// and request body (if present). If the config has a signing path function, the
+// request path is passed through it before it is signed, while the request is
+// still sent to the original path. The query string is signed along with the
+// path, unless the config omits it. The function returns the HTTP response and
+// any error that occurred during the request. If an error occurs during the
 // request, it is wrapped with additional context information.
-func newRoundTrip(req *http.Request, key, secret string) (*http.Response, error) {
+func newRoundTrip(req *http.Request, key, secret string, config signConfig) (*http.Response, error) {
 	var body []byte
 	if req.Body != nil {
 		body, _ = io.ReadAll(req.Body)
 		req.Body = io.NopCloser(bytes.NewBuffer(body))
 	}
 
-	signature := hmac.New(sha256.New, []byte(secret))
-
 	rpath := req.URL.Path
-	if req.URL.RawQuery != "" {
-		rpath = fmt.Sprintf("%s?%s", req.URL.Path, req.URL.RawQuery)
+	if config.signingPath != nil {
+		rpath = config.signingPath(rpath)
+	}
+
+	if req.URL.RawQuery != "" && !config.omitQuery {
+		rpath = fmt.Sprintf("%s?%s", rpath, req.URL.RawQuery)
 	}
 
-	formatBase := 10
-	unix := strconv.FormatInt(time.Now().Unix(), formatBase)
+	unix := config.resolution.format(time.Now())
 
 	msg := strings.Join([]string{unix, req.Method, rpath, string(body)}, "")
+	sig := sign(secret, msg)
 
-	// Don't handle error because hash.Write method never returns an
-	// error.
-	signature.Write([]byte(msg))
-	sig := hex.EncodeToString(signature.Sum(nil))
+	if config.inspect != nil {
+		config.inspect(req.Method, rpath, msg)
+	}
 
 	req.Header.Add("cb-access-key", key)
 	req.Header.Add("cb-access-sign", sig)
 	req.Header.Add("cb-access-timestamp", unix)
 
-	rsp, err := http.DefaultTransport.RoundTrip(req)
+	transport := config.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	rsp, err := transport.RoundTrip(req)
 	if err != nil {
 		return nil, fmt.Errorf("error making request: %w", err)
 	}
@@ -68,19 +104,99 @@ func newRoundTrip(req *http.Request, key, secret string) (*http.Response, error)
 	return rsp, nil
 }
 
+// sign returns the hex-encoded HMAC SHA256 signature of the message using the
+// secret.
+func sign(secret, msg string) string {
+	signature := hmac.New(sha256.New, []byte(secret))
+
+	// Don't handle error because hash.Write method never returns an
+	// error.
+	signature.Write([]byte(msg))
+
+	return hex.EncodeToString(signature.Sum(nil))
+}
+
 // newRoundTripper will return a "RoundTrip" function that can be used
 // as a "RoundTrip" function in an "http.RoundTripper" interface to authenticate
-// requests to the Coinbase Cloud API.
-func newRoundTripper(key, secret string) (*roundTripper, error) {
+// requests to the Coinbase Cloud API. The requests are signed and sent as set
+// by the config.
+func newRoundTripper(key, secret string, config signConfig) (*roundTripper, error) {
 	if key == "" || secret == "" {
 		return nil, errInvalidRoundTripArgs
 	}
 
 	rtripper := &roundTripper{
 		roundTrip: func(req *http.Request) (*http.Response, error) {
-			return newRoundTrip(req, key, secret)
+			return newRoundTrip(req, key, secret, config)
 		},
 	}
 
 	return rtripper, nil
 }
+
+// CredentialProvider provides the API key and secret that requests are signed
+// with, for deployments where they are rotated, such as secrets read from a
+// vault.
+type CredentialProvider interface {
+	Credentials(ctx context.Context) (key, secret string, err error)
+}
+
+// WithCredentialProvider signs requests with the credentials returned by the
+// provider instead of the key and secret given to NewClient, which are ignored
+// and may be empty. The credentials are cached for a minute, so that the
+// provider is not called for every request, after which rotated credentials
+// are picked up by the next request.
+func WithCredentialProvider(provider CredentialProvider) ClientOption {
+	return func(client *Client) {
+		client.credentials = &credentialsCache{provider: provider}
+	}
+}
+
+// credentialsCache holds the credentials last returned by a provider.
+type credentialsCache struct {
+	provider CredentialProvider
+
+	mu      sync.Mutex
+	key     string
+	secret  string
+	fetched time.Time
+}
+
+// get returns the cached credentials, requesting them from the provider if they
+// are older than credentialsTTL.
+func (cache *credentialsCache) get(ctx context.Context) (string, string, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if !cache.fetched.IsZero() && time.Since(cache.fetched) < credentialsTTL {
+		return cache.key, cache.secret, nil
+	}
+
+	key, secret, err := cache.provider.Credentials(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get credentials: %w", err)
+	}
+
+	if key == "" || secret == "" {
+		return "", "", errInvalidRoundTripArgs
+	}
+
+	cache.key, cache.secret, cache.fetched = key, secret, time.Now()
+
+	return key, secret, nil
+}
+
+// newProviderRoundTripper is like newRoundTripper, but signs each request with
+// the credentials from the cache.
+func newProviderRoundTripper(cache *credentialsCache, config signConfig) *roundTripper {
+	return &roundTripper{
+		roundTrip: func(req *http.Request) (*http.Response, error) {
+			key, secret, err := cache.get(req.Context())
+			if err != nil {
+				return nil, err
+			}
+
+			return newRoundTrip(req, key, secret, config)
+		},
+	}
+}
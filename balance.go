@@ -0,0 +1,123 @@
+package coinbase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultBalancePollInterval is the poll interval used by WatchBalance when a
+// non-positive interval is given.
+const defaultBalancePollInterval = 10 * time.Second
+
+// WatchBalance returns a channel on which the available balance of the user's
+// accounts in the currency, summed as by AccountsMap, is sent: first the
+// current balance, then the new balance each time it changes. The balance is
+// fetched before WatchBalance returns, which fails with ErrAccountNotFound if
+// the user has no account in the currency.
+//
+// The balance is polled every interval, since the user WebSocket channel only
+// carries order updates. Each poll pages through the accounts and goes through
+// the client's retries, so a rate-limited poll backs off, and a poll that still
+// fails is skipped. Balances are compared as decimals, so "100" and "100.00"
+// are the same balance. A balance is only sent once the previous one has been
+// received, and the channel is closed once the context is cancelled.
+func (client *Client) WatchBalance(ctx context.Context, currency string, pollInterval time.Duration,
+	opts ...CallOption,
+) (<-chan AvailableMoney, error) {
+	if pollInterval <= 0 {
+		pollInterval = defaultBalancePollInterval
+	}
+
+	balance, found, err := client.availableBalance(ctx, currency, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w: no %s account", ErrAccountNotFound, currency)
+	}
+
+	balances := make(chan AvailableMoney)
+
+	go client.watchBalance(ctx, currency, pollInterval, balance, balances, opts)
+
+	return balances, nil
+}
+
+// watchBalance sends the balance and polls for changes to it every interval,
+// until the context is cancelled.
+func (client *Client) watchBalance(ctx context.Context, currency string, pollInterval time.Duration,
+	balance AvailableMoney, balances chan<- AvailableMoney, opts []CallOption,
+) {
+	defer close(balances)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case balances <- balance:
+		case <-ctx.Done():
+			return
+		}
+
+		balance = client.nextBalance(ctx, currency, balance, ticker.C, opts)
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// nextBalance polls the balance on every tick until it differs from the last
+// one, which it returns. If the context is cancelled the last balance is
+// returned.
+func (client *Client) nextBalance(ctx context.Context, currency string, last AvailableMoney,
+	ticks <-chan time.Time, opts []CallOption,
+) AvailableMoney {
+	// The balances returned by AccountsMap are always decimals.
+	lastAmount, _ := last.Amount()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return last
+		case <-ticks:
+		}
+
+		// An account that is no longer listed has a balance of zero,
+		// so it is not treated differently.
+		balance, _, err := client.availableBalance(ctx, currency, opts)
+		if err != nil {
+			continue
+		}
+
+		amount, err := balance.Amount()
+		if err != nil || amount.Equal(lastAmount) {
+			continue
+		}
+
+		return balance
+	}
+}
+
+// availableBalance returns the available balance of the user's accounts in the
+// currency, matched case-insensitively, and whether the user has any account
+// in it. The balance of a currency without an account is zero.
+func (client *Client) availableBalance(ctx context.Context, currency string,
+	opts []CallOption,
+) (AvailableMoney, bool, error) {
+	balances, err := client.AccountsMap(ctx, opts...)
+	if err != nil {
+		return AvailableMoney{}, false, err
+	}
+
+	for accountCurrency, balance := range balances {
+		if strings.EqualFold(accountCurrency, currency) {
+			return balance, true, nil
+		}
+	}
+
+	return AvailableMoney{Value: "0", Currency: currency}, false, nil
+}
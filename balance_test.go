@@ -0,0 +1,89 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatchBalance(t *testing.T) {
+	t.Parallel()
+
+	// Each poll returns the next balance, the last one repeating.
+	polls := []string{"", "100", "100.00", "150.5", "150.50"}
+
+	var calls int32
+
+	client := &Client{
+		httpClient: mockDoFunc(func(*http.Request) (*http.Response, error) {
+			call := int(atomic.AddInt32(&calls, 1)) - 1
+			if call >= len(polls) {
+				call = len(polls) - 1
+			}
+
+			return newMockResponse(http.StatusOK, `{"accounts": [
+				{"currency": "BTC", "available_balance": {"value": "1", "currency": "BTC"}},
+				{"currency": "USD", "available_balance": {"value": "`+polls[call]+`", "currency": "USD"}}
+			]}`), nil
+		}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	balances, err := client.WatchBalance(ctx, "usd", time.Millisecond)
+	if err != nil {
+		t.Fatalf("failed to watch balance: %v", err)
+	}
+
+	for _, want := range []AvailableMoney{
+		{Value: "0", Currency: "USD"},
+		{Value: "100", Currency: "USD"},
+		{Value: "150.5", Currency: "USD"},
+	} {
+		select {
+		case got := <-balances:
+			if got != want {
+				t.Fatalf("got balance %v, want %v", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for balance %v", want)
+		}
+	}
+
+	// The balance no longer changes, so nothing else is sent.
+	select {
+	case got := <-balances:
+		t.Fatalf("got balance %v, want none", got)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-balances:
+		if ok {
+			t.Fatalf("got a balance after the context was cancelled")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("balances still open after the context was cancelled")
+	}
+}
+
+func TestWatchBalanceNoAccount(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{
+		httpClient: &mockClient{
+			response:   []byte(`{"accounts": [{"currency": "BTC", "available_balance": {"value": "1", "currency": "BTC"}}]}`),
+			statusCode: http.StatusOK,
+		},
+	}
+
+	if _, err := client.WatchBalance(context.Background(), "USD", time.Millisecond); !errors.Is(err, ErrAccountNotFound) {
+		t.Fatalf("got %v, want %v", err, ErrAccountNotFound)
+	}
+}
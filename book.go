@@ -0,0 +1,255 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// basisPointsPerUnit is the number of basis points in one.
+const basisPointsPerUnit = 10000
+
+// productBooksConcurrency is the number of product books that GetProductBooks
+// requests at a time.
+const productBooksConcurrency = 8
+
+// ErrMissingBidAsk is returned when a price is derived from a book that has no
+// bid or no ask.
+var ErrMissingBidAsk = errors.New("missing bid or ask")
+
+// PriceLevel represents the size available at a price in an order book.
+type PriceLevel struct {
+	Price string `json:"price"`
+	Size  string `json:"size"`
+}
+
+// BestBidAsk represents the best bid and ask of a product, the first bid and
+// ask being the best.
+type BestBidAsk struct {
+	ProductID string       `json:"product_id"`
+	Bids      []PriceLevel `json:"bids"`
+	Asks      []PriceLevel `json:"asks"`
+	Time      time.Time    `json:"time"`
+}
+
+// BestBidAsks represents a collection of best bids and asks.
+type BestBidAsks struct {
+	Data []BestBidAsk `json:"pricebooks"`
+}
+
+// GetBestBidAsk returns the best bid and ask of each of the given products, or
+// of every product if none are given.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_getbestbidask
+func (client *Client) GetBestBidAsk(ctx context.Context, productIDs []string,
+	opts ...CallOption,
+) (*BestBidAsks, error) {
+	path := []string{"brokerage", "best_bid_ask"}
+
+	query := url.Values{}
+	for _, productID := range productIDs {
+		query.Add("product_ids", productID)
+	}
+
+	books := &BestBidAsks{}
+	if err := client.do(ctx, http.MethodGet, path, query, nil, books, opts); err != nil {
+		return nil, err
+	}
+
+	return books, nil
+}
+
+// ProductBook represents the order book of a product, with the best bids and
+// asks first. It is an alias of BestBidAsk, so that its first levels give the
+// best bid and ask and its Mid and Spread methods can be used.
+type ProductBook = BestBidAsk
+
+// getProductBookResponse is the response from getting a single product book.
+type getProductBookResponse struct {
+	Book ProductBook `json:"pricebook"`
+}
+
+// GetProductBook returns the order book of a product, with up to limit price
+// levels on each side, or the API's default number of levels if limit is
+// zero.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_getproductbook
+func (client *Client) GetProductBook(ctx context.Context, productID string, limit int32,
+	opts ...CallOption,
+) (*ProductBook, error) {
+	path := []string{"brokerage", "product_book"}
+
+	query := url.Values{}
+	query.Set("product_id", productID)
+
+	if limit > 0 {
+		formatBase := 10
+		query.Set("limit", strconv.FormatInt(int64(limit), formatBase))
+	}
+
+	resp := &getProductBookResponse{}
+	if err := client.do(ctx, http.MethodGet, path, query, nil, resp, opts); err != nil {
+		return nil, err
+	}
+
+	return &resp.Book, nil
+}
+
+// ProductBooksError is returned by GetProductBooks when the books of some of
+// the products could not be fetched.
+type ProductBooksError struct {
+	// Errors holds the error for each product whose book could not be
+	// fetched.
+	Errors map[string]error
+}
+
+// Error returns the error message, which lists the errors by product ID.
+func (err *ProductBooksError) Error() string {
+	productIDs := make([]string, 0, len(err.Errors))
+	for productID := range err.Errors {
+		productIDs = append(productIDs, productID)
+	}
+
+	sort.Strings(productIDs)
+
+	msgs := make([]string, len(productIDs))
+	for i, productID := range productIDs {
+		msgs[i] = fmt.Sprintf("%s: %v", productID, err.Errors[productID])
+	}
+
+	return fmt.Sprintf("failed to get %d product books: %s", len(productIDs), strings.Join(msgs, "; "))
+}
+
+// GetProductBooks returns the order books of the given products keyed by
+// product ID, with up to limit price levels on each side, requesting a few
+// books at a time. A product whose book cannot be fetched does not stop the
+// others: the books that were fetched are returned along with a
+// *ProductBooksError holding the error for each product that failed. Once the
+// context is cancelled, no more books are requested and the products not yet
+// requested fail with the context's error.
+func (client *Client) GetProductBooks(ctx context.Context, productIDs []string, limit int32,
+	opts ...CallOption,
+) (map[string]*ProductBook, error) {
+	books := make(map[string]*ProductBook, len(productIDs))
+	errs := make(map[string]error)
+	sem := make(chan struct{}, productBooksConcurrency)
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for i, productID := range productIDs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			for _, productID := range productIDs[i:] {
+				errs[productID] = ctx.Err()
+			}
+			mu.Unlock()
+
+			wg.Wait()
+
+			return books, &ProductBooksError{Errors: errs}
+		}
+
+		wg.Add(1)
+
+		go func(productID string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			book, err := client.GetProductBook(ctx, productID, limit, opts...)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				errs[productID] = err
+
+				return
+			}
+
+			books[productID] = book
+		}(productID)
+	}
+
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return books, &ProductBooksError{Errors: errs}
+	}
+
+	return books, nil
+}
+
+// best returns the best bid and ask prices, or ErrMissingBidAsk if either is
+// missing.
+func (book BestBidAsk) best() (decimal.Decimal, decimal.Decimal, error) {
+	if len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return decimal.Decimal{}, decimal.Decimal{}, fmt.Errorf("%w: %s", ErrMissingBidAsk, book.ProductID)
+	}
+
+	bid, err := decimal.NewFromString(book.Bids[0].Price)
+	if err != nil {
+		return decimal.Decimal{}, decimal.Decimal{}, fmt.Errorf("failed to parse bid %q: %w", book.Bids[0].Price, err)
+	}
+
+	ask, err := decimal.NewFromString(book.Asks[0].Price)
+	if err != nil {
+		return decimal.Decimal{}, decimal.Decimal{}, fmt.Errorf("failed to parse ask %q: %w", book.Asks[0].Price, err)
+	}
+
+	return bid, ask, nil
+}
+
+// Mid returns the price halfway between the best bid and the best ask.
+func (book BestBidAsk) Mid() (decimal.Decimal, error) {
+	bid, ask, err := book.best()
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	return bid.Add(ask).Div(decimal.NewFromInt(2)), nil //nolint:gomnd
+}
+
+// Spread returns the difference between the best ask and the best bid.
+func (book BestBidAsk) Spread() (decimal.Decimal, error) {
+	bid, ask, err := book.best()
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	return ask.Sub(bid), nil
+}
+
+// SpreadBps returns the spread in basis points of the mid price.
+func (book BestBidAsk) SpreadBps() (decimal.Decimal, error) {
+	mid, err := book.Mid()
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	if mid.IsZero() {
+		return decimal.Decimal{}, fmt.Errorf("%w: %s has a zero mid price", ErrMissingBidAsk, book.ProductID)
+	}
+
+	spread, err := book.Spread()
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	return spread.Div(mid).Mul(decimal.NewFromInt(basisPointsPerUnit)), nil
+}
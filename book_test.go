@@ -0,0 +1,275 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestGetBestBidAsk(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response []byte
+		want     *BestBidAsks
+		err      error
+	}{
+		{
+			name: "nil",
+			err:  io.EOF, // end of file, nothing in response
+		},
+		{
+			name:     "empty slice",
+			response: []byte(`{}`),
+			want:     &BestBidAsks{},
+		},
+		{
+			name: "single",
+			response: []byte(`
+{
+  "pricebooks": [{
+    "product_id": "BTC-USD",
+    "bids": [{"price": "29999.5", "size": "0.1"}],
+    "asks": [{"price": "30000.5", "size": "0.2"}],
+    "time": "2023-07-28T15:00:00Z"
+  }]
+}`),
+			want: &BestBidAsks{
+				Data: []BestBidAsk{
+					{
+						ProductID: "BTC-USD",
+						Bids:      []PriceLevel{{Price: "29999.5", Size: "0.1"}},
+						Asks:      []PriceLevel{{Price: "30000.5", Size: "0.2"}},
+						Time:      time.Date(2023, time.July, 28, 15, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{
+				httpClient: &mockClient{
+					response:   test.response,
+					statusCode: http.StatusOK,
+				},
+			}
+
+			got, err := client.GetBestBidAsk(context.Background(), []string{"BTC-USD"})
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestBestBidAskPrices(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		book      BestBidAsk
+		mid       string
+		spread    string
+		spreadBps string
+		err       error
+	}{
+		{
+			name: "bid and ask",
+			book: BestBidAsk{
+				Bids: []PriceLevel{{Price: "99.5"}, {Price: "99"}},
+				Asks: []PriceLevel{{Price: "100.5"}, {Price: "101"}},
+			},
+			mid:       "100",
+			spread:    "1",
+			spreadBps: "100",
+		},
+		{
+			name: "missing bid",
+			book: BestBidAsk{Asks: []PriceLevel{{Price: "100.5"}}},
+			err:  ErrMissingBidAsk,
+		},
+		{
+			name: "missing ask",
+			book: BestBidAsk{Bids: []PriceLevel{{Price: "99.5"}}},
+			err:  ErrMissingBidAsk,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			prices := []struct {
+				name  string
+				price func() (decimal.Decimal, error)
+				want  string
+			}{
+				{name: "mid", price: test.book.Mid, want: test.mid},
+				{name: "spread", price: test.book.Spread, want: test.spread},
+				{name: "spread bps", price: test.book.SpreadBps, want: test.spreadBps},
+			}
+
+			for _, price := range prices {
+				got, err := price.price()
+				if !errors.Is(err, test.err) {
+					t.Fatalf("%s: got %v, want %v", price.name, err, test.err)
+				}
+
+				if test.err == nil && !got.Equal(decimal.RequireFromString(price.want)) {
+					t.Fatalf("%s: got %s, want %s", price.name, got, price.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetProductBook(t *testing.T) {
+	t.Parallel()
+
+	var gotQuery string
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			gotQuery = req.URL.RawQuery
+
+			return newMockResponse(http.StatusOK, `{"pricebook": {"product_id": "BTC-USD", `+
+				`"bids": [{"price": "100", "size": "1"}], "asks": [{"price": "101", "size": "2"}], `+
+				`"time": "2023-05-31T09:59:59Z"}}`), nil
+		}),
+	}
+
+	got, err := client.GetProductBook(context.Background(), "BTC-USD", 1)
+	if err != nil {
+		t.Fatalf("failed to get product book: %v", err)
+	}
+
+	want := &ProductBook{
+		ProductID: "BTC-USD",
+		Bids:      []PriceLevel{{Price: "100", Size: "1"}},
+		Asks:      []PriceLevel{{Price: "101", Size: "2"}},
+		Time:      time.Date(2023, 5, 31, 9, 59, 59, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	if want := "limit=1&product_id=BTC-USD"; gotQuery != want {
+		t.Fatalf("got query %q, want %q", gotQuery, want)
+	}
+}
+
+func TestGetProductBooks(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			productID := req.URL.Query().Get("product_id")
+			if productID == "BAD-USD" {
+				return newMockResponse(http.StatusNotFound, `{"error": "NOT_FOUND"}`), nil
+			}
+
+			return newMockResponse(http.StatusOK, `{"pricebook": {"product_id": "`+productID+`"}}`), nil
+		}),
+	}
+
+	productIDs := []string{"BTC-USD", "BAD-USD", "ETH-USD", "SOL-USD"}
+
+	books, err := client.GetProductBooks(context.Background(), productIDs, 1)
+
+	booksErr := &ProductBooksError{}
+	if !errors.As(err, &booksErr) {
+		t.Fatalf("got %v, want %T", err, booksErr)
+	}
+
+	if len(booksErr.Errors) != 1 || !errors.Is(booksErr.Errors["BAD-USD"], ErrStatusNotOK) {
+		t.Fatalf("got errors %v, want one for BAD-USD", booksErr.Errors)
+	}
+
+	if len(books) != 3 {
+		t.Fatalf("got %d books, want 3", len(books))
+	}
+
+	for _, productID := range []string{"BTC-USD", "ETH-USD", "SOL-USD"} {
+		if book := books[productID]; book == nil || book.ProductID != productID {
+			t.Fatalf("got book %+v for %s", book, productID)
+		}
+	}
+
+	if _, err := client.GetProductBooks(context.Background(), []string{"BTC-USD"}, 1); err != nil {
+		t.Fatalf("got %v, want no error", err)
+	}
+}
+
+func TestGetProductBooksCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var requests int32
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			// The first books hold every slot until the call is
+			// cancelled.
+			if atomic.AddInt32(&requests, 1) == productBooksConcurrency {
+				cancel()
+			}
+
+			<-req.Context().Done()
+
+			return nil, req.Context().Err()
+		}),
+	}
+
+	productIDs := make([]string, 0, productBooksConcurrency+2)
+	for i := 0; i < cap(productIDs); i++ {
+		productIDs = append(productIDs, fmt.Sprintf("P%d-USD", i))
+	}
+
+	books, err := client.GetProductBooks(ctx, productIDs, 1)
+
+	booksErr := &ProductBooksError{}
+	if !errors.As(err, &booksErr) {
+		t.Fatalf("got %v, want %T", err, booksErr)
+	}
+
+	if len(booksErr.Errors) != len(productIDs) {
+		t.Fatalf("got %d errors, want %d", len(booksErr.Errors), len(productIDs))
+	}
+
+	for _, productID := range productIDs {
+		if err := booksErr.Errors[productID]; !errors.Is(err, context.Canceled) {
+			t.Fatalf("got error %v for %s, want %v", err, productID, context.Canceled)
+		}
+	}
+
+	if len(books) != 0 {
+		t.Fatalf("got %d books, want none", len(books))
+	}
+
+	if got := atomic.LoadInt32(&requests); got != productBooksConcurrency {
+		t.Fatalf("got %d requests, want %d", got, productBooksConcurrency)
+	}
+}
@@ -0,0 +1,247 @@
+package coinbase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderBuilder builds an OrderRequest step by step, checking the request once
+// it is built. The first error encountered by a step is returned by Build.
+type OrderBuilder struct {
+	req     OrderRequest
+	product *Product
+	err     error
+}
+
+// NewOrderBuilder returns a builder for an order with the given client order
+// ID, which Coinbase uses to deduplicate orders.
+func NewOrderBuilder(clientOrderID string) *OrderBuilder {
+	return &OrderBuilder{
+		req: OrderRequest{ClientOrderID: clientOrderID},
+	}
+}
+
+// ProductID sets the ID of the product to trade.
+func (builder *OrderBuilder) ProductID(productID string) *OrderBuilder {
+	builder.req.ProductID = productID
+
+	return builder
+}
+
+// Product sets the product to trade. Build checks that the product can be
+// traded before returning the request, and returns ErrInvalidOrderConfig if the
+// product is nil.
+func (builder *OrderBuilder) Product(product *Product) *OrderBuilder {
+	if product == nil {
+		if builder.err == nil {
+			builder.err = fmt.Errorf("%w: nil product", ErrInvalidOrderConfig)
+		}
+
+		return builder
+	}
+
+	builder.product = product
+	builder.req.ProductID = product.ProductID
+
+	return builder
+}
+
+// Side sets the side of the order from a case-insensitive side string, such as
+// "buy" or "Sell".
+func (builder *OrderBuilder) Side(side string) *OrderBuilder {
+	orderSide, err := ParseOrderSide(side)
+	if err != nil && builder.err == nil {
+		builder.err = err
+	}
+
+	builder.req.Side = orderSide
+
+	return builder
+}
+
+// Buy makes the order a buy order.
+func (builder *OrderBuilder) Buy() *OrderBuilder {
+	builder.req.Side = OrderSideBuy
+
+	return builder
+}
+
+// Sell makes the order a sell order.
+func (builder *OrderBuilder) Sell() *OrderBuilder {
+	builder.req.Side = OrderSideSell
+
+	return builder
+}
+
+// MarketQuoteSize makes the order a market order for the given amount of the
+// quote currency.
+func (builder *OrderBuilder) MarketQuoteSize(quoteSize string) *OrderBuilder {
+	builder.req.Configuration = OrderConfig{
+		MarketIOC: &MarketIOCConfig{QuoteSize: quoteSize},
+	}
+
+	return builder
+}
+
+// MarketBaseSize makes the order a market order for the given amount of the
+// base currency.
+func (builder *OrderBuilder) MarketBaseSize(baseSize string) *OrderBuilder {
+	builder.req.Configuration = OrderConfig{
+		MarketIOC: &MarketIOCConfig{BaseSize: baseSize},
+	}
+
+	return builder
+}
+
+// LimitGTC makes the order a good-'til-cancelled limit order.
+func (builder *OrderBuilder) LimitGTC(baseSize, price string, postOnly bool) *OrderBuilder {
+	builder.req.Configuration = OrderConfig{
+		LimitGTC: &LimitGTCConfig{BaseSize: baseSize, Price: price, PostOnly: postOnly},
+	}
+
+	return builder
+}
+
+// LimitGTD makes the order a good-'til-date limit order that expires at the
+// given time.
+func (builder *OrderBuilder) LimitGTD(baseSize, price string, endTime time.Time, postOnly bool) *OrderBuilder {
+	builder.req.Configuration = OrderConfig{
+		LimitGTD: &LimitGTDConfig{BaseSize: baseSize, Price: price, EndTime: endTime, PostOnly: postOnly},
+	}
+
+	return builder
+}
+
+// Configuration sets the order configuration, for configurations that have no
+// dedicated step.
+func (builder *OrderBuilder) Configuration(config OrderConfig) *OrderBuilder {
+	builder.req.Configuration = config
+
+	return builder
+}
+
+// Attach attaches a one-cancels-other order with the given configuration.
+func (builder *OrderBuilder) Attach(config OrderConfig) *OrderBuilder {
+	builder.req.AttachedOrderConfiguration = &config
+
+	return builder
+}
+
+// Leverage sets the leverage of the order, such as "3". Build checks that it
+// is a positive number.
+func (builder *OrderBuilder) Leverage(leverage string) *OrderBuilder {
+	builder.req.Leverage = leverage
+
+	return builder
+}
+
+// Margin sets how the margin of a leveraged order is held.
+func (builder *OrderBuilder) Margin(marginType MarginType) *OrderBuilder {
+	builder.req.MarginType = marginType
+
+	return builder
+}
+
+// Build returns the order request. It returns the first error encountered
+// while building, ErrProductNotTradable if the order was given a product that
+// cannot be traded, or the error from validating the request.
+func (builder *OrderBuilder) Build() (OrderRequest, error) {
+	if builder.err != nil {
+		return OrderRequest{}, builder.err
+	}
+
+	if builder.product != nil {
+		if err := builder.product.CanTrade(); err != nil {
+			return OrderRequest{}, err
+		}
+	}
+
+	if err := builder.req.Validate(); err != nil {
+		return OrderRequest{}, err
+	}
+
+	return builder.req, nil
+}
+
+// NewClientOrderID returns a random version 4 UUID to use as the client order
+// ID of a new order.
+func NewClientOrderID() string {
+	return uuid.NewString()
+}
+
+// ToOrderRequest returns a request for a new order like the historical order,
+// for the same product and side and with the same configuration variant and so
+// time in force, but with a new client order ID. The new price replaces the
+// limit price of limit and stop-limit orders, and the new size replaces the
+// order's size. An empty price or size keeps the order's own. The stop price
+// of stop-limit orders is kept.
+func (order HistoricalOrder) ToOrderRequest(newPrice, newSize string) OrderRequest {
+	orderReq := OrderRequest{
+		ClientOrderID: NewClientOrderID(),
+		ProductID:     order.ProductID,
+		Side:          order.Side,
+		Configuration: order.OrderConfiguration.reprice(newPrice, newSize),
+	}
+
+	if order.AttachedOrderConfiguration != nil {
+		attached := order.AttachedOrderConfiguration.reprice("", "")
+		orderReq.AttachedOrderConfiguration = &attached
+	}
+
+	return orderReq
+}
+
+// reprice returns a copy of the configuration with the limit price and size
+// replaced by the given price and size, unless they are empty.
+func (config OrderConfig) reprice(price, size string) OrderConfig {
+	replace := func(value *string, with string) {
+		if with != "" {
+			*value = with
+		}
+	}
+
+	repriced := OrderConfig{}
+
+	if config.MarketIOC != nil {
+		market := *config.MarketIOC
+		if market.QuoteSize != "" {
+			replace(&market.QuoteSize, size)
+		} else {
+			replace(&market.BaseSize, size)
+		}
+
+		repriced.MarketIOC = &market
+	}
+
+	if config.LimitGTC != nil {
+		limit := *config.LimitGTC
+		replace(&limit.Price, price)
+		replace(&limit.BaseSize, size)
+		repriced.LimitGTC = &limit
+	}
+
+	if config.LimitGTD != nil {
+		limit := *config.LimitGTD
+		replace(&limit.Price, price)
+		replace(&limit.BaseSize, size)
+		repriced.LimitGTD = &limit
+	}
+
+	if config.StopLimitGTC != nil {
+		stopLimit := *config.StopLimitGTC
+		replace(&stopLimit.LimitPrice, price)
+		replace(&stopLimit.BaseSize, size)
+		repriced.StopLimitGTC = &stopLimit
+	}
+
+	if config.StopLimitGTD != nil {
+		stopLimit := *config.StopLimitGTD
+		replace(&stopLimit.LimitPrice, price)
+		replace(&stopLimit.BaseSize, size)
+		repriced.StopLimitGTD = &stopLimit
+	}
+
+	return repriced
+}
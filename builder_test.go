@@ -0,0 +1,293 @@
+package coinbase
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestOrderBuilder(t *testing.T) {
+	t.Parallel()
+
+	online := &Product{ProductID: "BTC-USD", Status: "online"}
+
+	tests := []struct {
+		name    string
+		builder *OrderBuilder
+		want    OrderRequest
+		err     error
+	}{
+		{
+			name:    "market buy",
+			builder: NewOrderBuilder("client-1").Product(online).Side("buy").MarketQuoteSize("10"),
+			want: OrderRequest{
+				ClientOrderID: "client-1",
+				ProductID:     "BTC-USD",
+				Side:          OrderSideBuy,
+				Configuration: OrderConfig{MarketIOC: &MarketIOCConfig{QuoteSize: "10"}},
+			},
+		},
+		{
+			name:    "limit sell without product",
+			builder: NewOrderBuilder("client-2").ProductID("ETH-USD").Sell().LimitGTC("1", "2000", true),
+			want: OrderRequest{
+				ClientOrderID: "client-2",
+				ProductID:     "ETH-USD",
+				Side:          OrderSideSell,
+				Configuration: OrderConfig{LimitGTC: &LimitGTCConfig{BaseSize: "1", Price: "2000", PostOnly: true}},
+			},
+		},
+		{
+			name:    "invalid side",
+			builder: NewOrderBuilder("client-3").Product(online).Side("hold").MarketQuoteSize("10"),
+			err:     ErrInvalidOrderSide,
+		},
+		{
+			name: "trading disabled",
+			builder: NewOrderBuilder("client-4").
+				Product(&Product{ProductID: "BTC-USD", Status: "online", TradingDisabled: true}).
+				Buy().MarketQuoteSize("10"),
+			err: ErrProductNotTradable,
+		},
+		{
+			name: "product offline",
+			builder: NewOrderBuilder("client-5").
+				Product(&Product{ProductID: "BTC-USD", Status: "delisted"}).
+				Buy().MarketQuoteSize("10"),
+			err: ErrProductNotTradable,
+		},
+		{
+			name:    "nil product",
+			builder: NewOrderBuilder("client-11").Product(nil).Buy().MarketQuoteSize("10"),
+			err:     ErrInvalidOrderConfig,
+		},
+		{
+			name:    "no configuration",
+			builder: NewOrderBuilder("client-6").Product(online).Buy(),
+			err:     ErrInvalidOrderConfig,
+		},
+		{
+			name: "leveraged",
+			builder: NewOrderBuilder("client-7").Product(online).Buy().MarketQuoteSize("10").
+				Leverage("3").Margin(MarginIsolated),
+			want: OrderRequest{
+				ClientOrderID: "client-7",
+				ProductID:     "BTC-USD",
+				Side:          OrderSideBuy,
+				Configuration: OrderConfig{MarketIOC: &MarketIOCConfig{QuoteSize: "10"}},
+				Leverage:      "3",
+				MarginType:    MarginIsolated,
+			},
+		},
+		{
+			name:    "non-numeric leverage",
+			builder: NewOrderBuilder("client-8").Product(online).Buy().MarketQuoteSize("10").Leverage("x3"),
+			err:     ErrInvalidOrderConfig,
+		},
+		{
+			name:    "zero leverage",
+			builder: NewOrderBuilder("client-9").Product(online).Buy().MarketQuoteSize("10").Leverage("0"),
+			err:     ErrInvalidOrderConfig,
+		},
+		{
+			name:    "unknown margin type",
+			builder: NewOrderBuilder("client-10").Product(online).Buy().MarketQuoteSize("10").Margin("PORTFOLIO"),
+			err:     ErrInvalidOrderConfig,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := test.builder.Build()
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestOrderBuilderLeverageJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		builder *OrderBuilder
+		want    string
+	}{
+		{
+			name:    "spot",
+			builder: NewOrderBuilder("client-1").ProductID("BTC-USD").Buy().MarketQuoteSize("10"),
+			want: `{"client_order_id":"client-1","product_id":"BTC-USD","side":"BUY",` +
+				`"order_configuration":{"market_market_ioc":{"quote_size":"10","base_size":""}}}`,
+		},
+		{
+			name: "leveraged",
+			builder: NewOrderBuilder("client-2").ProductID("BTC-USD").Buy().MarketQuoteSize("10").
+				Leverage("3").Margin(MarginIsolated),
+			want: `{"client_order_id":"client-2","product_id":"BTC-USD","side":"BUY",` +
+				`"order_configuration":{"market_market_ioc":{"quote_size":"10","base_size":""}},` +
+				`"leverage":"3","margin_type":"ISOLATED"}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := test.builder.Build()
+			if err != nil {
+				t.Fatalf("failed to build order: %v", err)
+			}
+
+			got, err := json.Marshal(req)
+			if err != nil {
+				t.Fatalf("failed to marshal order: %v", err)
+			}
+
+			if string(got) != test.want {
+				t.Fatalf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNewClientOrderID(t *testing.T) {
+	t.Parallel()
+
+	pattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	first, second := NewClientOrderID(), NewClientOrderID()
+	if !pattern.MatchString(first) {
+		t.Fatalf("got %q, want a version 4 UUID", first)
+	}
+
+	if first == second {
+		t.Fatalf("got the same client order ID twice: %q", first)
+	}
+}
+
+func TestHistoricalOrderToOrderRequest(t *testing.T) {
+	t.Parallel()
+
+	endTime := time.Date(2023, time.July, 28, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		order HistoricalOrder
+		price string
+		size  string
+		want  OrderConfig
+	}{
+		{
+			name: "limit GTC",
+			order: HistoricalOrder{
+				OrderConfiguration: OrderConfig{
+					LimitGTC: &LimitGTCConfig{BaseSize: "1", Price: "100", PostOnly: true},
+				},
+			},
+			price: "101",
+			size:  "2",
+			want: OrderConfig{
+				LimitGTC: &LimitGTCConfig{BaseSize: "2", Price: "101", PostOnly: true},
+			},
+		},
+		{
+			name: "limit GTD keeps size",
+			order: HistoricalOrder{
+				OrderConfiguration: OrderConfig{
+					LimitGTD: &LimitGTDConfig{BaseSize: "1", Price: "100", EndTime: endTime},
+				},
+			},
+			price: "99",
+			want: OrderConfig{
+				LimitGTD: &LimitGTDConfig{BaseSize: "1", Price: "99", EndTime: endTime},
+			},
+		},
+		{
+			name: "stop-limit GTC",
+			order: HistoricalOrder{
+				OrderConfiguration: OrderConfig{
+					StopLimitGTC: &StopLimitGTCConfig{
+						BaseSize: "1", LimitPrice: "90", StopPrice: "95", StopDirection: StopDirDown,
+					},
+				},
+			},
+			price: "89",
+			size:  "0.5",
+			want: OrderConfig{
+				StopLimitGTC: &StopLimitGTCConfig{
+					BaseSize: "0.5", LimitPrice: "89", StopPrice: "95", StopDirection: StopDirDown,
+				},
+			},
+		},
+		{
+			name: "stop-limit GTD",
+			order: HistoricalOrder{
+				OrderConfiguration: OrderConfig{
+					StopLimitGTD: &StopLimitGTDConfig{
+						BaseSize: "1", LimitPrice: "110", StopPrice: "105", EndTime: endTime,
+					},
+				},
+			},
+			price: "111",
+			want: OrderConfig{
+				StopLimitGTD: &StopLimitGTDConfig{
+					BaseSize: "1", LimitPrice: "111", StopPrice: "105", EndTime: endTime,
+				},
+			},
+		},
+		{
+			name: "market quote size",
+			order: HistoricalOrder{
+				OrderConfiguration: OrderConfig{MarketIOC: &MarketIOCConfig{QuoteSize: "10"}},
+			},
+			price: "100",
+			size:  "20",
+			want:  OrderConfig{MarketIOC: &MarketIOCConfig{QuoteSize: "20"}},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			test.order.ProductID = "BTC-USD"
+			test.order.Side = OrderSideSell
+			test.order.ClientOrderID = "original"
+
+			original := test.order.OrderConfiguration
+
+			got := test.order.ToOrderRequest(test.price, test.size)
+			if got.ProductID != "BTC-USD" || got.Side != OrderSideSell {
+				t.Fatalf("got product %q and side %q, want BTC-USD and SELL", got.ProductID, got.Side)
+			}
+
+			if got.ClientOrderID == "" || got.ClientOrderID == "original" {
+				t.Fatalf("got client order ID %q, want a new one", got.ClientOrderID)
+			}
+
+			if !reflect.DeepEqual(got.Configuration, test.want) {
+				t.Fatalf("got configuration %+v, want %+v", got.Configuration, test.want)
+			}
+
+			if !reflect.DeepEqual(test.order.OrderConfiguration, original) {
+				t.Fatalf("repricing changed the historical order's configuration")
+			}
+		})
+	}
+}
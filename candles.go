@@ -0,0 +1,301 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidGranularity is returned when a candle granularity is not one of the
+// supported Granularity constants, see SupportedGranularities.
+var ErrInvalidGranularity = errors.New("invalid granularity")
+
+// maxCandlesLimit is the maximum number of candles that the API returns for a
+// single request.
+const maxCandlesLimit = 300
+
+// Granularity represents the timeslice of each candle.
+type Granularity string
+
+const (
+	// GranularityUnknown represents an unknown granularity.
+	GranularityUnknown Granularity = "UNKNOWN_GRANULARITY"
+
+	// GranularityOneMinute represents one minute candles.
+	GranularityOneMinute Granularity = "ONE_MINUTE"
+
+	// GranularityFiveMinute represents five minute candles.
+	GranularityFiveMinute Granularity = "FIVE_MINUTE"
+
+	// GranularityFifteenMinute represents fifteen minute candles.
+	GranularityFifteenMinute Granularity = "FIFTEEN_MINUTE"
+
+	// GranularityThirtyMinute represents thirty minute candles.
+	GranularityThirtyMinute Granularity = "THIRTY_MINUTE"
+
+	// GranularityOneHour represents one hour candles.
+	GranularityOneHour Granularity = "ONE_HOUR"
+
+	// GranularityTwoHour represents two hour candles.
+	GranularityTwoHour Granularity = "TWO_HOUR"
+
+	// GranularitySixHour represents six hour candles.
+	GranularitySixHour Granularity = "SIX_HOUR"
+
+	// GranularityOneDay represents one day candles.
+	GranularityOneDay Granularity = "ONE_DAY"
+)
+
+// SupportedGranularities returns the granularities that candles can be
+// requested with, from the shortest timeslice to the longest.
+func SupportedGranularities() []Granularity {
+	return []Granularity{
+		GranularityOneMinute,
+		GranularityFiveMinute,
+		GranularityFifteenMinute,
+		GranularityThirtyMinute,
+		GranularityOneHour,
+		GranularityTwoHour,
+		GranularitySixHour,
+		GranularityOneDay,
+	}
+}
+
+// Duration returns the timeslice of a candle with the granularity, or zero if
+// the granularity is not supported.
+//
+//nolint:gomnd
+func (granularity Granularity) Duration() time.Duration {
+	switch granularity {
+	case GranularityOneMinute:
+		return time.Minute
+	case GranularityFiveMinute:
+		return 5 * time.Minute
+	case GranularityFifteenMinute:
+		return 15 * time.Minute
+	case GranularityThirtyMinute:
+		return 30 * time.Minute
+	case GranularityOneHour:
+		return time.Hour
+	case GranularityTwoHour:
+		return 2 * time.Hour
+	case GranularitySixHour:
+		return 6 * time.Hour
+	case GranularityOneDay:
+		return 24 * time.Hour
+	case GranularityUnknown:
+		return 0
+	}
+
+	return 0
+}
+
+// Candle represents the price movement of a product over a timeslice. Start is
+// the UNIX timestamp, in seconds, at which the timeslice begins.
+type Candle struct {
+	Start  string `json:"start"`
+	Low    string `json:"low"`
+	High   string `json:"high"`
+	Open   string `json:"open"`
+	Close  string `json:"close"`
+	Volume string `json:"volume"`
+}
+
+// startUnix returns the candle's start time as a UNIX timestamp.
+func (candle Candle) startUnix() (int64, error) {
+	formatBase, bitSize := 10, 64
+
+	unix, err := strconv.ParseInt(candle.Start, formatBase, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse candle start: %w", err)
+	}
+
+	return unix, nil
+}
+
+// Candles represents a collection of candles.
+type Candles struct {
+	Data []Candle `json:"candles"`
+}
+
+// CandlesParams are the query parameters used to select the candles returned
+// by GetProductCandles.
+type CandlesParams struct {
+	Start       time.Time
+	End         time.Time
+	Granularity Granularity
+
+	// SortDirection orders the candles by start time. It is not sent to
+	// the API, the candles are sorted client-side.
+	SortDirection SortDirection
+}
+
+// query returns the URL query values for the parameters.
+func (params CandlesParams) query() url.Values {
+	formatBase := 10
+
+	query := url.Values{}
+	query.Set("start", strconv.FormatInt(params.Start.Unix(), formatBase))
+	query.Set("end", strconv.FormatInt(params.End.Unix(), formatBase))
+	query.Set("granularity", string(params.Granularity))
+
+	return query
+}
+
+// GetProductCandles returns the candles for a product between the start and
+// end times, newest first unless the parameters set a sort direction. A window
+// covering more than 300 candles is handled according to the client's
+// LimitPolicy, with LimitPolicyClamp moving the end time back to cover exactly
+// 300 candles.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_getcandles
+func (client *Client) GetProductCandles(ctx context.Context, productID string, params CandlesParams,
+	opts ...CallOption,
+) (*Candles, error) {
+	dur := params.Granularity.Duration()
+	if dur == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidGranularity, params.Granularity)
+	}
+
+	if err := params.SortDirection.validate(); err != nil {
+		return nil, err
+	}
+
+	if count := params.End.Sub(params.Start) / dur; count > maxCandlesLimit {
+		if client.limitPolicy != LimitPolicyClamp {
+			return nil, fmt.Errorf("%w: %d candles is greater than the maximum of %d",
+				ErrLimitExceeded, count, maxCandlesLimit)
+		}
+
+		params.End = params.Start.Add(maxCandlesLimit * dur)
+	}
+
+	path := []string{"brokerage", "products", productID, "candles"}
+
+	candles := &Candles{}
+	if err := client.do(ctx, http.MethodGet, path, params.query(), nil, candles, opts); err != nil {
+		return nil, err
+	}
+
+	if err := sortCandles(candles.Data, params.SortDirection); err != nil {
+		return nil, err
+	}
+
+	return candles, nil
+}
+
+// sortCandles sorts the candles by start time in the direction, leaving them
+// as they are if the direction is empty.
+func sortCandles(candles []Candle, direction SortDirection) error {
+	if direction == "" {
+		return nil
+	}
+
+	for _, candle := range candles {
+		if _, err := candle.startUnix(); err != nil {
+			return err
+		}
+	}
+
+	sortByDirection(candles, direction, func(candle Candle) int64 {
+		// The start times have already been parsed successfully.
+		unix, _ := candle.startUnix()
+
+		return unix
+	})
+
+	return nil
+}
+
+// GetProductCandlesRange returns the candles for a product between the start
+// and end times, however many candles the window covers. The window is split
+// into chunks of at most 300 candles that are requested in turn, and the
+// results are returned in chronological order with any candles duplicated at
+// the chunk boundaries removed.
+func (client *Client) GetProductCandlesRange(ctx context.Context, productID string, start, end time.Time,
+	granularity Granularity, opts ...CallOption,
+) (*Candles, error) {
+	dur := granularity.Duration()
+	if dur == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidGranularity, granularity)
+	}
+
+	chunk := maxCandlesLimit * dur
+	seen := make(map[int64]bool)
+	candles := &Candles{}
+
+	for chunkStart := start; chunkStart.Before(end); chunkStart = chunkStart.Add(chunk) {
+		chunkEnd := chunkStart.Add(chunk)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+
+		params := CandlesParams{Start: chunkStart, End: chunkEnd, Granularity: granularity}
+
+		page, err := client.GetProductCandles(ctx, productID, params, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get candles from %v: %w", chunkStart, err)
+		}
+
+		for _, candle := range page.Data {
+			unix, err := candle.startUnix()
+			if err != nil {
+				return nil, err
+			}
+
+			if seen[unix] {
+				continue
+			}
+
+			seen[unix] = true
+			candles.Data = append(candles.Data, candle)
+		}
+	}
+
+	sort.Slice(candles.Data, func(i, j int) bool {
+		// The start times have already been parsed successfully.
+		iStart, _ := candles.Data[i].startUnix()
+		jStart, _ := candles.Data[j].startUnix()
+
+		return iStart < jStart
+	})
+
+	return candles, nil
+}
+
+// LatestCandles returns the latest n candles of a product, in chronological
+// order. The window requested runs from n timeslices of the granularity before
+// now up to now, and is requested in chunks of at most 300 candles as by
+// GetProductCandlesRange. Since the window may also cover the candle that is
+// still in progress, the result is trimmed to the latest n candles.
+func (client *Client) LatestCandles(ctx context.Context, productID string, granularity Granularity,
+	n int, opts ...CallOption,
+) (*Candles, error) {
+	dur := granularity.Duration()
+	if dur == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidGranularity, granularity)
+	}
+
+	if n <= 0 {
+		return &Candles{}, nil
+	}
+
+	end := time.Now()
+	start := end.Add(-time.Duration(n) * dur)
+
+	candles, err := client.GetProductCandlesRange(ctx, productID, start, end, granularity, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(candles.Data) > n {
+		candles.Data = candles.Data[len(candles.Data)-n:]
+	}
+
+	return candles, nil
+}
@@ -0,0 +1,320 @@
+package coinbase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestGetProductCandles(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response []byte
+		want     *Candles
+		err      error
+	}{
+		{
+			name: "nil",
+			err:  io.EOF, // end of file, nothing in response
+		},
+		{
+			name:     "empty slice",
+			response: []byte(`{}`),
+			want:     &Candles{},
+		},
+		{
+			name: "single",
+			response: []byte(`
+{
+  "candles": [{
+    "start": "1639508050",
+    "low": "140.21",
+    "high": "140.21",
+    "open": "140.21",
+    "close": "140.21",
+    "volume": "56437345"
+  }]
+}`),
+			want: &Candles{
+				Data: []Candle{
+					{
+						Start:  "1639508050",
+						Low:    "140.21",
+						High:   "140.21",
+						Open:   "140.21",
+						Close:  "140.21",
+						Volume: "56437345",
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{
+				httpClient: &mockClient{
+					response:   test.response,
+					statusCode: http.StatusOK,
+				},
+			}
+
+			params := CandlesParams{
+				Start:       time.Unix(1639508050, 0),
+				End:         time.Unix(1639508050, 0).Add(time.Hour),
+				Granularity: GranularityOneMinute,
+			}
+
+			got, err := client.GetProductCandles(context.Background(), "BTC-USD", params)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGranularityDuration(t *testing.T) {
+	t.Parallel()
+
+	want := map[Granularity]time.Duration{
+		GranularityOneMinute:     time.Minute,
+		GranularityFiveMinute:    5 * time.Minute,
+		GranularityFifteenMinute: 15 * time.Minute,
+		GranularityThirtyMinute:  30 * time.Minute,
+		GranularityOneHour:       time.Hour,
+		GranularityTwoHour:       2 * time.Hour,
+		GranularitySixHour:       6 * time.Hour,
+		GranularityOneDay:        24 * time.Hour,
+		GranularityUnknown:       0,
+		"ONE_WEEK":               0,
+	}
+
+	for granularity, duration := range want {
+		if got := granularity.Duration(); got != duration {
+			t.Errorf("got %v for %s, want %v", got, granularity, duration)
+		}
+	}
+
+	supported := SupportedGranularities()
+	if len(supported) != len(want)-2 {
+		t.Fatalf("got %d supported granularities, want %d", len(supported), len(want)-2)
+	}
+
+	for i, granularity := range supported {
+		if granularity.Duration() == 0 {
+			t.Fatalf("got unsupported granularity %s", granularity)
+		}
+
+		if i > 0 && granularity.Duration() <= supported[i-1].Duration() {
+			t.Fatalf("got %s after %s, want shortest first", granularity, supported[i-1])
+		}
+	}
+}
+
+func TestGetProductCandlesInvalid(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	start := time.Unix(1639508050, 0)
+	client := &Client{httpClient: &mockClient{response: []byte(`{}`), statusCode: http.StatusOK}}
+
+	params := CandlesParams{Start: start, End: start.Add(time.Hour), Granularity: "ONE_WEEK"}
+	if _, err := client.GetProductCandles(ctx, "BTC-USD", params); !errors.Is(err, ErrInvalidGranularity) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidGranularity)
+	}
+
+	params = CandlesParams{Start: start, End: start.Add(301 * time.Minute), Granularity: GranularityOneMinute}
+	if _, err := client.GetProductCandles(ctx, "BTC-USD", params); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("got %v, want %v", err, ErrLimitExceeded)
+	}
+}
+
+func TestGetProductCandlesSortDirection(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		direction SortDirection
+		want      []string
+		err       error
+	}{
+		{name: "unset", want: []string{"1639508100", "1639507980", "1639508040"}},
+		{name: "ascending", direction: SortAscending, want: []string{"1639507980", "1639508040", "1639508100"}},
+		{name: "descending", direction: SortDescending, want: []string{"1639508100", "1639508040", "1639507980"}},
+		{name: "invalid", direction: "SIDEWAYS", err: ErrInvalidSortDirection},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{httpClient: &mockClient{
+				response: []byte(`{"candles": [{"start": "1639508100"}, {"start": "1639507980"},
+					{"start": "1639508040"}]}`),
+				statusCode: http.StatusOK,
+			}}
+
+			start := time.Unix(1639507980, 0)
+			params := CandlesParams{
+				Start:         start,
+				End:           start.Add(3 * time.Minute),
+				Granularity:   GranularityOneMinute,
+				SortDirection: test.direction,
+			}
+
+			candles, err := client.GetProductCandles(context.Background(), "BTC-USD", params)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if test.err != nil {
+				return
+			}
+
+			var starts []string
+			for _, candle := range candles.Data {
+				starts = append(starts, candle.Start)
+			}
+
+			if !reflect.DeepEqual(starts, test.want) {
+				t.Fatalf("got starts %v, want %v", starts, test.want)
+			}
+		})
+	}
+}
+
+// mockCandles returns a mock HTTP client that responds with a one minute candle
+// for every minute between the requested start and end, inclusive, in
+// descending order as the API does. Each request's window is recorded.
+func mockCandles(t *testing.T, windows *[][2]int64) mockDoFunc {
+	t.Helper()
+
+	return func(req *http.Request) (*http.Response, error) {
+		start, _ := strconv.ParseInt(req.URL.Query().Get("start"), 10, 64)
+		end, _ := strconv.ParseInt(req.URL.Query().Get("end"), 10, 64)
+
+		*windows = append(*windows, [2]int64{start, end})
+
+		candles := &Candles{}
+		for unix := end; unix >= start; unix -= 60 {
+			candles.Data = append(candles.Data, Candle{Start: strconv.FormatInt(unix, 10)})
+		}
+
+		body, err := json.Marshal(candles)
+		if err != nil {
+			return nil, err
+		}
+
+		return newMockResponse(http.StatusOK, string(body)), nil
+	}
+}
+
+func TestGetProductCandlesRange(t *testing.T) {
+	t.Parallel()
+
+	var windows [][2]int64
+
+	client := &Client{httpClient: mockCandles(t, &windows)}
+
+	start := time.Unix(1639508040, 0)
+	end := start.Add(700 * time.Minute)
+
+	got, err := client.GetProductCandlesRange(context.Background(), "BTC-USD", start, end, GranularityOneMinute)
+	if err != nil {
+		t.Fatalf("failed to get candles: %v", err)
+	}
+
+	wantWindows := [][2]int64{
+		{start.Unix(), start.Add(300 * time.Minute).Unix()},
+		{start.Add(300 * time.Minute).Unix(), start.Add(600 * time.Minute).Unix()},
+		{start.Add(600 * time.Minute).Unix(), end.Unix()},
+	}
+
+	if !reflect.DeepEqual(windows, wantWindows) {
+		t.Fatalf("got windows %v, want %v", windows, wantWindows)
+	}
+
+	if len(got.Data) != 701 {
+		t.Fatalf("got %d candles, want %d", len(got.Data), 701)
+	}
+
+	for i, candle := range got.Data {
+		want := strconv.FormatInt(start.Add(time.Duration(i)*time.Minute).Unix(), 10)
+		if candle.Start != want {
+			t.Fatalf("got candle %d start %s, want %s", i, candle.Start, want)
+		}
+	}
+}
+
+func TestLatestCandles(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		n           int
+		wantWindows []time.Duration // the length of each window requested
+	}{
+		{name: "single window", n: 50, wantWindows: []time.Duration{50 * time.Minute}},
+		{name: "paged", n: 350, wantWindows: []time.Duration{300 * time.Minute, 50 * time.Minute}},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var windows [][2]int64
+
+			client := &Client{httpClient: mockCandles(t, &windows)}
+
+			before := time.Now().Unix()
+
+			got, err := client.LatestCandles(context.Background(), "BTC-USD", GranularityOneMinute, test.n)
+			if err != nil {
+				t.Fatalf("failed to get candles: %v", err)
+			}
+
+			if len(windows) != len(test.wantWindows) {
+				t.Fatalf("got windows %v, want %d", windows, len(test.wantWindows))
+			}
+
+			for i, window := range windows {
+				if got := time.Duration(window[1]-window[0]) * time.Second; got != test.wantWindows[i] {
+					t.Fatalf("got window %d of %v, want %v", i, got, test.wantWindows[i])
+				}
+			}
+
+			end := windows[len(windows)-1][1]
+			if end < before || end > time.Now().Unix() {
+				t.Fatalf("got window ending at %d, want now", end)
+			}
+
+			if len(got.Data) != test.n {
+				t.Fatalf("got %d candles, want %d", len(got.Data), test.n)
+			}
+
+			if last := got.Data[len(got.Data)-1].Start; last != strconv.FormatInt(end, 10) {
+				t.Fatalf("got latest candle start %s, want %d", last, end)
+			}
+		})
+	}
+}
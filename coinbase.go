@@ -11,52 +11,499 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 const api = "https://api.coinbase.com/api/v3"
 
-// ErrStatusNotOK is returned when the Coinbase API returns a non-OK status
-// code.
+// apiV2 is the base URL of the Coinbase v2 API, which serves data that the
+// Advanced Trade API does not, such as exchange rates.
+const apiV2 = "https://api.coinbase.com/v2"
+
+// ErrStatusNotOK is returned when the Coinbase API returns a status code
+// outside of the 2xx range.
 var ErrStatusNotOK = errors.New("status not OK")
 
+// ErrAccountNotFound is returned when an account matching the requested
+// criteria does not exist.
+var ErrAccountNotFound = errors.New("account not found")
+
+// ErrAccountCreationUnsupported is returned by EnsureAccount when the user has
+// no account in a currency, since the Advanced Trade API cannot create
+// accounts. It wraps ErrAccountNotFound.
+var ErrAccountCreationUnsupported = fmt.Errorf("%w: accounts cannot be created through the API", ErrAccountNotFound)
+
+// ErrOrderNotFound is returned when no order matches the requested order ID or
+// client order ID.
+var ErrOrderNotFound = errors.New("order not found")
+
+// ErrAmbiguousClientOrderID is returned when more than one order matches the
+// requested client order ID.
+var ErrAmbiguousClientOrderID = errors.New("client order ID matches more than one order")
+
+// ErrMissingPreviewID is returned when an order is created from a preview
+// without a preview ID.
+var ErrMissingPreviewID = errors.New("missing preview ID")
+
+// ErrInvalidOrderConfig is returned when an order configuration does not have
+// exactly one variant set.
+var ErrInvalidOrderConfig = errors.New("invalid order configuration")
+
+// ErrMissingPrice is returned by OrderConfig.Notional for a market order sized
+// in the base currency, whose notional value depends on the price it fills at.
+var ErrMissingPrice = errors.New("order configuration has no price")
+
+// ErrInvalidClientOrderID is returned when a client order ID is longer than
+// maxClientOrderIDLength or has characters other than ASCII letters, digits,
+// hyphens and underscores, and when an order is looked up by an empty client
+// order ID.
+var ErrInvalidClientOrderID = errors.New("invalid client order ID")
+
+// maxClientOrderIDLength is the longest client order ID accepted by Coinbase.
+const maxClientOrderIDLength = 128
+
+// ErrInvalidOrderSide is returned when a string cannot be parsed as an order
+// side.
+var ErrInvalidOrderSide = errors.New("invalid order side")
+
+// ErrPostOnlyWouldCross is returned by CreateOrder and PreviewOrder when a
+// post-only order is rejected because it would cross the book and so take
+// liquidity, in which case it can be placed again at a less aggressive price.
+var ErrPostOnlyWouldCross = errors.New("post-only order would cross")
+
+// ErrUnauthorized is returned when the Coinbase API responds with a 401
+// status code, which almost always means that the API key and secret are
+// invalid or that the local clock is out of sync with Coinbase's. It wraps
+// ErrStatusNotOK.
+var ErrUnauthorized = fmt.Errorf("%w: unauthorized", ErrStatusNotOK)
+
+// ErrServiceUnavailable is returned when the Coinbase API responds with a 503
+// status code, which it does during scheduled maintenance. It wraps
+// ErrStatusNotOK. Requests that fail with it are retried with a longer
+// backoff, see WithMaintenanceRetryWait.
+var ErrServiceUnavailable = fmt.Errorf("%w: service unavailable", ErrStatusNotOK)
+
+// ErrRateLimited is returned when the Coinbase API responds with a 429 status
+// code because a rate limit was exceeded. It wraps ErrStatusNotOK. The
+// StatusError that carries it has the decoded body, which names the limit, and
+// the wait asked for by the Retry-After header.
+var ErrRateLimited = fmt.Errorf("%w: rate limited", ErrStatusNotOK)
+
+// clockSkewHint is appended to unauthorized errors that mention the request
+// timestamp.
+const clockSkewHint = "hint: the request timestamp was rejected, check that the system clock is in sync"
+
 // Client is a Coinbase API client.
 type Client struct {
 	httpClient interface {
 		Do(*http.Request) (*http.Response, error)
 	}
+
+	// timeout is the default timeout for each request, zero means no
+	// timeout.
+	timeout time.Duration
+
+	// limitPolicy determines how list methods handle limits greater than
+	// the endpoint's maximum.
+	limitPolicy LimitPolicy
+
+	// defaultPageSize is the limit requested by list methods when a call
+	// sets none, zero meaning the endpoint's default.
+	defaultPageSize int32
+
+	// maxRetries is the number of times a failed request is retried, and
+	// retryWait the wait before the first retry, zero meaning the default.
+	maxRetries int
+	retryWait  time.Duration
+
+	// maintenanceRetryWait is the wait before the first retry of a
+	// request that failed with ErrServiceUnavailable, zero meaning the
+	// default.
+	maintenanceRetryWait time.Duration
+
+	// retryBudget limits the retries made across all requests, nil means
+	// no limit.
+	retryBudget *retryBudget
+
+	// withoutAutoSign is set when requests are sent without the
+	// "cb-access-*" authentication headers.
+	withoutAutoSign bool
+
+	// strictDecoding is set when response fields that are not modeled are
+	// an error.
+	strictDecoding bool
+
+	// maxResponseBytes is the largest response body that is read, zero
+	// meaning no limit.
+	maxResponseBytes int64
+
+	// transport sends the requests made by a client created with
+	// NewClient, nil meaning the default HTTP transport.
+	transport http.RoundTripper
+
+	// proxy is the URL of the HTTP proxy that requests are sent through,
+	// nil meaning the proxy set by the environment.
+	proxy *url.URL
+
+	// signingPath transforms the path that requests are signed over, nil
+	// meaning the path they are sent to.
+	signingPath func(string) string
+
+	// inspectRequest is called with the message signed for each request,
+	// if it is non-nil.
+	inspectRequest func(method, path, signedMessage string)
+
+	// timestampResolution is the resolution of the timestamp that
+	// requests are signed with.
+	timestampResolution TimestampResolution
+
+	// unsignedQuery is set when the query string is left out of the path
+	// that requests are signed over.
+	unsignedQuery bool
+
+	// credentials provides the key and secret that requests are signed
+	// with, nil meaning those given to NewClient.
+	credentials *credentialsCache
+
+	// feeTierTTL is how long the fee tier is cached for, zero meaning
+	// defaultFeeTierTTL, and feeTier is the cached tier.
+	feeTierTTL time.Duration
+	feeTier    feeTierCache
+
+	// clockSkew is the skew between the local clock and the
+	// server's, as measured by Fresh.
+	clockSkew clockSkewCache
+
+	// stats records the requests made by the client, nil meaning they
+	// are not recorded.
+	stats *statsRecorder
+
+	// permissions holds the permissions of the client's API key, nil
+	// meaning they are not checked before trading.
+	permissions *permissionsCache
+
+	// orderVisibilityWindow is how long after creating an order GetOrder
+	// waits for it to become visible, zero meaning the default, and
+	// createdOrders holds the orders created within it.
+	orderVisibilityWindow time.Duration
+	createdOrders         createdOrders
 }
 
 // NewClient creates a new Coinbase API client with the provided API key and
 // secret. The Coinbase API requests are automatically signed with the provided
-// API key and secret using an http Transport middleware.
-func NewClient(key, secret string) (*Client, error) {
-	httpClient := http.DefaultClient
+// API key and secret using an http Transport middleware, unless the client is
+// created WithoutAutoSign or WithCredentialProvider.
+func NewClient(key, secret string, opts ...ClientOption) (*Client, error) {
+	client := &Client{}
 
-	var err error
+	for _, opt := range opts {
+		opt(client)
+	}
 
-	httpClient.Transport, err = newRoundTripper(key, secret)
+	if client.withoutAutoSign {
+		client.httpClient = &http.Client{Transport: client.baseTransport()}
+
+		return client, nil
+	}
+
+	if client.credentials != nil {
+		transport := newProviderRoundTripper(client.credentials, client.signConfig())
+		client.httpClient = &http.Client{Transport: transport}
+
+		return client, nil
+	}
+
+	transport, err := newRoundTripper(key, secret, client.signConfig())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create client: %w", err)
 	}
 
-	client := &Client{
-		httpClient: http.DefaultClient,
-	}
+	client.httpClient = &http.Client{Transport: transport}
 
 	return client, nil
 }
 
-// AvailableMoney represents an amount of money that is available.
-type AvailableMoney struct {
-	Value    string `json:"value"`
-	Currency string `json:"currency"`
+// signConfig returns the configuration for signing the client's requests.
+func (client *Client) signConfig() signConfig {
+	return signConfig{
+		transport:   client.baseTransport(),
+		signingPath: client.signingPath,
+		inspect:     client.inspectRequest,
+		resolution:  client.timestampResolution,
+		omitQuery:   client.unsignedQuery,
+	}
+}
+
+// emptyIfNil returns the slice, or an empty slice if it is nil, so that list
+// results can be used without checking for nil.
+func emptyIfNil[T any](data []T) []T {
+	if data == nil {
+		return []T{}
+	}
+
+	return data
+}
+
+// Do sends a signed request to an endpoint of the Coinbase Advanced Trade API
+// that this package does not wrap yet, with the same error handling, retries
+// and options as the wrapped endpoints. The path is relative to the API's base
+// URL, such as "brokerage/orders". If "body" is non-nil it is encoded as the
+// JSON request body, and the JSON response body is decoded into "out" unless
+// it is nil, in which case the response body is discarded.
+func (client *Client) Do(ctx context.Context, method, path string, query url.Values, body, out any,
+	opts ...CallOption,
+) error {
+	return client.do(ctx, method, []string{strings.TrimPrefix(path, "/")}, query, body, out, opts)
+}
+
+// do sends a request to the Coinbase Advanced Trade API at the given path. If
+// "body" is non-nil it is encoded as the JSON request body, and the JSON
+// response body is decoded into "out".
+func (client *Client) do(ctx context.Context, method string, path []string, query url.Values, body, out any,
+	opts []CallOption,
+) error {
+	return client.doWithBase(ctx, api, method, path, query, body, out, opts)
+}
+
+// doWithBase is like do, but sends the request to the given base URL.
+func (client *Client) doWithBase(ctx context.Context, base, method string, path []string, query url.Values,
+	body, out any, opts []CallOption,
+) error {
+	ctx, cancel := client.newCallOptions(opts).withTimeout(ctx)
+	defer cancel()
+
+	full, err := url.JoinPath(base, path...)
+	if err != nil {
+		return fmt.Errorf("failed to join path: %w", err)
+	}
+
+	if len(query) > 0 {
+		full = fmt.Sprintf("%s?%s", full, query.Encode())
+	}
+
+	var data []byte
+
+	if body != nil {
+		if data, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+	}
+
+	endpoint := method + " /" + strings.Join(path, "/")
+
+	return client.retry(ctx, func() (bool, error) {
+		start := time.Now()
+		retryable, err := client.send(ctx, method, full, data, out)
+		client.stats.record(endpoint, time.Since(start), err != nil)
+
+		return retryable, err
+	})
+}
+
+// send makes a single attempt at a request, decoding the JSON response body
+// into "out". It reports whether a failed request may be retried.
+func (client *Client) send(ctx context.Context, method, full string, data []byte, out any) (bool, error) {
+	var reqBody io.Reader
+
+	if data != nil {
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, full, reqBody)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Header should be application/json.
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			panic(err)
+		}
+	}()
+
+	var respBody io.Reader = resp.Body
+	if client.maxResponseBytes > 0 {
+		respBody = &maxBytesReader{reader: resp.Body, remaining: client.maxResponseBytes, limit: client.maxResponseBytes}
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, err := io.ReadAll(respBody)
+		if errors.Is(err, ErrResponseTooLarge) {
+			return false, fmt.Errorf("failed to read response with status code %d: %w", resp.StatusCode, err)
+		}
+
+		return isRetryableStatus(resp.StatusCode), newStatusError(resp.StatusCode, resp.Header, body)
+	}
+
+	// A response without content leaves "out" as it is, and a nil "out"
+	// discards the content.
+	if resp.StatusCode == http.StatusNoContent || out == nil {
+		return false, nil
+	}
+
+	decoder := json.NewDecoder(respBody)
+	if client.strictDecoding {
+		decoder.DisallowUnknownFields()
+	}
+
+	if err := decoder.Decode(out); err != nil {
+		return false, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if paged, ok := out.(headerPaginated); ok {
+		if cursor := headerCursor(resp.Header); cursor != "" {
+			paged.setHeaderCursor(cursor)
+		}
+	}
+
+	return false, nil
+}
+
+// StatusError is returned when the Coinbase API responds with a status code
+// outside of the 2xx range. It wraps ErrStatusNotOK, or ErrUnauthorized for a
+// 401 status code, ErrRateLimited for a 429 one and ErrServiceUnavailable for a
+// 503 one.
+type StatusError struct {
+	StatusCode int
+	Body       []byte
+
+	// Response is the decoded JSON error body, or nil if the body is not a
+	// JSON object. For a 429 status code its error and details say which
+	// rate limit was exceeded.
+	Response *ErrorResponse
+
+	// RetryAfter is how long the Retry-After header of the response asks
+	// the client to wait before trying again, zero if it has none.
+	RetryAfter time.Duration
+
+	err error
+}
+
+// Error returns the error message, which includes the status code and body.
+func (err *StatusError) Error() string {
+	return err.err.Error()
+}
+
+// Unwrap returns the sentinel error for the status code.
+func (err *StatusError) Unwrap() error {
+	return err.err
+}
+
+// Is reports whether the error matches ErrPostOnlyWouldCross, when the error
+// body rejects a post-only order.
+func (err *StatusError) Is(target error) bool {
+	return target == ErrPostOnlyWouldCross && err.Response != nil && err.Response.isPostOnlyRejection()
+}
+
+// newStatusError returns the error for a response with a non-2xx status code.
+func newStatusError(statusCode int, header http.Header, body []byte) error {
+	statusErr := &StatusError{
+		StatusCode: statusCode,
+		Body:       body,
+		RetryAfter: parseRetryAfter(header.Get("Retry-After"), time.Now()),
+	}
+
+	errResp := &ErrorResponse{}
+	if json.Unmarshal(body, errResp) == nil {
+		statusErr.Response = errResp
+	}
+
+	switch {
+	case statusCode == http.StatusUnauthorized && bytes.Contains(bytes.ToLower(body), []byte("timestamp")):
+		statusErr.err = fmt.Errorf("%w: body: %s, %s", ErrUnauthorized, body, clockSkewHint)
+	case statusCode == http.StatusUnauthorized:
+		statusErr.err = fmt.Errorf("%w: body: %s", ErrUnauthorized, body)
+	case statusCode == http.StatusTooManyRequests:
+		statusErr.err = fmt.Errorf("%w: body: %s", ErrRateLimited, body)
+	case statusCode == http.StatusServiceUnavailable:
+		statusErr.err = fmt.Errorf("%w: body: %s", ErrServiceUnavailable, body)
+	default:
+		statusErr.err = fmt.Errorf("%w: unexpected status code: %d, body: %s",
+			ErrStatusNotOK, statusCode, body)
+	}
+
+	return statusErr
+}
+
+// parseRetryAfter returns the wait asked for by a Retry-After header value,
+// which is either a number of seconds or an HTTP date. A missing, malformed or
+// past value is zero.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	var wait time.Duration
+
+	formatBase, bitSize := 10, 64
+	if seconds, err := strconv.ParseInt(value, formatBase, bitSize); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		wait = date.Sub(now)
+	}
+
+	if wait < 0 {
+		return 0
+	}
+
+	return wait
 }
 
+// AvailableMoney represents an amount of money that is available.
+type AvailableMoney = Money
+
 // HoldMoney represents an amount of money that is being held.
-type HoldMoney struct {
-	Value    string `json:"value"`
-	Currency string `json:"currency"`
+type HoldMoney = Money
+
+// AccountType represents the type of an account.
+type AccountType string
+
+const (
+	// AccountTypeUnspecified represents an account of an unspecified or
+	// unknown type.
+	AccountTypeUnspecified AccountType = "ACCOUNT_TYPE_UNSPECIFIED"
+
+	// AccountTypeCrypto represents a cryptocurrency account.
+	AccountTypeCrypto AccountType = "ACCOUNT_TYPE_CRYPTO"
+
+	// AccountTypeFiat represents a fiat currency account.
+	AccountTypeFiat AccountType = "ACCOUNT_TYPE_FIAT"
+
+	// AccountTypeVault represents a vault account.
+	AccountTypeVault AccountType = "ACCOUNT_TYPE_VAULT"
+)
+
+// UnmarshalJSON decodes the account type, falling back to
+// AccountTypeUnspecified for types that this package does not know about so
+// that new account types do not break decoding.
+func (accountType *AccountType) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to decode account type: %w", err)
+	}
+
+	switch parsed := AccountType(raw); parsed {
+	case AccountTypeUnspecified, AccountTypeCrypto, AccountTypeFiat, AccountTypeVault:
+		*accountType = parsed
+	default:
+		*accountType = AccountTypeUnspecified
+	}
+
+	return nil
 }
 
 // Account represents a user account with the available balance and hold amount
@@ -71,9 +518,18 @@ type Account struct {
 	CreatedAt        time.Time      `json:"created_at"`
 	UpdatedAt        time.Time      `json:"updated_at"`
 	DeletedAt        *time.Time     `json:"deleted_at,omitempty"`
-	Type             string         `json:"type"`
+	Type             AccountType    `json:"type"`
 	Ready            bool           `json:"ready"`
-	Hold             HoldMoney      `json:"hold"`
+
+	// Hold is the amount of money held for open orders, which carries its
+	// own currency since it may differ from that of the available
+	// balance. It is the zero Money, with an empty value and currency,
+	// when the response has no hold.
+	Hold HoldMoney `json:"hold"`
+
+	// RetailPortfolioID is the ID of the portfolio that the account
+	// belongs to.
+	RetailPortfolioID string `json:"retail_portfolio_id"`
 }
 
 // Accounts represents a collection of accounts along with metadata.
@@ -84,44 +540,116 @@ type Accounts struct {
 	Size    int32     `json:"size"`
 }
 
+// ListAccountsParams are the query parameters used to page through the
+// accounts returned by ListAccounts. Zero values are omitted from the request.
+type ListAccountsParams struct {
+	Limit  int32
+	Cursor string
+}
+
+// query returns the URL query values for the parameters.
+func (params ListAccountsParams) query() url.Values {
+	query := url.Values{}
+
+	if params.Limit > 0 {
+		formatBase := 10
+		query.Set("limit", strconv.FormatInt(int64(params.Limit), formatBase))
+	}
+
+	if params.Cursor != "" {
+		query.Set("cursor", params.Cursor)
+	}
+
+	return query
+}
+
 // Accounts returns a slice of accounts for the authenticated user.
 //
 // https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_getaccounts
-func (client *Client) Accounts(ctx context.Context) (*Accounts, error) {
-	full, err := url.JoinPath(api, "brokerage", "accounts")
-	if err != nil {
-		return nil, fmt.Errorf("failed to join path: %w", err)
+func (client *Client) Accounts(ctx context.Context, opts ...CallOption) (*Accounts, error) {
+	return client.ListAccounts(ctx, ListAccountsParams{}, opts...)
+}
+
+// ListAccounts returns a page of accounts for the authenticated user. A limit
+// greater than the endpoint's maximum is handled according to the client's
+// LimitPolicy. The Data of an empty page is an empty slice, never nil.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_getaccounts
+func (client *Client) ListAccounts(ctx context.Context, params ListAccountsParams,
+	opts ...CallOption,
+) (*Accounts, error) {
+	var err error
+	if params.Limit, err = client.checkLimit(params.Limit, maxAccountsLimit); err != nil {
+		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, full, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	path := []string{"brokerage", "accounts"}
+
+	accounts := &Accounts{}
+	if err := client.do(ctx, http.MethodGet, path, params.query(), nil, accounts, opts); err != nil {
+		return nil, err
 	}
 
-	resp, err := client.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+	accounts.Data = emptyIfNil(accounts.Data)
+
+	return accounts, nil
+}
+
+// PrimaryFiatAccount returns the default account for the given currency, such
+// as "USD", scanning every account of the authenticated user. ErrAccountNotFound
+// is returned if there is no default account for the currency.
+func (client *Client) PrimaryFiatAccount(ctx context.Context, currency string, opts ...CallOption) (*Account, error) {
+	pager := client.AccountsPager(ctx, opts...)
+
+	for pager.Next() {
+		account := pager.Account()
+		if account.Default && strings.EqualFold(account.Currency, currency) {
+			return &account, nil
+		}
 	}
 
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			panic(err)
+	if err := pager.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	return nil, fmt.Errorf("%w: no default %s account", ErrAccountNotFound, currency)
+}
+
+// EnsureAccount returns the user's account for the given currency, such as
+// "ETH", preferring the default account if there are several and scanning
+// every account of the authenticated user. The Advanced Trade API has no
+// endpoint to create accounts, so if there is none for the currency
+// ErrAccountCreationUnsupported is returned, and the account has to be
+// created in the Coinbase app, such as by receiving or buying the currency.
+func (client *Client) EnsureAccount(ctx context.Context, currency string, opts ...CallOption) (*Account, error) {
+	var found *Account
+
+	pager := client.AccountsPager(ctx, opts...)
+
+	for pager.Next() {
+		account := pager.Account()
+		if !strings.EqualFold(account.Currency, currency) {
+			continue
 		}
-	}()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
+		if account.Default {
+			return &account, nil
+		}
 
-		return nil, fmt.Errorf("%w: unexpected status code: %d, body: %s",
-			ErrStatusNotOK, resp.StatusCode, body)
+		if found == nil {
+			found = &account
+		}
 	}
 
-	accounts := &Accounts{}
-	if err := json.NewDecoder(resp.Body).Decode(accounts); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := pager.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
 	}
 
-	return accounts, nil
+	if found == nil {
+		return nil, fmt.Errorf("%w: no %s account", ErrAccountCreationUnsupported, currency)
+	}
+
+	return found, nil
 }
 
 // MarketIOCConfig represents the configuration of a market or
@@ -191,6 +719,91 @@ type OrderConfig struct {
 	StopLimitGTD *StopLimitGTDConfig `json:"stop_limit_stop_limit_gtd,omitempty"`
 }
 
+// Validate returns ErrInvalidOrderConfig unless exactly one order
+// configuration variant is set, since an order with no variant or with several
+// variants is ambiguous.
+func (config OrderConfig) Validate() error {
+	variants := 0
+
+	for _, set := range []bool{
+		config.MarketIOC != nil,
+		config.LimitGTC != nil,
+		config.LimitGTD != nil,
+		config.StopLimitGTC != nil,
+		config.StopLimitGTD != nil,
+	} {
+		if set {
+			variants++
+		}
+	}
+
+	switch variants {
+	case 0:
+		return fmt.Errorf("%w: no variant is set", ErrInvalidOrderConfig)
+	case 1:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %d variants are set, want exactly one", ErrInvalidOrderConfig, variants)
+}
+
+// PriceSize returns the limit price and size of the configuration, whichever
+// variant is set. The size is the base size, except for market orders sized
+// in the quote currency, whose size is their quote size. Market orders have no
+// price, and a configuration without a variant has neither.
+func (config OrderConfig) PriceSize() (string, string) {
+	switch {
+	case config.MarketIOC != nil && config.MarketIOC.QuoteSize != "":
+		return "", config.MarketIOC.QuoteSize
+	case config.MarketIOC != nil:
+		return "", config.MarketIOC.BaseSize
+	case config.LimitGTC != nil:
+		return config.LimitGTC.Price, config.LimitGTC.BaseSize
+	case config.LimitGTD != nil:
+		return config.LimitGTD.Price, config.LimitGTD.BaseSize
+	case config.StopLimitGTC != nil:
+		return config.StopLimitGTC.LimitPrice, config.StopLimitGTC.BaseSize
+	case config.StopLimitGTD != nil:
+		return config.StopLimitGTD.LimitPrice, config.StopLimitGTD.BaseSize
+	}
+
+	return "", ""
+}
+
+// Notional returns the value of the configuration in the quote currency: the
+// limit price times the base size for limit and stop-limit orders, and the
+// quote size of market orders sized in the quote currency, such as market
+// buys. A market order sized in the base currency, such as a market sell, has
+// no price to value it at, so ErrMissingPrice is returned for it, and
+// ErrInvalidOrderConfig is returned unless exactly one variant is set.
+func (config OrderConfig) Notional() (decimal.Decimal, error) {
+	if err := config.Validate(); err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	price, size := config.PriceSize()
+
+	sizeAmount, err := decimal.NewFromString(size)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to parse size %q: %w", size, err)
+	}
+
+	if config.MarketIOC != nil && config.MarketIOC.QuoteSize != "" {
+		return sizeAmount, nil
+	}
+
+	if price == "" {
+		return decimal.Decimal{}, fmt.Errorf("%w: market order of base size %q", ErrMissingPrice, size)
+	}
+
+	priceAmount, err := decimal.NewFromString(price)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to parse price %q: %w", price, err)
+	}
+
+	return priceAmount.Mul(sizeAmount), nil
+}
+
 // OrderSide represents the side of an order, either BUY or SELL.
 type OrderSide string
 
@@ -205,12 +818,121 @@ const (
 	OrderSideSell OrderSide = "SELL"
 )
 
+// MarginType represents how the margin of a leveraged order is held.
+type MarginType string
+
+const (
+	// MarginCross represents margin shared across the positions of the
+	// portfolio.
+	MarginCross MarginType = "CROSS"
+
+	// MarginIsolated represents margin held separately for the position of
+	// the order.
+	MarginIsolated MarginType = "ISOLATED"
+)
+
+// ParseOrderSide returns the order side for a case-insensitive side string,
+// such as "buy" or "Sell". ErrInvalidOrderSide is returned if the string is
+// neither a buy nor a sell side.
+func ParseOrderSide(side string) (OrderSide, error) {
+	switch OrderSide(strings.ToUpper(strings.TrimSpace(side))) {
+	case OrderSideBuy:
+		return OrderSideBuy, nil
+	case OrderSideSell:
+		return OrderSideSell, nil
+	case OrderSideUnknown:
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrInvalidOrderSide, side)
+}
+
 // OrderRequest can be used to create an order on Coinbase.
 type OrderRequest struct {
 	ClientOrderID string      `json:"client_order_id" validate:"required"`
 	ProductID     string      `json:"product_id" validate:"required"`
 	Side          OrderSide   `json:"side"`
 	Configuration OrderConfig `json:"order_configuration"`
+
+	// AttachedOrderConfiguration is the configuration of a secondary order
+	// that is attached to the primary order, such that one cancels the
+	// other (OCO). For example, a stop-limit order attached to a limit
+	// entry order.
+	AttachedOrderConfiguration *OrderConfig `json:"attached_order_configuration,omitempty"`
+
+	// Leverage is the leverage of the order, such as "3", and MarginType
+	// how its margin is held. Both are only sent when set, so spot orders
+	// leave them empty.
+	Leverage   string     `json:"leverage,omitempty"`
+	MarginType MarginType `json:"margin_type,omitempty"`
+}
+
+// Validate checks the order request before it is sent, returning
+// ErrInvalidOrderConfig if its order configuration, or its attached order
+// configuration when set, does not have exactly one variant set, or if a market
+// order is missing the size its side requires: the quote size for a buy and
+// the base size for a sell. ErrInvalidClientOrderID is returned if the client
+// order ID is set but is not in the format Coinbase accepts, see
+// validateClientOrderID. ErrInvalidOrderConfig is also returned if the leverage
+// is set but is not a positive number, or if the margin type is set but is not
+// one of the MarginType constants.
+func (orderReq OrderRequest) Validate() error {
+	if err := orderReq.Configuration.Validate(); err != nil {
+		return err
+	}
+
+	if err := validateClientOrderID(orderReq.ClientOrderID); err != nil {
+		return err
+	}
+
+	if market := orderReq.Configuration.MarketIOC; market != nil {
+		switch {
+		case orderReq.Side == OrderSideBuy && market.QuoteSize == "":
+			return fmt.Errorf("%w: market BUY requires quote_size", ErrInvalidOrderConfig)
+		case orderReq.Side == OrderSideSell && market.BaseSize == "":
+			return fmt.Errorf("%w: market SELL requires base_size", ErrInvalidOrderConfig)
+		}
+	}
+
+	if orderReq.AttachedOrderConfiguration != nil {
+		if err := orderReq.AttachedOrderConfiguration.Validate(); err != nil {
+			return fmt.Errorf("attached order: %w", err)
+		}
+	}
+
+	if orderReq.Leverage != "" {
+		leverage, err := decimal.NewFromString(orderReq.Leverage)
+		if err != nil || !leverage.IsPositive() {
+			return fmt.Errorf("%w: leverage %q is not a positive number", ErrInvalidOrderConfig, orderReq.Leverage)
+		}
+	}
+
+	switch orderReq.MarginType {
+	case "", MarginCross, MarginIsolated:
+	default:
+		return fmt.Errorf("%w: unknown margin type %q", ErrInvalidOrderConfig, orderReq.MarginType)
+	}
+
+	return nil
+}
+
+// validateClientOrderID checks that a non-empty client order ID is at most 128
+// characters of ASCII letters, digits, hyphens and underscores, which covers
+// UUIDs such as those from NewClientOrderID.
+func validateClientOrderID(clientOrderID string) error {
+	if len(clientOrderID) > maxClientOrderIDLength {
+		return fmt.Errorf("%w: %d characters is more than the maximum of %d",
+			ErrInvalidClientOrderID, len(clientOrderID), maxClientOrderIDLength)
+	}
+
+	for _, char := range clientOrderID {
+		switch {
+		case char >= 'a' && char <= 'z', char >= 'A' && char <= 'Z', char >= '0' && char <= '9', char == '-', char == '_':
+		default:
+			return fmt.Errorf("%w: %q has the character %q", ErrInvalidClientOrderID, clientOrderID, char)
+		}
+	}
+
+	return nil
 }
 
 // SuccessResponse represents a successful order response.
@@ -219,18 +941,159 @@ type SuccessResponse struct {
 	ProductID     string    `json:"product_id,omitempty"`
 	Side          OrderSide `json:"side,omitempty"`
 	ClientOrderID string    `json:"client_order_id,omitempty"`
+
+	// AttachedOrderID is the ID of the one-cancels-other order attached to
+	// the created order, if any.
+	AttachedOrderID string `json:"attached_order_id,omitempty"`
 }
 
 // ErrorResponse represents an error response.
 type ErrorResponse struct {
-	Error                 string `json:"error"`
-	Message               string `json:"message,omitempty"`
-	ErrorDetails          string `json:"error_details,omitempty"`
-	PreviewFailureReason  string `json:"preview_failure_reason,omitempty"`
-	NewOrderFailureReason string `json:"new_order_failure_reason,omitempty"`
+	Error                 string                `json:"error"`
+	Message               string                `json:"message,omitempty"`
+	ErrorDetails          string                `json:"error_details,omitempty"`
+	PreviewFailureReason  string                `json:"preview_failure_reason,omitempty"`
+	NewOrderFailureReason NewOrderFailureReason `json:"new_order_failure_reason,omitempty"`
 }
 
-// Order is the response from creating an order.
+// NewOrderFailureReason represents the reason an order could not be created.
+type NewOrderFailureReason string
+
+const (
+	// NewOrderFailureUnknown represents an unknown reason for a failure.
+	NewOrderFailureUnknown NewOrderFailureReason = "UNKNOWN_FAILURE_REASON"
+
+	// NewOrderFailureUnsupportedOrderConfiguration represents an order
+	// configuration that is not supported for the product.
+	NewOrderFailureUnsupportedOrderConfiguration NewOrderFailureReason = "UNSUPPORTED_ORDER_CONFIGURATION"
+
+	// NewOrderFailureInvalidSide represents an invalid order side.
+	NewOrderFailureInvalidSide NewOrderFailureReason = "INVALID_SIDE"
+
+	// NewOrderFailureInvalidProductID represents a product that does not
+	// exist.
+	NewOrderFailureInvalidProductID NewOrderFailureReason = "INVALID_PRODUCT_ID"
+
+	// NewOrderFailureInvalidSizePrecision represents a size that is not a
+	// multiple of the product's increment.
+	NewOrderFailureInvalidSizePrecision NewOrderFailureReason = "INVALID_SIZE_PRECISION"
+
+	// NewOrderFailureInvalidPricePrecision represents a price that is not a
+	// multiple of the product's increment.
+	NewOrderFailureInvalidPricePrecision NewOrderFailureReason = "INVALID_PRICE_PRECISION"
+
+	// NewOrderFailureInsufficientFund represents an account without the
+	// funds for the order.
+	NewOrderFailureInsufficientFund NewOrderFailureReason = "INSUFFICIENT_FUND"
+
+	// NewOrderFailureInvalidLedgerBalance represents an account whose
+	// ledger balance cannot cover the order.
+	NewOrderFailureInvalidLedgerBalance NewOrderFailureReason = "INVALID_LEDGER_BALANCE"
+
+	// NewOrderFailureOrderEntryDisabled represents a product that does not
+	// accept orders at the moment, such as during maintenance.
+	NewOrderFailureOrderEntryDisabled NewOrderFailureReason = "ORDER_ENTRY_DISABLED"
+
+	// NewOrderFailureIneligiblePair represents a product that the user
+	// cannot trade.
+	NewOrderFailureIneligiblePair NewOrderFailureReason = "INELIGIBLE_PAIR"
+
+	// NewOrderFailureInvalidLimitPricePostOnly represents a post-only order
+	// that would cross the book.
+	NewOrderFailureInvalidLimitPricePostOnly NewOrderFailureReason = "INVALID_LIMIT_PRICE_POST_ONLY"
+
+	// NewOrderFailureInvalidLimitPrice represents an invalid limit price.
+	NewOrderFailureInvalidLimitPrice NewOrderFailureReason = "INVALID_LIMIT_PRICE"
+
+	// NewOrderFailureInvalidNoLiquidity represents a market order for a
+	// product whose book has no liquidity at the moment.
+	NewOrderFailureInvalidNoLiquidity NewOrderFailureReason = "INVALID_NO_LIQUIDITY"
+
+	// NewOrderFailureInvalidRequest represents a malformed order request.
+	NewOrderFailureInvalidRequest NewOrderFailureReason = "INVALID_REQUEST"
+
+	// NewOrderFailureCommanderRejected represents an order that was
+	// rejected by the exchange.
+	NewOrderFailureCommanderRejected NewOrderFailureReason = "COMMANDER_REJECTED_NEW_ORDER"
+
+	// NewOrderFailureInsufficientFunds represents an account without the
+	// funds for the order.
+	NewOrderFailureInsufficientFunds NewOrderFailureReason = "INSUFFICIENT_FUNDS"
+)
+
+// UnmarshalJSON decodes the failure reason, falling back to
+// NewOrderFailureUnknown for reasons that this package does not know about so
+// that new reasons do not break decoding.
+func (reason *NewOrderFailureReason) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to decode new order failure reason: %w", err)
+	}
+
+	switch parsed := NewOrderFailureReason(raw); parsed {
+	case "", NewOrderFailureUnknown, NewOrderFailureUnsupportedOrderConfiguration, NewOrderFailureInvalidSide,
+		NewOrderFailureInvalidProductID, NewOrderFailureInvalidSizePrecision, NewOrderFailureInvalidPricePrecision,
+		NewOrderFailureInsufficientFund, NewOrderFailureInvalidLedgerBalance, NewOrderFailureOrderEntryDisabled,
+		NewOrderFailureIneligiblePair, NewOrderFailureInvalidLimitPricePostOnly, NewOrderFailureInvalidLimitPrice,
+		NewOrderFailureInvalidNoLiquidity, NewOrderFailureInvalidRequest, NewOrderFailureCommanderRejected,
+		NewOrderFailureInsufficientFunds:
+		*reason = parsed
+	default:
+		*reason = NewOrderFailureUnknown
+	}
+
+	return nil
+}
+
+// IsRetryable reports whether the order may be created if it is sent again
+// later, because the reason it failed is transient: the product's book had no
+// liquidity, or the product did not accept orders at the moment.
+func (reason NewOrderFailureReason) IsRetryable() bool {
+	switch reason {
+	case NewOrderFailureInvalidNoLiquidity, NewOrderFailureOrderEntryDisabled:
+		return true
+	case NewOrderFailureUnknown, NewOrderFailureUnsupportedOrderConfiguration, NewOrderFailureInvalidSide,
+		NewOrderFailureInvalidProductID, NewOrderFailureInvalidSizePrecision, NewOrderFailureInvalidPricePrecision,
+		NewOrderFailureInsufficientFund, NewOrderFailureInvalidLedgerBalance, NewOrderFailureIneligiblePair,
+		NewOrderFailureInvalidLimitPricePostOnly, NewOrderFailureInvalidLimitPrice, NewOrderFailureInvalidRequest,
+		NewOrderFailureCommanderRejected, NewOrderFailureInsufficientFunds:
+		return false
+	}
+
+	return false
+}
+
+// postOnlyReason is contained in the failure reasons of a post-only order that
+// would cross the book, such as "INVALID_LIMIT_PRICE_POST_ONLY" and
+// "PREVIEW_INVALID_LIMIT_PRICE_POST_ONLY".
+const postOnlyReason = "POST_ONLY"
+
+// isPostOnlyRejection reports whether the error response rejects a post-only
+// order that would cross the book.
+func (errResp ErrorResponse) isPostOnlyRejection() bool {
+	reasons := []string{errResp.Error, errResp.PreviewFailureReason, string(errResp.NewOrderFailureReason)}
+	for _, reason := range reasons {
+		if strings.Contains(strings.ToUpper(reason), postOnlyReason) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExecutionStats represents how much of an order has been executed. The
+// amounts are decimal strings, empty when the response does not include them.
+type ExecutionStats struct {
+	AverageFilledPrice string `json:"average_filled_price,omitempty"`
+	FilledSize         string `json:"filled_size,omitempty"`
+	FilledValue        string `json:"filled_value,omitempty"`
+	NumberOfFills      string `json:"number_of_fills,omitempty"`
+	Fee                string `json:"fee,omitempty"`
+	TotalFees          string `json:"total_fees,omitempty"`
+}
+
+// Order is the response from creating an order. Market and other immediate
+// orders may already include their execution stats.
 type Order struct {
 	Success            bool            `json:"success"`
 	FailureReason      string          `json:"failure_reason"`
@@ -238,54 +1101,667 @@ type Order struct {
 	SuccessResponse    SuccessResponse `json:"success_response,omitempty"`
 	ErrorResponse      ErrorResponse   `json:"error_response,omitempty"`
 	OrderConfiguration OrderConfig     `json:"order_configuration,omitempty"`
+
+	ExecutionStats
 }
 
 // CreateOrder will create an order with a specified product_id (BASE-QUOTE),
-// side (buy/sell), etc.
+// side (buy/sell), etc. The order request is validated before it is sent, see
+// OrderRequest.Validate. ErrPostOnlyWouldCross is returned if the order is
+// post-only and is rejected because it would cross the book, and
+// ErrNoTradePermission if the client checks its key's permissions and the key
+// cannot trade, see WithTradePermissionCheck.
 //
 // https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_postorder
-func (client *Client) CreateOrder(ctx context.Context, orderReq OrderRequest) (*Order, error) {
-	full, err := url.JoinPath(api, "brokerage", "orders")
-	if err != nil {
-		return nil, fmt.Errorf("failed to join path: %w", err)
+func (client *Client) CreateOrder(ctx context.Context, orderReq OrderRequest, opts ...CallOption) (*Order, error) {
+	if err := orderReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	return client.createOrder(ctx, orderReq, opts)
+}
+
+// previewOrderRequest is the request body for creating an order from a
+// preview.
+type previewOrderRequest struct {
+	OrderRequest
+
+	PreviewID string `json:"preview_id"`
+}
+
+// CreateOrderFromPreview creates an order like CreateOrder, from the preview
+// with the given ID, so that the order's fees and quote match those of the
+// preview. The order request should be the one that was previewed.
+// ErrMissingPreviewID is returned if the preview ID is empty.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_postorder
+func (client *Client) CreateOrderFromPreview(ctx context.Context, previewID string, orderReq OrderRequest,
+	opts ...CallOption,
+) (*Order, error) {
+	if strings.TrimSpace(previewID) == "" {
+		return nil, ErrMissingPreviewID
+	}
+
+	if err := orderReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	return client.createOrder(ctx, previewOrderRequest{OrderRequest: orderReq, PreviewID: previewID}, opts)
+}
+
+// createOrder sends the request body for a new order, which has been
+// validated.
+func (client *Client) createOrder(ctx context.Context, body any, opts []CallOption) (*Order, error) {
+	if err := client.checkTradePermission(ctx, opts); err != nil {
+		return nil, err
+	}
+
+	path := []string{"brokerage", "orders"}
+
+	orderResponse := &Order{}
+	if err := client.do(ctx, http.MethodPost, path, nil, body, orderResponse, opts); err != nil {
+		return nil, err
+	}
+
+	if !orderResponse.Success && orderResponse.ErrorResponse.isPostOnlyRejection() {
+		return nil, fmt.Errorf("%w: %s: %s", ErrPostOnlyWouldCross,
+			orderResponse.ErrorResponse.Error, orderResponse.ErrorResponse.Message)
+	}
+
+	if orderResponse.Success && orderResponse.OrderID != "" {
+		client.createdOrders.record(orderResponse.OrderID, time.Now(), client.visibilityWindow())
+	}
+
+	return orderResponse, nil
+}
+
+// CreateOrders will create each of the given orders, sending at most
+// "concurrency" requests at a time. The returned orders and errors are aligned
+// by index with the given requests, so the failure of one order does not stop
+// the rest of the batch from being submitted. Once the context is cancelled, no
+// more requests are sent and the orders not yet submitted fail with the
+// context's error.
+func (client *Client) CreateOrders(ctx context.Context, reqs []OrderRequest, concurrency int,
+	opts ...CallOption,
+) ([]*Order, []error) {
+	orders := make([]*Order, len(reqs))
+	errs := make([]error, len(reqs))
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+
+	for i := range reqs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			for j := i; j < len(reqs); j++ {
+				errs[j] = ctx.Err()
+			}
+
+			wg.Wait()
+
+			return orders, errs
+		}
+
+		wg.Add(1)
+
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			orders[i], errs[i] = client.CreateOrder(ctx, reqs[i], opts...)
+		}(i)
+	}
+
+	wg.Wait()
+
+	return orders, errs
+}
+
+// OrderStatus represents the status of an order.
+type OrderStatus string
+
+const (
+	// OrderStatusUnknown represents an unknown order status.
+	OrderStatusUnknown OrderStatus = "UNKNOWN_ORDER_STATUS"
+
+	// OrderStatusPending represents an order that has not yet been
+	// accepted.
+	OrderStatusPending OrderStatus = "PENDING"
+
+	// OrderStatusOpen represents an order that is resting on the book.
+	OrderStatusOpen OrderStatus = "OPEN"
+
+	// OrderStatusFilled represents an order that has been completely
+	// filled.
+	OrderStatusFilled OrderStatus = "FILLED"
+
+	// OrderStatusCancelled represents an order that has been cancelled.
+	OrderStatusCancelled OrderStatus = "CANCELLED"
+
+	// OrderStatusExpired represents an order that has expired.
+	OrderStatusExpired OrderStatus = "EXPIRED"
+
+	// OrderStatusFailed represents an order that failed to be placed.
+	OrderStatusFailed OrderStatus = "FAILED"
+
+	// OrderStatusQueued represents an order that is queued to be placed.
+	OrderStatusQueued OrderStatus = "QUEUED"
+
+	// OrderStatusCancelQueued represents an order that is queued to be
+	// cancelled.
+	OrderStatusCancelQueued OrderStatus = "CANCEL_QUEUED"
+)
+
+// IsTerminal reports whether an order with the status can no longer change,
+// i.e. it has been filled, cancelled, expired or has failed.
+func (status OrderStatus) IsTerminal() bool {
+	switch status {
+	case OrderStatusFilled, OrderStatusCancelled, OrderStatusExpired, OrderStatusFailed:
+		return true
+	case OrderStatusUnknown, OrderStatusPending, OrderStatusOpen, OrderStatusQueued, OrderStatusCancelQueued:
+		return false
+	}
+
+	return false
+}
+
+// EditHistoryEntry represents a prior edit of an order's price or size.
+type EditHistoryEntry struct {
+	Price                  string    `json:"price"`
+	Size                   string    `json:"size"`
+	ReplaceAcceptTimestamp time.Time `json:"replace_accept_timestamp"`
+}
+
+// HistoricalOrder represents an order that has been placed on Coinbase.
+type HistoricalOrder struct {
+	OrderID            string      `json:"order_id"`
+	ProductID          string      `json:"product_id"`
+	UserID             string      `json:"user_id"`
+	OrderConfiguration OrderConfig `json:"order_configuration"`
+	Side               OrderSide   `json:"side"`
+	ClientOrderID      string      `json:"client_order_id"`
+	Status             OrderStatus `json:"status"`
+	TimeInForce        string      `json:"time_in_force"`
+	CreatedTime        time.Time   `json:"created_time"`
+	OrderType          string      `json:"order_type"`
+
+	// RetailPortfolioID is the ID of the portfolio that the order was
+	// placed in.
+	RetailPortfolioID string `json:"retail_portfolio_id"`
+
+	// EditHistory lists the edits made to the order, oldest first.
+	EditHistory []EditHistoryEntry `json:"edit_history,omitempty"`
+
+	ExecutionStats
+
+	// AttachedOrderConfiguration is the configuration of the order's
+	// attached one-cancels-other order, if any.
+	AttachedOrderConfiguration *OrderConfig `json:"attached_order_configuration,omitempty"`
+
+	// AttachedOrderID is the ID of the one-cancels-other order attached to
+	// the order, and OriginatingOrderID the ID of the order that an
+	// attached order was attached to, so that either side of a bracket
+	// can be found from the other.
+	AttachedOrderID    string `json:"attached_order_id,omitempty"`
+	OriginatingOrderID string `json:"originating_order_id,omitempty"`
+}
+
+// FilledFraction returns the fraction of the order's original size that has
+// been filled, from zero for an unfilled order to one for a fully filled one.
+// Market orders sized in the quote currency compare their filled value with the
+// quote size, other orders their filled size with the base size. An order
+// whose original size is zero or unknown has a fraction of zero.
+func (order HistoricalOrder) FilledFraction() (decimal.Decimal, error) {
+	size, filled := order.originalSize()
+	if size == "" {
+		return decimal.Zero, nil
 	}
 
-	// Create the request body.
-	body, err := json.Marshal(orderReq)
+	sizeAmount, err := decimal.NewFromString(size)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		return decimal.Decimal{}, fmt.Errorf("failed to parse size %q: %w", size, err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, full, bytes.NewReader(body))
+	if sizeAmount.IsZero() || filled == "" {
+		return decimal.Zero, nil
+	}
+
+	filledAmount, err := decimal.NewFromString(filled)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return decimal.Decimal{}, fmt.Errorf("failed to parse filled size %q: %w", filled, err)
 	}
 
-	// Header should be application/json.
-	req.Header.Set("Content-Type", "application/json")
+	return filledAmount.Div(sizeAmount), nil
+}
 
-	resp, err := client.httpClient.Do(req)
+// IsPartiallyFilled reports whether some, but not all, of the order has been
+// filled, see FilledFraction. An order whose sizes cannot be parsed is not
+// reported as partially filled.
+func (order HistoricalOrder) IsPartiallyFilled() bool {
+	fraction, err := order.FilledFraction()
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return false
 	}
 
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			panic(err)
+	return fraction.IsPositive() && fraction.LessThan(decimal.NewFromInt(1))
+}
+
+// originalSize returns the size the order was placed with and the matching
+// filled amount.
+func (order HistoricalOrder) originalSize() (string, string) {
+	config := order.OrderConfiguration
+
+	switch {
+	case config.MarketIOC != nil && config.MarketIOC.QuoteSize != "":
+		return config.MarketIOC.QuoteSize, order.FilledValue
+	case config.MarketIOC != nil:
+		return config.MarketIOC.BaseSize, order.FilledSize
+	case config.LimitGTC != nil:
+		return config.LimitGTC.BaseSize, order.FilledSize
+	case config.LimitGTD != nil:
+		return config.LimitGTD.BaseSize, order.FilledSize
+	case config.StopLimitGTC != nil:
+		return config.StopLimitGTC.BaseSize, order.FilledSize
+	case config.StopLimitGTD != nil:
+		return config.StopLimitGTD.BaseSize, order.FilledSize
+	}
+
+	return "", order.FilledSize
+}
+
+// Orders represents a collection of historical orders along with metadata.
+type Orders struct {
+	Data     []HistoricalOrder `json:"orders"`
+	Sequence string            `json:"sequence"`
+	HasNext  bool              `json:"has_next"`
+	Cursor   string            `json:"cursor"`
+}
+
+// ListOrdersParams are the query parameters used to filter the orders returned
+// by ListOrders. Zero values are omitted from the request.
+type ListOrdersParams struct {
+	ProductID   string
+	OrderStatus []OrderStatus
+	OrderSide   OrderSide
+	StartDate   time.Time
+	EndDate     time.Time
+	Limit       int32
+	Cursor      string
+
+	// RetailPortfolioID limits the orders to those placed in the given
+	// portfolio.
+	RetailPortfolioID string
+}
+
+// query returns the URL query values for the parameters.
+func (params ListOrdersParams) query() url.Values {
+	query := url.Values{}
+
+	if params.ProductID != "" {
+		query.Set("product_id", params.ProductID)
+	}
+
+	for _, status := range params.OrderStatus {
+		query.Add("order_status", string(status))
+	}
+
+	if params.OrderSide != "" {
+		query.Set("order_side", string(params.OrderSide))
+	}
+
+	if !params.StartDate.IsZero() {
+		query.Set("start_date", params.StartDate.UTC().Format(time.RFC3339Nano))
+	}
+
+	if !params.EndDate.IsZero() {
+		query.Set("end_date", params.EndDate.UTC().Format(time.RFC3339Nano))
+	}
+
+	if params.Limit > 0 {
+		formatBase := 10
+		query.Set("limit", strconv.FormatInt(int64(params.Limit), formatBase))
+	}
+
+	if params.Cursor != "" {
+		query.Set("cursor", params.Cursor)
+	}
+
+	if params.RetailPortfolioID != "" {
+		query.Set("retail_portfolio_id", params.RetailPortfolioID)
+	}
+
+	return query
+}
+
+// ListOrders returns a page of historical orders matching the given
+// parameters. A limit greater than the endpoint's maximum is handled according
+// to the client's LimitPolicy. The Data of an empty page is an empty slice,
+// never nil.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_gethistoricalorders
+func (client *Client) ListOrders(ctx context.Context, params ListOrdersParams, opts ...CallOption) (*Orders, error) {
+	path := []string{"brokerage", "orders", "historical", "batch"}
+
+	var err error
+	if params.Limit, err = client.checkLimit(params.Limit, maxOrdersLimit); err != nil {
+		return nil, err
+	}
+
+	orders := &Orders{}
+	if err := client.do(ctx, http.MethodGet, path, params.query(), nil, orders, opts); err != nil {
+		return nil, err
+	}
+
+	orders.Data = emptyIfNil(orders.Data)
+
+	return orders, nil
+}
+
+// getOrderResponse is the response from getting a single historical order.
+type getOrderResponse struct {
+	Order HistoricalOrder `json:"order"`
+}
+
+// GetOrder returns a single historical order by its order ID. An order that
+// Coinbase does not find fails with ErrOrderNotFound, wrapping the StatusError.
+//
+// Since an order is not always visible as soon as it has been created, an
+// order created by the client that is not found is looked up again with a
+// backoff until it is, or until the client's order visibility window has
+// passed since it was created, see WithOrderVisibilityWindow. Only then is it
+// reported as not found.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_gethistoricalorder
+func (client *Client) GetOrder(ctx context.Context, orderID string, opts ...CallOption) (*HistoricalOrder, error) {
+	path := []string{"brokerage", "orders", "historical", orderID}
+	wait := orderVisibilityRetryWait
+
+	for {
+		resp := &getOrderResponse{}
+
+		err := client.do(ctx, http.MethodGet, path, nil, nil, resp, opts)
+		if err == nil {
+			return &resp.Order, nil
 		}
-	}()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
+		if !isNotFound(err) {
+			return nil, err
+		}
+
+		created, recent := client.createdOrders.createdAt(orderID)
+
+		remaining := time.Until(created.Add(client.visibilityWindow()))
+		if !recent || remaining <= 0 {
+			return nil, &orderNotFoundError{orderID: orderID, err: err}
+		}
+
+		if wait > remaining {
+			wait = remaining
+		}
 
-		return nil, fmt.Errorf("%w: unexpected status code: %d, body: %s",
-			ErrStatusNotOK, resp.StatusCode, body)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to wait for order %s to be visible: %w", orderID, ctx.Err())
+		case <-time.After(wait):
+		}
+
+		if wait *= 2; wait > maxOrderVisibilityRetryWait {
+			wait = maxOrderVisibilityRetryWait
+		}
 	}
+}
 
-	orderResponse := &Order{}
-	if err := json.NewDecoder(resp.Body).Decode(orderResponse); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// GetOrderByClientID returns the historical order with the given client order
+// ID. Coinbase cannot filter orders by client order ID, so every order is
+// scanned. ErrOrderNotFound is returned if no order matches, and
+// ErrAmbiguousClientOrderID if more than one does. ErrInvalidClientOrderID is
+// returned for an empty client order ID, which orders without one would match.
+func (client *Client) GetOrderByClientID(ctx context.Context, clientOrderID string,
+	opts ...CallOption,
+) (*HistoricalOrder, error) {
+	if clientOrderID == "" {
+		return nil, fmt.Errorf("%w: empty client order ID", ErrInvalidClientOrderID)
 	}
 
-	return orderResponse, nil
+	var match *HistoricalOrder
+
+	pager := client.OrdersPager(ctx, ListOrdersParams{}, opts...)
+	for pager.Next() {
+		order := pager.Order()
+		if order.ClientOrderID != clientOrderID {
+			continue
+		}
+
+		if match != nil {
+			return nil, fmt.Errorf("%w: %q matches orders %s and %s",
+				ErrAmbiguousClientOrderID, clientOrderID, match.OrderID, order.OrderID)
+		}
+
+		match = &order
+	}
+
+	if err := pager.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	if match == nil {
+		return nil, fmt.Errorf("%w: no order has client order ID %q", ErrOrderNotFound, clientOrderID)
+	}
+
+	return match, nil
+}
+
+// CancelFailureReason represents the reason an order could not be cancelled.
+type CancelFailureReason string
+
+const (
+	// CancelFailureUnknown represents an unknown reason for a failure.
+	CancelFailureUnknown CancelFailureReason = "UNKNOWN_CANCEL_FAILURE_REASON"
+
+	// CancelFailureInvalidRequest represents a malformed cancel request.
+	CancelFailureInvalidRequest CancelFailureReason = "INVALID_CANCEL_REQUEST"
+
+	// CancelFailureUnknownOrder represents a cancel of an order that does
+	// not exist.
+	CancelFailureUnknownOrder CancelFailureReason = "UNKNOWN_CANCEL_ORDER"
+
+	// CancelFailureCommanderRejected represents a cancel that was rejected,
+	// for example because the order has already been filled or cancelled.
+	CancelFailureCommanderRejected CancelFailureReason = "COMMANDER_REJECTED_CANCEL_ORDER"
+
+	// CancelFailureDuplicateRequest represents a cancel of an order that is
+	// already being cancelled.
+	CancelFailureDuplicateRequest CancelFailureReason = "DUPLICATE_CANCEL_REQUEST"
+)
+
+// CancelOrderResult is the result of cancelling a single order.
+type CancelOrderResult struct {
+	Success bool `json:"success"`
+
+	// FailureReason is the reason the order could not be cancelled, or
+	// empty if it was. Reasons that this package does not know about are
+	// kept as they are.
+	FailureReason CancelFailureReason `json:"failure_reason"`
+	OrderID       string              `json:"order_id"`
+
+	// ClientOrderID is the client order ID that the order was resolved
+	// from. It is only set by CancelByClientOrderID.
+	ClientOrderID string `json:"-"`
+}
+
+// CancelOrdersResult is the response from cancelling a batch of orders.
+type CancelOrdersResult struct {
+	Results []CancelOrderResult `json:"results"`
+}
+
+// Failed returns the IDs of the orders that could not be cancelled, in the
+// order of the results. Failed results without an order ID, such as those of
+// the client order IDs that CancelByClientOrderID did not match to an open
+// order, are left out; use ByClientOrderID to find those.
+func (result *CancelOrdersResult) Failed() []string {
+	var failed []string
+
+	for _, res := range result.Results {
+		if !res.Success && res.OrderID != "" {
+			failed = append(failed, res.OrderID)
+		}
+	}
+
+	return failed
+}
+
+// ByClientOrderID returns the results keyed by the client order ID they were
+// resolved from. Results without a client order ID are omitted.
+func (result *CancelOrdersResult) ByClientOrderID() map[string]CancelOrderResult {
+	byID := make(map[string]CancelOrderResult, len(result.Results))
+
+	for _, res := range result.Results {
+		if res.ClientOrderID != "" {
+			byID[res.ClientOrderID] = res
+		}
+	}
+
+	return byID
+}
+
+// cancelOrdersRequest is the request body for cancelling a batch of orders.
+type cancelOrdersRequest struct {
+	OrderIDs []string `json:"order_ids"`
+}
+
+// CancelOrders initiates cancel requests for one or more orders.
+// ErrNoTradePermission is returned if the client checks its key's permissions
+// and the key cannot trade, see WithTradePermissionCheck.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_cancelorders
+func (client *Client) CancelOrders(ctx context.Context, orderIDs []string,
+	opts ...CallOption,
+) (*CancelOrdersResult, error) {
+	if err := client.checkTradePermission(ctx, opts); err != nil {
+		return nil, err
+	}
+
+	path := []string{"brokerage", "orders", "batch_cancel"}
+	body := cancelOrdersRequest{OrderIDs: orderIDs}
+
+	result := &CancelOrdersResult{}
+	if err := client.do(ctx, http.MethodPost, path, nil, body, result, opts); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CancelByClientOrderID cancels the open orders with the given client order
+// IDs. The client order IDs are resolved to Coinbase order IDs by listing the
+// open orders, and each result is tagged with the client order ID it was
+// resolved from. A client order ID that does not match an open order is
+// reported as a failed result with the "UNKNOWN_CANCEL_ORDER" failure reason
+// rather than an error. ErrInvalidClientOrderID is returned if any of the
+// client order IDs is empty.
+func (client *Client) CancelByClientOrderID(ctx context.Context, clientOrderIDs []string,
+	opts ...CallOption,
+) (*CancelOrdersResult, error) {
+	wanted := make(map[string]string, len(clientOrderIDs))
+	for _, id := range clientOrderIDs {
+		if id == "" {
+			return nil, fmt.Errorf("%w: empty client order ID", ErrInvalidClientOrderID)
+		}
+
+		wanted[id] = ""
+	}
+
+	pager := client.OpenOrdersPager(ctx, "", opts...)
+
+	for pager.Next() {
+		order := pager.Order()
+		if orderID, ok := wanted[order.ClientOrderID]; ok && orderID == "" {
+			wanted[order.ClientOrderID] = order.OrderID
+		}
+	}
+
+	if err := pager.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list open orders: %w", err)
+	}
+
+	result := &CancelOrdersResult{}
+	clientIDs := make(map[string]string, len(wanted))
+	orderIDs := make([]string, 0, len(wanted))
+
+	for _, clientOrderID := range clientOrderIDs {
+		orderID, ok := wanted[clientOrderID]
+		if !ok {
+			continue // duplicate client order ID
+		}
+
+		delete(wanted, clientOrderID)
+
+		if orderID == "" {
+			result.Results = append(result.Results, CancelOrderResult{
+				FailureReason: CancelFailureUnknownOrder,
+				ClientOrderID: clientOrderID,
+			})
+
+			continue
+		}
+
+		clientIDs[orderID] = clientOrderID
+		orderIDs = append(orderIDs, orderID)
+	}
+
+	if len(orderIDs) == 0 {
+		return result, nil
+	}
+
+	cancelled, err := client.CancelOrders(ctx, orderIDs, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, res := range cancelled.Results {
+		res.ClientOrderID = clientIDs[res.OrderID]
+		result.Results = append(result.Results, res)
+	}
+
+	return result, nil
+}
+
+// CancelOrdersOlderThan cancels the open orders for the given product that were
+// created more than age ago. If the product ID is empty, stale open orders for
+// all products are cancelled. Orders without a created time are left open,
+// since their age is unknown. If no order is stale, an empty result is returned
+// without sending a cancel request.
+func (client *Client) CancelOrdersOlderThan(ctx context.Context, productID string, age time.Duration,
+	opts ...CallOption,
+) (*CancelOrdersResult, error) {
+	cutoff := time.Now().Add(-age)
+
+	var orderIDs []string
+
+	pager := client.OpenOrdersPager(ctx, productID, opts...)
+
+	for pager.Next() {
+		order := pager.Order()
+		if !order.CreatedTime.IsZero() && order.CreatedTime.Before(cutoff) {
+			orderIDs = append(orderIDs, order.OrderID)
+		}
+	}
+
+	if err := pager.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list open orders: %w", err)
+	}
+
+	if len(orderIDs) == 0 {
+		return &CancelOrdersResult{}, nil
+	}
+
+	return client.CancelOrders(ctx, orderIDs, opts...)
 }
@@ -1,14 +1,18 @@
 package coinbase_test
 
 import (
-	"context"
 	"log"
 	"os"
+	"time"
 
 	"github.com/alpstable/coinbase"
 	"github.com/google/uuid"
 )
 
+// exampleTimeout bounds the calls made by the examples, which should not be
+// left to hang on a stalled connection.
+const exampleTimeout = 10 * time.Second
+
 //nolint:testableexamples
 func ExampleClient_Accounts() {
 	// DO NOT RUN THIS EXAMPLE ON A LIVE ACCOUNT
@@ -21,12 +25,15 @@ func ExampleClient_Accounts() {
 		return
 	}
 
-	client, err := coinbase.NewClient(key, secret)
+	client, err := coinbase.NewClient(key, secret, coinbase.WithTimeout(exampleTimeout))
 	if err != nil {
 		panic(err)
 	}
 
-	accounts, err := client.Accounts(context.Background())
+	ctx, cancel := client.DefaultContext()
+	defer cancel()
+
+	accounts, err := client.Accounts(ctx)
 	if err != nil {
 		panic(err)
 	}
@@ -46,11 +53,14 @@ func ExampleClient_CreateOrder() {
 		return
 	}
 
-	client, err := coinbase.NewClient(key, secret)
+	client, err := coinbase.NewClient(key, secret, coinbase.WithTimeout(exampleTimeout))
 	if err != nil {
 		panic(err)
 	}
 
+	ctx, cancel := client.DefaultContext()
+	defer cancel()
+
 	limit := &coinbase.LimitGTCConfig{
 		BaseSize: "1",
 		Price:    "2.7",
@@ -68,7 +78,7 @@ func ExampleClient_CreateOrder() {
 	}
 
 	// Buy 1 BTC at 2.7 USDT
-	orderResponse, err := client.CreateOrder(context.Background(), req)
+	orderResponse, err := client.CreateOrder(ctx, req)
 	if err != nil {
 		panic(err)
 	}
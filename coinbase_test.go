@@ -3,10 +3,14 @@ package coinbase
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
+	"net/url"
 	"reflect"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -23,6 +27,23 @@ func (m *mockClient) Do(req *http.Request) (*http.Response, error) {
 	}, nil
 }
 
+// mockDoFunc is a mock HTTP client that delegates each request to a function,
+// allowing tests to vary the response by request.
+type mockDoFunc func(*http.Request) (*http.Response, error)
+
+func (fn mockDoFunc) Do(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+// newMockResponse returns an HTTP response with the given status code and
+// body.
+func newMockResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		StatusCode: statusCode,
+	}
+}
+
 func TestAccounts(t *testing.T) {
 	t.Parallel()
 
@@ -39,7 +60,7 @@ func TestAccounts(t *testing.T) {
 		{
 			name:     "empty slice",
 			response: []byte(`{}`),
-			want:     &Accounts{},
+			want:     &Accounts{Data: []Account{}},
 		},
 		{
 			name: "single",
@@ -63,7 +84,8 @@ func TestAccounts(t *testing.T) {
     "hold": {
       "value": "1.23",
       "currency": "BTC"
-    }
+    },
+    "retail_portfolio_id": "3333-000000-000000"
   }],
   "has_next": true,
   "cursor": "789100",
@@ -88,12 +110,13 @@ func TestAccounts(t *testing.T) {
 
 							return &dt
 						}(),
-						Type:  "ACCOUNT_TYPE_UNSPECIFIED",
+						Type:  AccountTypeUnspecified,
 						Ready: true,
 						Hold: HoldMoney{
 							Value:    "1.23",
 							Currency: "BTC",
 						},
+						RetailPortfolioID: "3333-000000-000000",
 					},
 				},
 				HasNext: true,
@@ -128,6 +151,65 @@ func TestAccounts(t *testing.T) {
 	}
 }
 
+func TestAccountBalances(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		response  []byte
+		available AvailableMoney
+		hold      HoldMoney
+	}{
+		{
+			name: "differing currencies",
+			response: []byte(`{"accounts": [{
+				"currency": "USDC",
+				"available_balance": {"value": "10", "currency": "USDC"},
+				"hold": {"value": "2.5", "currency": "USD"}
+			}]}`),
+			available: AvailableMoney{Value: "10", Currency: "USDC"},
+			hold:      HoldMoney{Value: "2.5", Currency: "USD"},
+		},
+		{
+			name: "absent hold",
+			response: []byte(`{"accounts": [{
+				"currency": "BTC",
+				"available_balance": {"value": "0.5", "currency": "BTC"}
+			}]}`),
+			available: AvailableMoney{Value: "0.5", Currency: "BTC"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{
+				httpClient: &mockClient{response: test.response, statusCode: http.StatusOK},
+			}
+
+			accounts, err := client.Accounts(context.Background())
+			if err != nil {
+				t.Fatalf("failed to list accounts: %v", err)
+			}
+
+			if len(accounts.Data) != 1 {
+				t.Fatalf("got %d accounts, want 1", len(accounts.Data))
+			}
+
+			if got := accounts.Data[0].AvailableBalance; got != test.available {
+				t.Fatalf("got available balance %+v, want %+v", got, test.available)
+			}
+
+			if got := accounts.Data[0].Hold; got != test.hold {
+				t.Fatalf("got hold %+v, want %+v", got, test.hold)
+			}
+		})
+	}
+}
+
 func TestCreateOrder(t *testing.T) {
 	t.Parallel()
 
@@ -247,6 +329,409 @@ func TestCreateOrder(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "one-cancels-other",
+			response: []byte(`
+{
+  "success": true,
+  "order_id": "11111-00000-000000",
+  "success_response": {
+    "order_id": "11111-00000-000000",
+    "product_id": "BTC-USD",
+    "side": "BUY",
+    "client_order_id": "0000-00000-000000",
+    "attached_order_id": "22222-00000-000000"
+  }
+}
+`),
+			want: &Order{
+				Success: true,
+				OrderID: "11111-00000-000000",
+				SuccessResponse: SuccessResponse{
+					OrderID:         "11111-00000-000000",
+					ProductID:       "BTC-USD",
+					Side:            OrderSideBuy,
+					ClientOrderID:   "0000-00000-000000",
+					AttachedOrderID: "22222-00000-000000",
+				},
+			},
+		},
+		{
+			name: "market order with execution stats",
+			response: []byte(`
+{
+  "success": true,
+  "order_id": "11111-00000-000000",
+  "success_response": {
+    "order_id": "11111-00000-000000",
+    "product_id": "BTC-USD",
+    "side": "BUY",
+    "client_order_id": "0000-00000-000000"
+  },
+  "order_configuration": {
+    "market_market_ioc": {
+      "quote_size": "10.00"
+    }
+  },
+  "average_filled_price": "30000.00",
+  "filled_size": "0.00033",
+  "filled_value": "9.9",
+  "number_of_fills": "2",
+  "fee": "0.1",
+  "total_fees": "0.1"
+}
+`),
+			want: &Order{
+				Success: true,
+				OrderID: "11111-00000-000000",
+				SuccessResponse: SuccessResponse{
+					OrderID:       "11111-00000-000000",
+					ProductID:     "BTC-USD",
+					Side:          OrderSideBuy,
+					ClientOrderID: "0000-00000-000000",
+				},
+				OrderConfiguration: OrderConfig{
+					MarketIOC: &MarketIOCConfig{QuoteSize: "10.00"},
+				},
+				ExecutionStats: ExecutionStats{
+					AverageFilledPrice: "30000.00",
+					FilledSize:         "0.00033",
+					FilledValue:        "9.9",
+					NumberOfFills:      "2",
+					Fee:                "0.1",
+					TotalFees:          "0.1",
+				},
+			},
+		},
+		{
+			name: "post-only rejection",
+			response: []byte(`
+{
+  "success": false,
+  "failure_reason": "UNKNOWN_FAILURE_REASON",
+  "error_response": {
+    "error": "INVALID_LIMIT_PRICE_POST_ONLY",
+    "message": "Invalid limit price for post only order",
+    "preview_failure_reason": "PREVIEW_INVALID_LIMIT_PRICE_POST_ONLY",
+    "new_order_failure_reason": "INVALID_LIMIT_PRICE_POST_ONLY"
+  }
+}`),
+			err: ErrPostOnlyWouldCross,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{
+				httpClient: &mockClient{
+					response:   test.response,
+					statusCode: http.StatusOK,
+				},
+			}
+
+			orderReq := OrderRequest{
+				Configuration: OrderConfig{MarketIOC: &MarketIOCConfig{QuoteSize: "10.00"}},
+			}
+
+			got, err := client.CreateOrder(context.Background(), orderReq)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCreateOrderFromPreview(t *testing.T) {
+	t.Parallel()
+
+	orderReq := OrderRequest{
+		ClientOrderID: "0000-00000-000000",
+		ProductID:     "BTC-USD",
+		Side:          OrderSideBuy,
+		Configuration: OrderConfig{MarketIOC: &MarketIOCConfig{QuoteSize: "10.00"}},
+	}
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			body := map[string]any{}
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+
+			if body["preview_id"] != "preview-1" || body["product_id"] != "BTC-USD" {
+				t.Errorf("got body %v, want the order with its preview ID", body)
+			}
+
+			return newMockResponse(http.StatusOK, `{
+				"success": true,
+				"order_id": "11111-00000-000000",
+				"success_response": {
+					"order_id": "11111-00000-000000",
+					"product_id": "BTC-USD",
+					"side": "BUY",
+					"client_order_id": "0000-00000-000000"
+				}
+			}`), nil
+		}),
+	}
+
+	got, err := client.CreateOrderFromPreview(context.Background(), "preview-1", orderReq)
+	if err != nil {
+		t.Fatalf("failed to create order: %v", err)
+	}
+
+	want := &Order{
+		Success: true,
+		OrderID: "11111-00000-000000",
+		SuccessResponse: SuccessResponse{
+			OrderID:       "11111-00000-000000",
+			ProductID:     "BTC-USD",
+			Side:          OrderSideBuy,
+			ClientOrderID: "0000-00000-000000",
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	if _, err := client.CreateOrderFromPreview(context.Background(), " ", orderReq); !errors.Is(err, ErrMissingPreviewID) {
+		t.Fatalf("got %v, want %v", err, ErrMissingPreviewID)
+	}
+}
+
+func TestCreateOrderPostOnlyStatusError(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{
+		httpClient: &mockClient{
+			response:   []byte(`{"error": "INVALID_LIMIT_PRICE_POST_ONLY", "message": "would cross"}`),
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	orderReq := OrderRequest{
+		Configuration: OrderConfig{LimitGTC: &LimitGTCConfig{BaseSize: "1", Price: "100", PostOnly: true}},
+	}
+
+	_, err := client.CreateOrder(context.Background(), orderReq)
+	if !errors.Is(err, ErrPostOnlyWouldCross) || !errors.Is(err, ErrStatusNotOK) {
+		t.Fatalf("got %v, want %v and %v", err, ErrPostOnlyWouldCross, ErrStatusNotOK)
+	}
+
+	client.httpClient = &mockClient{
+		response:   []byte(`{"error": "INVALID_LIMIT_PRICE"}`),
+		statusCode: http.StatusBadRequest,
+	}
+	if _, err := client.CreateOrder(context.Background(), orderReq); errors.Is(err, ErrPostOnlyWouldCross) {
+		t.Fatalf("got %v, want an error that is not %v", err, ErrPostOnlyWouldCross)
+	}
+}
+
+func TestCreateOrders(t *testing.T) {
+	t.Parallel()
+
+	const concurrency = 2
+
+	var inFlight, maxInFlight int32
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+
+			for {
+				peak := atomic.LoadInt32(&maxInFlight)
+				if n <= peak || atomic.CompareAndSwapInt32(&maxInFlight, peak, n) {
+					break
+				}
+			}
+
+			time.Sleep(10 * time.Millisecond)
+
+			orderReq := OrderRequest{}
+			if err := json.NewDecoder(req.Body).Decode(&orderReq); err != nil {
+				return nil, err
+			}
+
+			if orderReq.ClientOrderID == "bad" {
+				return newMockResponse(http.StatusBadRequest, `{"error":"INVALID_ARGUMENT"}`), nil
+			}
+
+			return newMockResponse(http.StatusOK, `{"success":true,"order_id":"`+orderReq.ClientOrderID+`"}`), nil
+		}),
+	}
+
+	config := OrderConfig{MarketIOC: &MarketIOCConfig{QuoteSize: "10.00"}}
+
+	reqs := []OrderRequest{
+		{ClientOrderID: "a", Configuration: config},
+		{ClientOrderID: "bad", Configuration: config},
+		{ClientOrderID: "b", Configuration: config},
+		{ClientOrderID: "bad", Configuration: config},
+		{ClientOrderID: "c", Configuration: config},
+	}
+
+	orders, errs := client.CreateOrders(context.Background(), reqs, concurrency)
+	if len(orders) != len(reqs) || len(errs) != len(reqs) {
+		t.Fatalf("got %d orders and %d errors, want %d", len(orders), len(errs), len(reqs))
+	}
+
+	for i, req := range reqs {
+		if req.ClientOrderID == "bad" {
+			if !errors.Is(errs[i], ErrStatusNotOK) {
+				t.Fatalf("request %d: got error %v, want %v", i, errs[i], ErrStatusNotOK)
+			}
+
+			if orders[i] != nil {
+				t.Fatalf("request %d: got order %v, want nil", i, orders[i])
+			}
+
+			continue
+		}
+
+		if errs[i] != nil {
+			t.Fatalf("request %d: got error %v, want nil", i, errs[i])
+		}
+
+		if orders[i].OrderID != req.ClientOrderID {
+			t.Fatalf("request %d: got order ID %q, want %q", i, orders[i].OrderID, req.ClientOrderID)
+		}
+	}
+
+	if got := atomic.LoadInt32(&maxInFlight); got > concurrency {
+		t.Fatalf("got %d requests in flight, want at most %d", got, concurrency)
+	}
+}
+
+func TestCreateOrdersCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var requests int32
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&requests, 1)
+
+			// The first order holds the only slot until the batch is
+			// cancelled.
+			cancel()
+			<-req.Context().Done()
+
+			return nil, req.Context().Err()
+		}),
+	}
+
+	config := OrderConfig{MarketIOC: &MarketIOCConfig{QuoteSize: "10.00"}}
+	reqs := []OrderRequest{
+		{ClientOrderID: "a", Configuration: config},
+		{ClientOrderID: "b", Configuration: config},
+		{ClientOrderID: "c", Configuration: config},
+	}
+
+	orders, errs := client.CreateOrders(ctx, reqs, 1)
+	if len(orders) != len(reqs) || len(errs) != len(reqs) {
+		t.Fatalf("got %d orders and %d errors, want %d", len(orders), len(errs), len(reqs))
+	}
+
+	for i, err := range errs {
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("request %d: got error %v, want %v", i, err, context.Canceled)
+		}
+
+		if orders[i] != nil {
+			t.Fatalf("request %d: got order %v, want nil", i, orders[i])
+		}
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Fatalf("got %d requests, want 1", got)
+	}
+}
+
+func TestListOrders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response []byte
+		want     *Orders
+		err      error
+	}{
+		{
+			name: "nil",
+			err:  io.EOF, // end of file, nothing in response
+		},
+		{
+			name:     "empty slice",
+			response: []byte(`{}`),
+			want:     &Orders{Data: []HistoricalOrder{}},
+		},
+		{
+			name: "single",
+			response: []byte(`
+{
+  "orders": [{
+    "order_id": "0000-000000-000000",
+    "product_id": "BTC-USD",
+    "user_id": "2222-000000-000000",
+    "order_configuration": {
+      "limit_limit_gtc": {
+        "base_size": "0.001",
+        "limit_price": "10000.00",
+        "post_only": false
+      }
+    },
+    "side": "BUY",
+    "client_order_id": "11111-000000-000000",
+    "status": "OPEN",
+    "time_in_force": "GOOD_UNTIL_CANCELLED",
+    "created_time": "2021-05-31T09:59:59Z",
+    "order_type": "LIMIT",
+    "retail_portfolio_id": "3333-000000-000000"
+  }],
+  "sequence": "0",
+  "has_next": true,
+  "cursor": "789100"
+}`),
+			want: &Orders{
+				Data: []HistoricalOrder{
+					{
+						OrderID:   "0000-000000-000000",
+						ProductID: "BTC-USD",
+						UserID:    "2222-000000-000000",
+						OrderConfiguration: OrderConfig{
+							LimitGTC: &LimitGTCConfig{
+								BaseSize: "0.001",
+								Price:    "10000.00",
+							},
+						},
+						Side:          OrderSideBuy,
+						ClientOrderID: "11111-000000-000000",
+						Status:        OrderStatusOpen,
+						TimeInForce:   "GOOD_UNTIL_CANCELLED",
+						CreatedTime:   time.Date(2021, 5, 31, 9, 59, 59, 0, time.UTC),
+						OrderType:     "LIMIT",
+
+						RetailPortfolioID: "3333-000000-000000",
+					},
+				},
+				Sequence: "0",
+				HasNext:  true,
+				Cursor:   "789100",
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -262,7 +747,7 @@ func TestCreateOrder(t *testing.T) {
 				},
 			}
 
-			got, err := client.CreateOrder(context.Background(), OrderRequest{})
+			got, err := client.ListOrders(context.Background(), ListOrdersParams{})
 			if !errors.Is(err, test.err) {
 				t.Fatalf("got %v, want %v", err, test.err)
 			}
@@ -273,3 +758,1617 @@ func TestCreateOrder(t *testing.T) {
 		})
 	}
 }
+
+func TestListOrdersParams(t *testing.T) {
+	t.Parallel()
+
+	params := ListOrdersParams{
+		ProductID:   "BTC-USD",
+		OrderStatus: []OrderStatus{OrderStatusOpen, OrderStatusPending},
+		OrderSide:   OrderSideSell,
+		StartDate:   time.Date(2021, 5, 31, 9, 59, 59, 0, time.UTC),
+		Limit:       10,
+		Cursor:      "789100",
+
+		RetailPortfolioID: "3333-000000-000000",
+	}
+
+	want := "cursor=789100&limit=10&order_side=SELL&order_status=OPEN&order_status=PENDING" +
+		"&product_id=BTC-USD&retail_portfolio_id=3333-000000-000000&start_date=2021-05-31T09%3A59%3A59Z"
+
+	if got := params.query().Encode(); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestCancelOrders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response []byte
+		want     *CancelOrdersResult
+		err      error
+	}{
+		{
+			name: "nil",
+			err:  io.EOF, // end of file, nothing in response
+		},
+		{
+			name:     "empty slice",
+			response: []byte(`{}`),
+			want:     &CancelOrdersResult{},
+		},
+		{
+			name: "single",
+			response: []byte(`
+{
+  "results": [{
+    "success": true,
+    "failure_reason": "UNKNOWN_CANCEL_FAILURE_REASON",
+    "order_id": "0000-00000"
+  }]
+}`),
+			want: &CancelOrdersResult{
+				Results: []CancelOrderResult{
+					{
+						Success:       true,
+						FailureReason: "UNKNOWN_CANCEL_FAILURE_REASON",
+						OrderID:       "0000-00000",
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{
+				httpClient: &mockClient{
+					response:   test.response,
+					statusCode: http.StatusOK,
+				},
+			}
+
+			got, err := client.CancelOrders(context.Background(), []string{"0000-00000"})
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCancelOrdersFailed(t *testing.T) {
+	t.Parallel()
+
+	response := `
+{
+  "results": [
+    {"success": true, "order_id": "order-a"},
+    {"success": false, "failure_reason": "UNKNOWN_CANCEL_ORDER", "order_id": "order-b"},
+    {"success": true, "order_id": "order-c"},
+    {"success": false, "failure_reason": "COMMANDER_REJECTED_CANCEL_ORDER", "order_id": "order-d"}
+  ]
+}`
+
+	client := &Client{
+		httpClient: &mockClient{
+			response:   []byte(response),
+			statusCode: http.StatusOK,
+		},
+	}
+
+	got, err := client.CancelOrders(context.Background(), []string{"order-a", "order-b", "order-c", "order-d"})
+	if err != nil {
+		t.Fatalf("failed to cancel orders: %v", err)
+	}
+
+	if want := []string{"order-b", "order-d"}; !reflect.DeepEqual(got.Failed(), want) {
+		t.Fatalf("got failed %v, want %v", got.Failed(), want)
+	}
+
+	reasons := []CancelFailureReason{"", CancelFailureUnknownOrder, "", CancelFailureCommanderRejected}
+	for i, result := range got.Results {
+		if result.FailureReason != reasons[i] {
+			t.Fatalf("got failure reason %q for %s, want %q", result.FailureReason, result.OrderID, reasons[i])
+		}
+	}
+
+	if failed := (&CancelOrdersResult{}).Failed(); failed != nil {
+		t.Fatalf("got failed %v for no results, want nil", failed)
+	}
+}
+
+func TestCancelOrdersOlderThan(t *testing.T) {
+	t.Parallel()
+
+	stale := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339Nano)
+	fresh := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339Nano)
+
+	tests := []struct {
+		name      string
+		orders    string
+		cancelled []string
+	}{
+		{
+			name: "mixed",
+			orders: `{"orders": [
+				{"order_id": "stale-1", "status": "OPEN", "created_time": "` + stale + `"},
+				{"order_id": "fresh", "status": "OPEN", "created_time": "` + fresh + `"},
+				{"order_id": "no-time", "status": "OPEN"},
+				{"order_id": "stale-2", "status": "OPEN", "created_time": "` + stale + `"}
+			], "has_next": false}`,
+			cancelled: []string{"stale-1", "stale-2"},
+		},
+		{
+			name: "none stale",
+			orders: `{"orders": [
+				{"order_id": "fresh", "status": "OPEN", "created_time": "` + fresh + `"}
+			], "has_next": false}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var cancelled []string
+
+			client := &Client{
+				httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+					switch req.URL.Path {
+					case "/api/v3/brokerage/orders/historical/batch":
+						if got := req.URL.Query().Get("product_id"); got != "BTC-USD" {
+							t.Errorf("got product_id %q, want %q", got, "BTC-USD")
+						}
+
+						return newMockResponse(http.StatusOK, test.orders), nil
+					case "/api/v3/brokerage/orders/batch_cancel":
+						body := cancelOrdersRequest{}
+						if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+							return nil, err
+						}
+
+						cancelled = body.OrderIDs
+
+						return newMockResponse(http.StatusOK, `{"results": [
+							{"success": true, "order_id": "stale-1"},
+							{"success": true, "order_id": "stale-2"}
+						]}`), nil
+					}
+
+					t.Errorf("unexpected request path %q", req.URL.Path)
+
+					return newMockResponse(http.StatusNotFound, ""), nil
+				}),
+			}
+
+			result, err := client.CancelOrdersOlderThan(context.Background(), "BTC-USD", time.Hour)
+			if err != nil {
+				t.Fatalf("failed to cancel orders: %v", err)
+			}
+
+			if !reflect.DeepEqual(cancelled, test.cancelled) {
+				t.Fatalf("got cancelled order IDs %v, want %v", cancelled, test.cancelled)
+			}
+
+			if len(result.Results) != len(test.cancelled) {
+				t.Fatalf("got %d results, want %d", len(result.Results), len(test.cancelled))
+			}
+		})
+	}
+}
+
+func TestCancelByClientOrderID(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]string{
+		"": `{"orders": [
+			{"order_id": "order-a", "client_order_id": "client-a", "status": "OPEN"},
+			{"order_id": "order-x", "client_order_id": "client-x", "status": "OPEN"}
+		], "has_next": true, "cursor": "page-2"}`,
+		"page-2": `{"orders": [
+			{"order_id": "order-b", "client_order_id": "client-b", "status": "OPEN"}
+		], "has_next": false}`,
+	}
+
+	var cancelled []string
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			switch req.URL.Path {
+			case "/api/v3/brokerage/orders/historical/batch":
+				if got := req.URL.Query().Get("order_status"); got != string(OrderStatusOpen) {
+					t.Errorf("got order_status %q, want %q", got, OrderStatusOpen)
+				}
+
+				return newMockResponse(http.StatusOK, pages[req.URL.Query().Get("cursor")]), nil
+			case "/api/v3/brokerage/orders/batch_cancel":
+				body := cancelOrdersRequest{}
+				if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+					return nil, err
+				}
+
+				cancelled = body.OrderIDs
+
+				return newMockResponse(http.StatusOK, `{"results": [
+					{"success": true, "order_id": "order-a"},
+					{"success": false, "failure_reason": "COMMANDER_REJECTED_CANCEL_ORDER", "order_id": "order-b"}
+				]}`), nil
+			}
+
+			t.Errorf("unexpected request path %q", req.URL.Path)
+
+			return newMockResponse(http.StatusNotFound, ""), nil
+		}),
+	}
+
+	ids := []string{"client-a", "client-b", "client-missing", "client-a"}
+
+	got, err := client.CancelByClientOrderID(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("failed to cancel orders: %v", err)
+	}
+
+	if want := []string{"order-a", "order-b"}; !reflect.DeepEqual(cancelled, want) {
+		t.Fatalf("got cancelled order IDs %v, want %v", cancelled, want)
+	}
+
+	want := map[string]CancelOrderResult{
+		"client-a": {
+			Success:       true,
+			OrderID:       "order-a",
+			ClientOrderID: "client-a",
+		},
+		"client-b": {
+			FailureReason: "COMMANDER_REJECTED_CANCEL_ORDER",
+			OrderID:       "order-b",
+			ClientOrderID: "client-b",
+		},
+		"client-missing": {
+			FailureReason: CancelFailureUnknownOrder,
+			ClientOrderID: "client-missing",
+		},
+	}
+
+	if byID := got.ByClientOrderID(); !reflect.DeepEqual(byID, want) {
+		t.Fatalf("got %v, want %v", byID, want)
+	}
+
+	if failed := got.Failed(); !reflect.DeepEqual(failed, []string{"order-b"}) {
+		t.Fatalf("got failed %v, want [order-b]", failed)
+	}
+
+	_, err = client.CancelByClientOrderID(context.Background(), []string{"client-a", ""})
+	if !errors.Is(err, ErrInvalidClientOrderID) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidClientOrderID)
+	}
+}
+
+func TestStatusCodes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("created", func(t *testing.T) {
+		t.Parallel()
+
+		client := &Client{
+			httpClient: &mockClient{
+				response:   []byte(`{"accounts": [{"uuid": "a"}]}`),
+				statusCode: http.StatusCreated,
+			},
+		}
+
+		accounts, err := client.Accounts(context.Background())
+		if err != nil {
+			t.Fatalf("failed to list accounts: %v", err)
+		}
+
+		if len(accounts.Data) != 1 || accounts.Data[0].UUID != "a" {
+			t.Fatalf("got accounts %+v", accounts)
+		}
+	})
+
+	t.Run("no content", func(t *testing.T) {
+		t.Parallel()
+
+		client := &Client{
+			httpClient: &mockClient{statusCode: http.StatusNoContent},
+		}
+
+		out := &Accounts{}
+		if err := client.do(context.Background(), http.MethodDelete, []string{"path"}, nil, nil, out, nil); err != nil {
+			t.Fatalf("failed to handle no content: %v", err)
+		}
+
+		if !reflect.DeepEqual(out, &Accounts{}) {
+			t.Fatalf("got %+v, want an empty result", out)
+		}
+	})
+
+	t.Run("unprocessable entity", func(t *testing.T) {
+		t.Parallel()
+
+		client := &Client{
+			httpClient: &mockClient{
+				response:   []byte(`{"error": "INVALID_ARGUMENT", "message": "limit price too low"}`),
+				statusCode: http.StatusUnprocessableEntity,
+			},
+		}
+
+		_, err := client.Accounts(context.Background())
+		if !errors.Is(err, ErrStatusNotOK) {
+			t.Fatalf("got %v, want %v", err, ErrStatusNotOK)
+		}
+
+		statusErr := &StatusError{}
+		if !errors.As(err, &statusErr) {
+			t.Fatalf("got %T, want %T", err, statusErr)
+		}
+
+		want := &ErrorResponse{Error: "INVALID_ARGUMENT", Message: "limit price too low"}
+		if statusErr.StatusCode != http.StatusUnprocessableEntity || !reflect.DeepEqual(statusErr.Response, want) {
+			t.Fatalf("got status %d and response %+v, want %d and %+v",
+				statusErr.StatusCode, statusErr.Response, http.StatusUnprocessableEntity, want)
+		}
+	})
+
+	t.Run("non-JSON error body", func(t *testing.T) {
+		t.Parallel()
+
+		client := &Client{
+			httpClient: &mockClient{
+				response:   []byte(`<html>bad gateway</html>`),
+				statusCode: http.StatusBadGateway,
+			},
+		}
+
+		_, err := client.Accounts(context.Background())
+
+		statusErr := &StatusError{}
+		if !errors.As(err, &statusErr) || statusErr.Response != nil {
+			t.Fatalf("got %v, want a status error without a response", err)
+		}
+	})
+}
+
+func TestNewStatusError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		err        error
+		hint       bool
+	}{
+		{
+			name:       "bad request",
+			statusCode: http.StatusBadRequest,
+			body:       `{"error":"INVALID_ARGUMENT"}`,
+			err:        ErrStatusNotOK,
+		},
+		{
+			name:       "unauthorized",
+			statusCode: http.StatusUnauthorized,
+			body:       `{"error":"unauthorized","message":"invalid api key"}`,
+			err:        ErrUnauthorized,
+		},
+		{
+			name:       "unauthorized timestamp",
+			statusCode: http.StatusUnauthorized,
+			body:       `{"error":"unauthorized","message":"Invalid Timestamp"}`,
+			err:        ErrUnauthorized,
+			hint:       true,
+		},
+		{
+			name:       "too many requests",
+			statusCode: http.StatusTooManyRequests,
+			body:       `{"error":"RATE_LIMIT_EXCEEDED","message":"Too many requests"}`,
+			err:        ErrRateLimited,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{
+				httpClient: &mockClient{
+					response:   []byte(test.body),
+					statusCode: test.statusCode,
+				},
+			}
+
+			_, err := client.Accounts(context.Background())
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if !errors.Is(err, ErrStatusNotOK) {
+				t.Fatalf("got %v, want %v", err, ErrStatusNotOK)
+			}
+
+			if !strings.Contains(err.Error(), test.body) {
+				t.Fatalf("got %q, want body %q", err, test.body)
+			}
+
+			if got := strings.Contains(err.Error(), clockSkewHint); got != test.hint {
+				t.Fatalf("got hint %t, want %t", got, test.hint)
+			}
+		})
+	}
+}
+
+func TestStatusErrorRateLimited(t *testing.T) {
+	t.Parallel()
+
+	body := `{"error":"RATE_LIMIT_EXCEEDED","message":"Too many requests",` +
+		`"error_details":"private endpoints are limited to 30 requests per second"}`
+
+	client := &Client{
+		httpClient: mockDoFunc(func(*http.Request) (*http.Response, error) {
+			resp := newMockResponse(http.StatusTooManyRequests, body)
+			resp.Header = http.Header{"Retry-After": []string{"5"}}
+
+			return resp, nil
+		}),
+	}
+
+	_, err := client.Accounts(context.Background())
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("got %v, want %v", err, ErrRateLimited)
+	}
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("got %T, want *StatusError", err)
+	}
+
+	if statusErr.RetryAfter != 5*time.Second {
+		t.Fatalf("got retry after %v, want %v", statusErr.RetryAfter, 5*time.Second)
+	}
+
+	want := &ErrorResponse{
+		Error:        "RATE_LIMIT_EXCEEDED",
+		Message:      "Too many requests",
+		ErrorDetails: "private endpoints are limited to 30 requests per second",
+	}
+	if !reflect.DeepEqual(statusErr.Response, want) {
+		t.Fatalf("got response %+v, want %+v", statusErr.Response, want)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "missing", value: "", want: 0},
+		{name: "seconds", value: "30", want: 30 * time.Second},
+		{name: "date", value: "Thu, 01 Jun 2023 12:00:10 GMT", want: 10 * time.Second},
+		{name: "past date", value: "Thu, 01 Jun 2023 11:59:00 GMT", want: 0},
+		{name: "negative seconds", value: "-1", want: 0},
+		{name: "malformed", value: "soon", want: 0},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := parseRetryAfter(test.value, now); got != test.want {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGetOrder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response []byte
+		want     *HistoricalOrder
+		err      error
+	}{
+		{
+			name: "nil",
+			err:  io.EOF, // end of file, nothing in response
+		},
+		{
+			name:     "empty",
+			response: []byte(`{}`),
+			want:     &HistoricalOrder{},
+		},
+		{
+			name: "single",
+			response: []byte(`
+{
+  "order": {
+    "order_id": "0000-000000-000000",
+    "product_id": "BTC-USD",
+    "side": "SELL",
+    "client_order_id": "11111-000000-000000",
+    "status": "FILLED",
+    "created_time": "2021-05-31T09:59:59Z"
+  }
+}`),
+			want: &HistoricalOrder{
+				OrderID:       "0000-000000-000000",
+				ProductID:     "BTC-USD",
+				Side:          OrderSideSell,
+				ClientOrderID: "11111-000000-000000",
+				Status:        OrderStatusFilled,
+				CreatedTime:   time.Date(2021, 5, 31, 9, 59, 59, 0, time.UTC),
+			},
+		},
+		{
+			name: "edit history",
+			response: []byte(`
+{
+  "order": {
+    "order_id": "0000-000000-000000",
+    "status": "OPEN",
+    "edit_history": [
+      {
+        "price": "10000.00",
+        "size": "0.001",
+        "replace_accept_timestamp": "2021-05-31T09:59:59Z"
+      },
+      {
+        "price": "10500.00",
+        "size": "0.002",
+        "replace_accept_timestamp": "2021-05-31T10:59:59Z"
+      }
+    ]
+  }
+}`),
+			want: &HistoricalOrder{
+				OrderID: "0000-000000-000000",
+				Status:  OrderStatusOpen,
+				EditHistory: []EditHistoryEntry{
+					{
+						Price:                  "10000.00",
+						Size:                   "0.001",
+						ReplaceAcceptTimestamp: time.Date(2021, 5, 31, 9, 59, 59, 0, time.UTC),
+					},
+					{
+						Price:                  "10500.00",
+						Size:                   "0.002",
+						ReplaceAcceptTimestamp: time.Date(2021, 5, 31, 10, 59, 59, 0, time.UTC),
+					},
+				},
+			},
+		},
+		{
+			name: "one-cancels-other",
+			response: []byte(`
+{
+  "order": {
+    "order_id": "1111-000000-000000",
+    "status": "OPEN",
+    "attached_order_configuration": {
+      "stop_limit_stop_limit_gtc": {
+        "base_size": "0.001",
+        "limit_price": "9000.00",
+        "stop_price": "9100.00",
+        "stop_direction": "STOP_DIRECTION_STOP_DOWN"
+      }
+    },
+    "attached_order_id": "2222-000000-000000",
+    "originating_order_id": "0000-000000-000000"
+  }
+}`),
+			want: &HistoricalOrder{
+				OrderID: "1111-000000-000000",
+				Status:  OrderStatusOpen,
+				AttachedOrderConfiguration: &OrderConfig{
+					StopLimitGTC: &StopLimitGTCConfig{
+						BaseSize:      "0.001",
+						LimitPrice:    "9000.00",
+						StopPrice:     "9100.00",
+						StopDirection: "STOP_DIRECTION_STOP_DOWN",
+					},
+				},
+				AttachedOrderID:    "2222-000000-000000",
+				OriginatingOrderID: "0000-000000-000000",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{
+				httpClient: &mockClient{
+					response:   test.response,
+					statusCode: http.StatusOK,
+				},
+			}
+
+			got, err := client.GetOrder(context.Background(), "0000-000000-000000")
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPrimaryFiatAccount(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]string{
+		"": `{"accounts": [
+			{"uuid": "btc", "currency": "BTC", "default": true},
+			{"uuid": "usd-secondary", "currency": "USD", "default": false}
+		], "has_next": true, "cursor": "page-2"}`,
+		"page-2": `{"accounts": [
+			{"uuid": "usd-primary", "currency": "USD", "default": true}
+		], "has_next": false}`,
+	}
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			return newMockResponse(http.StatusOK, pages[req.URL.Query().Get("cursor")]), nil
+		}),
+	}
+
+	account, err := client.PrimaryFiatAccount(context.Background(), "usd")
+	if err != nil {
+		t.Fatalf("failed to get primary fiat account: %v", err)
+	}
+
+	if account.UUID != "usd-primary" {
+		t.Fatalf("got account %q, want %q", account.UUID, "usd-primary")
+	}
+
+	if _, err := client.PrimaryFiatAccount(context.Background(), "EUR"); !errors.Is(err, ErrAccountNotFound) {
+		t.Fatalf("got %v, want %v", err, ErrAccountNotFound)
+	}
+}
+
+func TestEnsureAccount(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]string{
+		"": `{"accounts": [
+			{"uuid": "eth-1", "currency": "ETH", "default": false},
+			{"uuid": "sol-1", "currency": "SOL", "default": false}
+		], "has_next": true, "cursor": "page-2"}`,
+		"page-2": `{"accounts": [
+			{"uuid": "eth-2", "currency": "ETH", "default": true},
+			{"uuid": "sol-2", "currency": "SOL", "default": false}
+		], "has_next": false}`,
+	}
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method != http.MethodGet {
+				t.Errorf("got %s request, want only %s", req.Method, http.MethodGet)
+			}
+
+			return newMockResponse(http.StatusOK, pages[req.URL.Query().Get("cursor")]), nil
+		}),
+	}
+
+	tests := []struct {
+		name     string
+		currency string
+		want     string
+		err      error
+	}{
+		{name: "default", currency: "eth", want: "eth-2"},
+		{name: "first", currency: "SOL", want: "sol-1"},
+		{name: "missing", currency: "ADA", err: ErrAccountCreationUnsupported},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			account, err := client.EnsureAccount(context.Background(), test.currency)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if test.err != nil {
+				if !errors.Is(err, ErrAccountNotFound) {
+					t.Fatalf("got %v, want it to wrap %v", err, ErrAccountNotFound)
+				}
+
+				return
+			}
+
+			if account.UUID != test.want {
+				t.Fatalf("got account %q, want %q", account.UUID, test.want)
+			}
+		})
+	}
+}
+
+func TestHistoricalOrderFilledFraction(t *testing.T) {
+	t.Parallel()
+
+	limit := OrderConfig{LimitGTC: &LimitGTCConfig{BaseSize: "2", Price: "100"}}
+
+	tests := []struct {
+		name    string
+		order   HistoricalOrder
+		want    string
+		partial bool
+	}{
+		{
+			name:  "unfilled",
+			order: HistoricalOrder{OrderConfiguration: limit},
+			want:  "0",
+		},
+		{
+			name:    "partial",
+			order:   HistoricalOrder{OrderConfiguration: limit, ExecutionStats: ExecutionStats{FilledSize: "0.5"}},
+			want:    "0.25",
+			partial: true,
+		},
+		{
+			name:  "filled",
+			order: HistoricalOrder{OrderConfiguration: limit, ExecutionStats: ExecutionStats{FilledSize: "2"}},
+			want:  "1",
+		},
+		{
+			name: "market quote size",
+			order: HistoricalOrder{
+				OrderConfiguration: OrderConfig{MarketIOC: &MarketIOCConfig{QuoteSize: "10"}},
+				ExecutionStats:     ExecutionStats{FilledSize: "0.0001", FilledValue: "7.5"},
+			},
+			want:    "0.75",
+			partial: true,
+		},
+		{
+			name: "zero size",
+			order: HistoricalOrder{
+				OrderConfiguration: OrderConfig{LimitGTC: &LimitGTCConfig{BaseSize: "0", Price: "100"}},
+				ExecutionStats:     ExecutionStats{FilledSize: "1"},
+			},
+			want: "0",
+		},
+		{
+			name:  "no configuration",
+			order: HistoricalOrder{ExecutionStats: ExecutionStats{FilledSize: "1"}},
+			want:  "0",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := test.order.FilledFraction()
+			if err != nil {
+				t.Fatalf("failed to get filled fraction: %v", err)
+			}
+
+			if got.String() != test.want {
+				t.Fatalf("got fraction %s, want %s", got, test.want)
+			}
+
+			if partial := test.order.IsPartiallyFilled(); partial != test.partial {
+				t.Fatalf("got partially filled %v, want %v", partial, test.partial)
+			}
+		})
+	}
+}
+
+func TestGetOrderByClientID(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]string{
+		"": `{"orders": [
+			{"order_id": "order-1", "client_order_id": "mine-1"},
+			{"order_id": "order-2", "client_order_id": "mine-2"}
+		], "has_next": true, "cursor": "page-2"}`,
+		"page-2": `{"orders": [
+			{"order_id": "order-3", "client_order_id": "mine-3"},
+			{"order_id": "order-4", "client_order_id": "mine-2"},
+			{"order_id": "order-5"}
+		], "has_next": false}`,
+	}
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			return newMockResponse(http.StatusOK, pages[req.URL.Query().Get("cursor")]), nil
+		}),
+	}
+
+	tests := []struct {
+		name          string
+		clientOrderID string
+		want          string
+		err           error
+	}{
+		{name: "found", clientOrderID: "mine-3", want: "order-3"},
+		{name: "not found", clientOrderID: "mine-4", err: ErrOrderNotFound},
+		{name: "ambiguous", clientOrderID: "mine-2", err: ErrAmbiguousClientOrderID},
+		{name: "empty", clientOrderID: "", err: ErrInvalidClientOrderID},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			order, err := client.GetOrderByClientID(context.Background(), test.clientOrderID)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if test.err != nil {
+				return
+			}
+
+			if order.OrderID != test.want {
+				t.Fatalf("got order %q, want %q", order.OrderID, test.want)
+			}
+		})
+	}
+}
+
+func TestEmptyListResults(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{
+		httpClient: &mockClient{response: []byte(`{}`), statusCode: http.StatusOK},
+	}
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		list func() (any, int, error)
+	}{
+		{
+			name: "accounts",
+			list: func() (any, int, error) {
+				accounts, err := client.ListAccounts(ctx, ListAccountsParams{})
+				if err != nil {
+					return nil, 0, err
+				}
+
+				return accounts.Data, len(accounts.Data), nil
+			},
+		},
+		{
+			name: "orders",
+			list: func() (any, int, error) {
+				orders, err := client.ListOrders(ctx, ListOrdersParams{})
+				if err != nil {
+					return nil, 0, err
+				}
+
+				return orders.Data, len(orders.Data), nil
+			},
+		},
+		{
+			name: "fills",
+			list: func() (any, int, error) {
+				fills, err := client.ListFills(ctx, ListFillsParams{})
+				if err != nil {
+					return nil, 0, err
+				}
+
+				return fills.Data, len(fills.Data), nil
+			},
+		},
+		{
+			name: "products",
+			list: func() (any, int, error) {
+				products, err := client.ListProducts(ctx, ListProductsParams{})
+				if err != nil {
+					return nil, 0, err
+				}
+
+				return products.Data, len(products.Data), nil
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, n, err := test.list()
+			if err != nil {
+				t.Fatalf("failed to list %s: %v", test.name, err)
+			}
+
+			if n != 0 {
+				t.Fatalf("got %d %s, want none", n, test.name)
+			}
+
+			if reflect.ValueOf(data).IsNil() {
+				t.Fatalf("got nil %s, want an empty slice", test.name)
+			}
+		})
+	}
+}
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+
+	type example struct {
+		Value string `json:"value"`
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		out    any
+		want   any
+		err    error
+	}{
+		{
+			name:   "decodes",
+			path:   "brokerage/example",
+			status: http.StatusOK,
+			out:    &example{},
+			want:   &example{Value: "ok"},
+		},
+		{
+			name:   "leading slash",
+			path:   "/brokerage/example",
+			status: http.StatusOK,
+			out:    &example{},
+			want:   &example{Value: "ok"},
+		},
+		{
+			name:   "nil out",
+			path:   "brokerage/example",
+			status: http.StatusOK,
+		},
+		{
+			name:   "status error",
+			path:   "brokerage/example",
+			status: http.StatusNotFound,
+			out:    &example{},
+			want:   &example{},
+			err:    ErrStatusNotOK,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{
+				httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+					if req.Method != http.MethodPost {
+						t.Errorf("got method %s, want %s", req.Method, http.MethodPost)
+					}
+
+					if got, want := req.URL.String(), api+"/brokerage/example?limit=1"; got != want {
+						t.Errorf("got URL %s, want %s", got, want)
+					}
+
+					body, _ := io.ReadAll(req.Body)
+					if got, want := string(body), `{"value":"in"}`; got != want {
+						t.Errorf("got body %s, want %s", got, want)
+					}
+
+					return newMockResponse(test.status, `{"value": "ok"}`), nil
+				}),
+			}
+
+			query := url.Values{"limit": {"1"}}
+
+			err := client.Do(context.Background(), http.MethodPost, test.path, query, example{Value: "in"}, test.out)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if !reflect.DeepEqual(test.out, test.want) {
+				t.Fatalf("got %+v, want %+v", test.out, test.want)
+			}
+		})
+	}
+}
+
+func TestParseOrderSide(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		side string
+		want OrderSide
+		err  error
+	}{
+		{side: "BUY", want: OrderSideBuy},
+		{side: "buy", want: OrderSideBuy},
+		{side: " Buy ", want: OrderSideBuy},
+		{side: "SELL", want: OrderSideSell},
+		{side: "sell", want: OrderSideSell},
+		{side: "Sell", want: OrderSideSell},
+		{side: "", err: ErrInvalidOrderSide},
+		{side: "short", err: ErrInvalidOrderSide},
+		{side: "UNKNOWN_ORDER_SIDE", err: ErrInvalidOrderSide},
+	}
+
+	for _, test := range tests {
+		got, err := ParseOrderSide(test.side)
+		if !errors.Is(err, test.err) {
+			t.Fatalf("%q: got %v, want %v", test.side, err, test.err)
+		}
+
+		if got != test.want {
+			t.Fatalf("%q: got %q, want %q", test.side, got, test.want)
+		}
+	}
+}
+
+func TestOrderRequestAttachedOrderConfiguration(t *testing.T) {
+	t.Parallel()
+
+	entry := OrderConfig{LimitGTC: &LimitGTCConfig{BaseSize: "0.001", Price: "10000.00"}}
+
+	body, err := json.Marshal(OrderRequest{Configuration: entry})
+	if err != nil {
+		t.Fatalf("failed to marshal order request: %v", err)
+	}
+
+	if strings.Contains(string(body), "attached_order_configuration") {
+		t.Fatalf("got attached order configuration in %s, want it omitted", body)
+	}
+
+	stop := &OrderConfig{
+		StopLimitGTC: &StopLimitGTCConfig{
+			BaseSize:      "0.001",
+			LimitPrice:    "9000.00",
+			StopPrice:     "9500.00",
+			StopDirection: StopDirDown,
+		},
+	}
+
+	body, err = json.Marshal(OrderRequest{Configuration: entry, AttachedOrderConfiguration: stop})
+	if err != nil {
+		t.Fatalf("failed to marshal order request: %v", err)
+	}
+
+	want := `"attached_order_configuration":{"stop_limit_stop_limit_gtc":{"base_size":"0.001",` +
+		`"limit_price":"9000.00","stop_price":"9500.00","stop_direction":"STOP_DIRECTION_STOP_DOWN"}}`
+	if !strings.Contains(string(body), want) {
+		t.Fatalf("got %s, want it to contain %s", body, want)
+	}
+
+	invalid := OrderRequest{Configuration: entry, AttachedOrderConfiguration: &OrderConfig{}}
+	if err := invalid.Validate(); !errors.Is(err, ErrInvalidOrderConfig) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidOrderConfig)
+	}
+
+	order := &HistoricalOrder{}
+
+	err = json.Unmarshal([]byte(`
+{
+  "order_id": "0000-000000-000000",
+  "order_configuration": {
+    "limit_limit_gtc": {
+      "base_size": "0.001",
+      "limit_price": "10000.00",
+      "post_only": false
+    }
+  },
+  "attached_order_configuration": {
+    "stop_limit_stop_limit_gtc": {
+      "base_size": "0.001",
+      "limit_price": "9000.00",
+      "stop_price": "9500.00",
+      "stop_direction": "STOP_DIRECTION_STOP_DOWN"
+    }
+  }
+}`), order)
+	if err != nil {
+		t.Fatalf("failed to decode order: %v", err)
+	}
+
+	if !reflect.DeepEqual(order.OrderConfiguration, entry) {
+		t.Fatalf("got order configuration %+v, want %+v", order.OrderConfiguration, entry)
+	}
+
+	if !reflect.DeepEqual(order.AttachedOrderConfiguration, stop) {
+		t.Fatalf("got attached order configuration %+v, want %+v", order.AttachedOrderConfiguration, stop)
+	}
+}
+
+func TestOrderRequestValidateMarketSide(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		side   OrderSide
+		market MarketIOCConfig
+		want   string // empty means valid
+	}{
+		{
+			name:   "buy without quote size",
+			side:   OrderSideBuy,
+			market: MarketIOCConfig{BaseSize: "0.001"},
+			want:   "invalid order configuration: market BUY requires quote_size",
+		},
+		{
+			name:   "sell without base size",
+			side:   OrderSideSell,
+			market: MarketIOCConfig{QuoteSize: "10.00"},
+			want:   "invalid order configuration: market SELL requires base_size",
+		},
+		{
+			name:   "buy",
+			side:   OrderSideBuy,
+			market: MarketIOCConfig{QuoteSize: "10.00"},
+		},
+		{
+			name:   "sell",
+			side:   OrderSideSell,
+			market: MarketIOCConfig{BaseSize: "0.001"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			market := test.market
+			req := OrderRequest{
+				ClientOrderID: "0000-00000-000000",
+				ProductID:     "BTC-USD",
+				Side:          test.side,
+				Configuration: OrderConfig{MarketIOC: &market},
+			}
+
+			err := req.Validate()
+			if test.want == "" {
+				if err != nil {
+					t.Fatalf("got %v, want no error", err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, ErrInvalidOrderConfig) || err.Error() != test.want {
+				t.Fatalf("got %v, want %q", err, test.want)
+			}
+		})
+	}
+}
+
+func TestOrderRequestValidateClientOrderID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		clientOrderID string
+		err           error
+	}{
+		{name: "uuid", clientOrderID: NewClientOrderID()},
+		{name: "letters digits and separators", clientOrderID: "grid_BTC-USD_42"},
+		{name: "longest", clientOrderID: strings.Repeat("a", 128)},
+		{name: "too long", clientOrderID: strings.Repeat("a", 129), err: ErrInvalidClientOrderID},
+		{name: "space", clientOrderID: "my order", err: ErrInvalidClientOrderID},
+		{name: "punctuation", clientOrderID: "order#1", err: ErrInvalidClientOrderID},
+		{name: "non-ascii", clientOrderID: "ordér", err: ErrInvalidClientOrderID},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := OrderRequest{
+				ClientOrderID: test.clientOrderID,
+				ProductID:     "BTC-USD",
+				Configuration: OrderConfig{MarketIOC: &MarketIOCConfig{QuoteSize: "10.00"}},
+			}
+
+			if err := req.Validate(); !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestOrderConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config OrderConfig
+		err    error
+	}{
+		{
+			name:   "zero variants",
+			config: OrderConfig{},
+			err:    ErrInvalidOrderConfig,
+		},
+		{
+			name: "one variant",
+			config: OrderConfig{
+				LimitGTC: &LimitGTCConfig{BaseSize: "0.001", Price: "10000.00"},
+			},
+		},
+		{
+			name: "two variants",
+			config: OrderConfig{
+				MarketIOC: &MarketIOCConfig{QuoteSize: "10.00"},
+				LimitGTC:  &LimitGTCConfig{BaseSize: "0.001", Price: "10000.00"},
+			},
+			err: ErrInvalidOrderConfig,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := test.config.Validate(); !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			sent := false
+
+			client := &Client{
+				httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+					sent = true
+
+					return newMockResponse(http.StatusOK, `{"success": true}`), nil
+				}),
+			}
+
+			_, err := client.CreateOrder(context.Background(), OrderRequest{Configuration: test.config})
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if want := test.err == nil; sent != want {
+				t.Fatalf("got request sent %t, want %t", sent, want)
+			}
+		})
+	}
+}
+
+func TestOrderConfigPriceSize(t *testing.T) {
+	t.Parallel()
+
+	endTime := time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		config string
+		want   OrderConfig
+		price  string
+		size   string
+	}{
+		{
+			name:   "market quote size",
+			config: `{"market_market_ioc": {"quote_size": "100"}}`,
+			want:   OrderConfig{MarketIOC: &MarketIOCConfig{QuoteSize: "100"}},
+			size:   "100",
+		},
+		{
+			name:   "market base size",
+			config: `{"market_market_ioc": {"base_size": "0.5"}}`,
+			want:   OrderConfig{MarketIOC: &MarketIOCConfig{BaseSize: "0.5"}},
+			size:   "0.5",
+		},
+		{
+			name:   "limit GTC",
+			config: `{"limit_limit_gtc": {"base_size": "0.5", "limit_price": "30000", "post_only": true}}`,
+			want:   OrderConfig{LimitGTC: &LimitGTCConfig{BaseSize: "0.5", Price: "30000", PostOnly: true}},
+			price:  "30000",
+			size:   "0.5",
+		},
+		{
+			name: "limit GTD",
+			config: `{"limit_limit_gtd": {"base_size": "0.5", "limit_price": "30000",
+				"end_time": "2023-07-01T00:00:00Z", "post_only": false}}`,
+			want:  OrderConfig{LimitGTD: &LimitGTDConfig{BaseSize: "0.5", Price: "30000", EndTime: endTime}},
+			price: "30000",
+			size:  "0.5",
+		},
+		{
+			name: "stop-limit GTC",
+			config: `{"stop_limit_stop_limit_gtc": {"base_size": "0.5", "limit_price": "29000",
+				"stop_price": "29500", "stop_direction": "STOP_DIRECTION_STOP_DOWN"}}`,
+			want: OrderConfig{StopLimitGTC: &StopLimitGTCConfig{
+				BaseSize: "0.5", LimitPrice: "29000", StopPrice: "29500", StopDirection: StopDirDown,
+			}},
+			price: "29000",
+			size:  "0.5",
+		},
+		{
+			name: "stop-limit GTD",
+			config: `{"stop_limit_stop_limit_gtd": {"base_size": "0.5", "limit_price": "31000",
+				"stop_price": "30500", "stop_direction": "STOP_DIRECTION_STOP_UP",
+				"end_time": "2023-07-01T00:00:00Z"}}`,
+			want: OrderConfig{StopLimitGTD: &StopLimitGTDConfig{
+				BaseSize: "0.5", LimitPrice: "31000", StopPrice: "30500", StopDirection: StopDirUp, EndTime: endTime,
+			}},
+			price: "31000",
+			size:  "0.5",
+		},
+		{
+			name:   "no variant",
+			config: `{}`,
+			want:   OrderConfig{},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{
+				httpClient: &mockClient{
+					response:   []byte(`{"order": {"order_id": "a", "order_configuration": ` + test.config + `}}`),
+					statusCode: http.StatusOK,
+				},
+			}
+
+			order, err := client.GetOrder(context.Background(), "a")
+			if err != nil {
+				t.Fatalf("failed to get order: %v", err)
+			}
+
+			if !reflect.DeepEqual(order.OrderConfiguration, test.want) {
+				t.Fatalf("got configuration %+v, want %+v", order.OrderConfiguration, test.want)
+			}
+
+			price, size := order.OrderConfiguration.PriceSize()
+			if price != test.price || size != test.size {
+				t.Fatalf("got price %q and size %q, want %q and %q", price, size, test.price, test.size)
+			}
+		})
+	}
+}
+
+func TestOrderConfigNotional(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config OrderConfig
+		want   string
+		err    error
+	}{
+		{
+			name:   "market quote size",
+			config: OrderConfig{MarketIOC: &MarketIOCConfig{QuoteSize: "100.50"}},
+			want:   "100.5",
+		},
+		{
+			name:   "market base size",
+			config: OrderConfig{MarketIOC: &MarketIOCConfig{BaseSize: "0.5"}},
+			err:    ErrMissingPrice,
+		},
+		{
+			name:   "limit GTC",
+			config: OrderConfig{LimitGTC: &LimitGTCConfig{BaseSize: "0.5", Price: "30000"}},
+			want:   "15000",
+		},
+		{
+			name:   "limit GTD",
+			config: OrderConfig{LimitGTD: &LimitGTDConfig{BaseSize: "0.25", Price: "30000.10"}},
+			want:   "7500.025",
+		},
+		{
+			name:   "stop-limit GTC",
+			config: OrderConfig{StopLimitGTC: &StopLimitGTCConfig{BaseSize: "2", LimitPrice: "29000", StopPrice: "29500"}},
+			want:   "58000",
+		},
+		{
+			name:   "stop-limit GTD",
+			config: OrderConfig{StopLimitGTD: &StopLimitGTDConfig{BaseSize: "0.1", LimitPrice: "31000", StopPrice: "30500"}},
+			want:   "3100",
+		},
+		{
+			name:   "no variant",
+			config: OrderConfig{},
+			err:    ErrInvalidOrderConfig,
+		},
+		{
+			name: "several variants",
+			config: OrderConfig{
+				MarketIOC: &MarketIOCConfig{QuoteSize: "100"},
+				LimitGTC:  &LimitGTCConfig{BaseSize: "0.5", Price: "30000"},
+			},
+			err: ErrInvalidOrderConfig,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			notional, err := test.config.Notional()
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got error %v, want %v", err, test.err)
+			}
+
+			if test.err != nil {
+				return
+			}
+
+			if notional.String() != test.want {
+				t.Fatalf("got notional %s, want %s", notional, test.want)
+			}
+		})
+	}
+}
+
+func TestOrderConfigNotionalMalformed(t *testing.T) {
+	t.Parallel()
+
+	for _, config := range []OrderConfig{
+		{LimitGTC: &LimitGTCConfig{BaseSize: "0.5", Price: "thirty"}},
+		{LimitGTC: &LimitGTCConfig{BaseSize: "", Price: "30000"}},
+		{MarketIOC: &MarketIOCConfig{QuoteSize: "a lot"}},
+	} {
+		if _, err := config.Notional(); err == nil {
+			t.Fatalf("got no error for configuration %+v", config)
+		}
+	}
+}
+
+func TestAccountTypeUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		json string
+		want AccountType
+	}{
+		{json: `"ACCOUNT_TYPE_UNSPECIFIED"`, want: AccountTypeUnspecified},
+		{json: `"ACCOUNT_TYPE_CRYPTO"`, want: AccountTypeCrypto},
+		{json: `"ACCOUNT_TYPE_FIAT"`, want: AccountTypeFiat},
+		{json: `"ACCOUNT_TYPE_VAULT"`, want: AccountTypeVault},
+		{json: `"ACCOUNT_TYPE_SOMETHING_NEW"`, want: AccountTypeUnspecified},
+		{json: `""`, want: AccountTypeUnspecified},
+		{json: `null`, want: AccountTypeUnspecified},
+	}
+
+	for _, test := range tests {
+		account := Account{}
+		if err := json.Unmarshal([]byte(`{"type": `+test.json+`}`), &account); err != nil {
+			t.Fatalf("%s: failed to decode account: %v", test.json, err)
+		}
+
+		if account.Type != test.want {
+			t.Fatalf("%s: got %q, want %q", test.json, account.Type, test.want)
+		}
+	}
+
+	account := Account{}
+	if err := json.Unmarshal([]byte(`{"type": 1}`), &account); err == nil {
+		t.Fatalf("got no error decoding a numeric account type")
+	}
+}
+
+func TestNewOrderFailureReasonUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		json      string
+		want      NewOrderFailureReason
+		retryable bool
+	}{
+		{json: `"INSUFFICIENT_FUND"`, want: NewOrderFailureInsufficientFund},
+		{json: `"INVALID_LIMIT_PRICE_POST_ONLY"`, want: NewOrderFailureInvalidLimitPricePostOnly},
+		{json: `"INVALID_NO_LIQUIDITY"`, want: NewOrderFailureInvalidNoLiquidity, retryable: true},
+		{json: `"ORDER_ENTRY_DISABLED"`, want: NewOrderFailureOrderEntryDisabled, retryable: true},
+		{json: `"UNKNOWN_FAILURE_REASON"`, want: NewOrderFailureUnknown},
+		{json: `"SOMETHING_NEW"`, want: NewOrderFailureUnknown},
+		{json: `""`, want: ""},
+		{json: `null`, want: ""},
+	}
+
+	for _, test := range tests {
+		errResp := ErrorResponse{}
+		if err := json.Unmarshal([]byte(`{"new_order_failure_reason": `+test.json+`}`), &errResp); err != nil {
+			t.Fatalf("%s: failed to decode error response: %v", test.json, err)
+		}
+
+		if errResp.NewOrderFailureReason != test.want {
+			t.Fatalf("%s: got %q, want %q", test.json, errResp.NewOrderFailureReason, test.want)
+		}
+
+		if got := errResp.NewOrderFailureReason.IsRetryable(); got != test.retryable {
+			t.Fatalf("%s: got retryable %t, want %t", test.json, got, test.retryable)
+		}
+	}
+
+	errResp := ErrorResponse{}
+	if err := json.Unmarshal([]byte(`{"new_order_failure_reason": 1}`), &errResp); err == nil {
+		t.Fatalf("got no error decoding a numeric failure reason")
+	}
+}
+
+func TestTimestampPrecision(t *testing.T) {
+	t.Parallel()
+
+	const stamp = "2021-05-31T09:59:59.123456Z"
+
+	want := time.Date(2021, 5, 31, 9, 59, 59, 123456000, time.UTC)
+
+	account := Account{}
+	if err := json.Unmarshal([]byte(`{"created_at": "`+stamp+`"}`), &account); err != nil {
+		t.Fatalf("failed to decode account: %v", err)
+	}
+
+	order := HistoricalOrder{}
+	if err := json.Unmarshal([]byte(`{"created_time": "`+stamp+`"}`), &order); err != nil {
+		t.Fatalf("failed to decode order: %v", err)
+	}
+
+	fill := Fill{}
+	if err := json.Unmarshal([]byte(`{"sequence_timestamp": "`+stamp+`"}`), &fill); err != nil {
+		t.Fatalf("failed to decode fill: %v", err)
+	}
+
+	msg := WSMessage{}
+	if err := json.Unmarshal([]byte(`{"timestamp": "`+stamp+`"}`), &msg); err != nil {
+		t.Fatalf("failed to decode message: %v", err)
+	}
+
+	for name, got := range map[string]time.Time{
+		"account created_at":      account.CreatedAt,
+		"order created_time":      order.CreatedTime,
+		"fill sequence_timestamp": fill.SequenceTimestamp,
+		"message timestamp":       msg.Timestamp,
+	} {
+		if !got.Equal(want) || got.Location() != time.UTC {
+			t.Fatalf("%s: got %v, want %v", name, got, want)
+		}
+	}
+
+	params := ListOrdersParams{StartDate: want.In(time.FixedZone("CEST", 2*60*60))}
+	if got := params.query().Get("start_date"); got != stamp {
+		t.Fatalf("got start_date %q, want %q", got, stamp)
+	}
+}
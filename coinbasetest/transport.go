@@ -0,0 +1,88 @@
+// Package coinbasetest provides helpers for testing code that uses the
+// coinbase package.
+package coinbasetest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+// injectedFailureBody is the body of the responses returned for injected
+// failures, shaped like the Coinbase API's error responses.
+const injectedFailureBody = `{"error":"INJECTED_FAILURE","message":"failure injected by coinbasetest.FaultyTransport"}`
+
+// FaultyTransport is an HTTP transport that deterministically fails every
+// FailEvery-th request, counting from one, and sends the other requests with
+// Transport. A failed request returns Err if it is set, simulating a network
+// error, and otherwise a response with StatusCode, such as 429 or 503, and a
+// JSON error body. The transport can be used with coinbase.WithTransport.
+//
+// A FaultyTransport is safe for concurrent use, and should not be copied
+// after its first use.
+type FaultyTransport struct {
+	// FailEvery is the interval of the failed requests. A FailEvery of
+	// zero or less fails no requests.
+	FailEvery int
+
+	// StatusCode is the status code of the responses to failed requests,
+	// or 500 if it is zero.
+	StatusCode int
+
+	// Err is the error returned for failed requests instead of a response.
+	Err error
+
+	// Transport sends the requests that are not failed, or the default
+	// HTTP transport if it is nil.
+	Transport http.RoundTripper
+
+	requests int64
+}
+
+// RoundTrip implements the "http.RoundTripper" interface.
+func (transport *FaultyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	n := atomic.AddInt64(&transport.requests, 1)
+
+	if transport.FailEvery <= 0 || n%int64(transport.FailEvery) != 0 {
+		next := transport.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+
+		return next.RoundTrip(req)
+	}
+
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+
+	if transport.Err != nil {
+		return nil, transport.Err
+	}
+
+	statusCode := transport.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusInternalServerError
+	}
+
+	return &http.Response{
+		Status:     http.StatusText(statusCode),
+		StatusCode: statusCode,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(bytes.NewBufferString(injectedFailureBody)),
+		Request:    req,
+	}, nil
+}
+
+// Requests returns the number of requests made with the transport, including
+// the failed ones.
+func (transport *FaultyTransport) Requests() int {
+	return int(atomic.LoadInt64(&transport.requests))
+}
+
+// Reset resets the count of requests, so that the same sequence of failures
+// is replayed.
+func (transport *FaultyTransport) Reset() {
+	atomic.StoreInt64(&transport.requests, 0)
+}
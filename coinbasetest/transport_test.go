@@ -0,0 +1,147 @@
+package coinbasetest_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alpstable/coinbase"
+	"github.com/alpstable/coinbase/coinbasetest"
+)
+
+// okTransport responds to every request with an empty list of accounts.
+type okTransport struct{}
+
+func (okTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"accounts": []}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestFaultyTransport(t *testing.T) {
+	t.Parallel()
+
+	errNetwork := errors.New("connection reset")
+
+	tests := []struct {
+		name      string
+		transport *coinbasetest.FaultyTransport
+		want      []int // zero means a network error
+	}{
+		{
+			name:      "no failures",
+			transport: &coinbasetest.FaultyTransport{Transport: okTransport{}},
+			want:      []int{200, 200, 200},
+		},
+		{
+			name: "rate limited",
+			transport: &coinbasetest.FaultyTransport{
+				FailEvery:  2,
+				StatusCode: http.StatusTooManyRequests,
+				Transport:  okTransport{},
+			},
+			want: []int{200, 429, 200, 429},
+		},
+		{
+			name:      "default status code",
+			transport: &coinbasetest.FaultyTransport{FailEvery: 3, Transport: okTransport{}},
+			want:      []int{200, 200, 500, 200, 200, 500},
+		},
+		{
+			name:      "network error",
+			transport: &coinbasetest.FaultyTransport{FailEvery: 1, Err: errNetwork},
+			want:      []int{0, 0},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Run the sequence twice to check that it replays after a
+			// reset.
+			for run := 0; run < 2; run++ {
+				var got []int
+
+				for range test.want {
+					req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://test", nil)
+
+					resp, err := test.transport.RoundTrip(req)
+					if err != nil {
+						if !errors.Is(err, errNetwork) {
+							t.Fatalf("got %v, want %v", err, errNetwork)
+						}
+
+						got = append(got, 0)
+
+						continue
+					}
+
+					_ = resp.Body.Close()
+
+					got = append(got, resp.StatusCode)
+				}
+
+				if !reflect.DeepEqual(got, test.want) {
+					t.Fatalf("got status codes %v, want %v", got, test.want)
+				}
+
+				if requests := test.transport.Requests(); requests != len(test.want) {
+					t.Fatalf("got %d requests, want %d", requests, len(test.want))
+				}
+
+				test.transport.Reset()
+			}
+		})
+	}
+}
+
+func TestFaultyTransportWithClient(t *testing.T) {
+	t.Parallel()
+
+	transport := &coinbasetest.FaultyTransport{
+		FailEvery:  2,
+		StatusCode: http.StatusServiceUnavailable,
+		Transport:  okTransport{},
+	}
+
+	client, err := coinbase.NewClient("key", "secret", coinbase.WithTransport(transport), coinbase.WithMaxRetries(1),
+		coinbase.WithMaintenanceRetryWait(time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// The second request fails and is retried by the client.
+	for i := 0; i < 2; i++ {
+		if _, err := client.Accounts(context.Background()); err != nil {
+			t.Fatalf("failed to list accounts: %v", err)
+		}
+	}
+
+	if requests := transport.Requests(); requests != 3 {
+		t.Fatalf("got %d requests, want 3", requests)
+	}
+
+	// Without retries, the injected failure is returned.
+	transport.Reset()
+
+	client, err = coinbase.NewClient("key", "secret", coinbase.WithTransport(transport))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	_, _ = client.Accounts(context.Background())
+
+	if _, err := client.Accounts(context.Background()); !errors.Is(err, coinbase.ErrStatusNotOK) {
+		t.Fatalf("got %v, want %v", err, coinbase.ErrStatusNotOK)
+	}
+}
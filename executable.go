@@ -0,0 +1,132 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInsufficientDepth is returned by ExecutablePrice when the product book
+// does not hold enough size on the side that an order would fill against.
+var ErrInsufficientDepth = errors.New("insufficient book depth")
+
+const (
+	// executableBookLimit is the number of price levels on each side that
+	// ExecutablePrice first requests, and maxExecutableBookLimit the most
+	// it requests when the first levels do not hold the size.
+	executableBookLimit    = 100
+	maxExecutableBookLimit = 1000
+
+	// executableBookDepthFactor is how many times deeper each further
+	// request for the book is.
+	executableBookDepthFactor = 10
+)
+
+// ExecutablePrice returns the average price per unit of the product's base
+// currency that a market order of the given base size would get right now,
+// including fees. The order is filled against the product book from
+// GetProductBook: a buy against the asks from the lowest up and a sell against
+// the bids from the highest down. The book is first requested 100 levels deep,
+// and again 1000 levels deep if those levels do not hold the size. The taker
+// fee from the user's fee tier, see FeeTier, is then added to the cost of a
+// buy and taken from the proceeds of a sell, so the price of a buy is above
+// the average fill price and that of a sell below it.
+//
+// ErrInsufficientDepth is returned if the book does not hold the whole size,
+// ErrInvalidOrderConfig if the size is not positive and ErrInvalidOrderSide if
+// the side is neither a buy nor a sell.
+func (client *Client) ExecutablePrice(ctx context.Context, productID string, side OrderSide, size string,
+	opts ...CallOption,
+) (decimal.Decimal, error) {
+	baseSize, err := decimal.NewFromString(size)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to parse size %q: %w", size, err)
+	}
+
+	if !baseSize.IsPositive() {
+		return decimal.Decimal{}, fmt.Errorf("%w: size %s must be positive", ErrInvalidOrderConfig, size)
+	}
+
+	if side != OrderSideBuy && side != OrderSideSell {
+		return decimal.Decimal{}, fmt.Errorf("%w: %q", ErrInvalidOrderSide, side)
+	}
+
+	notional, err := client.bookNotional(ctx, productID, side, baseSize, opts)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to fill %s %s of %s: %w", side, size, productID, err)
+	}
+
+	tier, err := client.FeeTier(ctx, opts...)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	fee, err := tier.EstimateFee(notional, false)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	if side == OrderSideSell {
+		fee = fee.Neg()
+	}
+
+	return notional.Add(fee).Div(baseSize), nil
+}
+
+// bookNotional returns the quote value of filling the base size against the
+// side of the product book that an order on the given side fills against,
+// requesting a deeper book while the levels returned do not hold the size but
+// the book may have more.
+func (client *Client) bookNotional(ctx context.Context, productID string, side OrderSide,
+	baseSize decimal.Decimal, opts []CallOption,
+) (decimal.Decimal, error) {
+	for limit := int32(executableBookLimit); ; limit *= executableBookDepthFactor {
+		book, err := client.GetProductBook(ctx, productID, limit, opts...)
+		if err != nil {
+			return decimal.Decimal{}, fmt.Errorf("failed to get product book: %w", err)
+		}
+
+		levels := book.Asks
+		if side == OrderSideSell {
+			levels = book.Bids
+		}
+
+		notional, err := fillNotional(levels, baseSize)
+
+		// Fewer levels than requested are the whole book.
+		deeper := len(levels) >= int(limit) && limit < maxExecutableBookLimit
+		if !errors.Is(err, ErrInsufficientDepth) || !deeper {
+			return notional, err
+		}
+	}
+}
+
+// fillNotional returns the quote value of filling the base size against the
+// price levels, best first.
+func fillNotional(levels []PriceLevel, baseSize decimal.Decimal) (decimal.Decimal, error) {
+	notional := decimal.Zero
+	remaining := baseSize
+
+	for _, level := range levels {
+		price, err := decimal.NewFromString(level.Price)
+		if err != nil {
+			return decimal.Decimal{}, fmt.Errorf("failed to parse price %q: %w", level.Price, err)
+		}
+
+		size, err := decimal.NewFromString(level.Size)
+		if err != nil {
+			return decimal.Decimal{}, fmt.Errorf("failed to parse size %q: %w", level.Size, err)
+		}
+
+		fill := decimal.Min(size, remaining)
+		notional = notional.Add(fill.Mul(price))
+
+		if remaining = remaining.Sub(fill); !remaining.IsPositive() {
+			return notional, nil
+		}
+	}
+
+	return decimal.Decimal{}, fmt.Errorf("%w: %s of %s is not in the book", ErrInsufficientDepth, remaining, baseSize)
+}
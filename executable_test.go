@@ -0,0 +1,195 @@
+package coinbase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// mockExecutableBook returns a mock HTTP client that responds with a BTC-USD
+// book and a fee tier with a taker fee rate of 0.6%.
+func mockExecutableBook(t *testing.T) mockDoFunc {
+	t.Helper()
+
+	return func(req *http.Request) (*http.Response, error) {
+		switch req.URL.Path {
+		case "/api/v3/brokerage/product_book":
+			return newMockResponse(http.StatusOK, `{"pricebook": {"product_id": "BTC-USD",
+				"bids": [{"price": "29990", "size": "0.4"}, {"price": "29980", "size": "1"}],
+				"asks": [{"price": "30000", "size": "0.5"}, {"price": "30010", "size": "1"},
+					{"price": "30050", "size": "2"}]}}`), nil
+		case "/api/v3/brokerage/transaction_summary":
+			return newMockResponse(http.StatusOK, `{"fee_tier": {"pricing_tier": "Advanced 1",
+				"taker_fee_rate": "0.006", "maker_fee_rate": "0.004"}}`), nil
+		}
+
+		t.Errorf("unexpected request path %q", req.URL.Path)
+
+		return newMockResponse(http.StatusNotFound, ""), nil
+	}
+}
+
+func TestExecutablePrice(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		side OrderSide
+		size string
+		want string
+	}{
+		{
+			// 0.4 at 30000 fills within the best ask, plus 0.6% fees.
+			name: "buy at the best ask",
+			side: OrderSideBuy,
+			size: "0.4",
+			want: "30180",
+		},
+		{
+			// 0.5 * 30000 + 0.5 * 30010 = 30005, plus 180.03 of fees.
+			name: "buy across levels",
+			side: OrderSideBuy,
+			size: "1",
+			want: "30185.03",
+		},
+		{
+			// 0.4 * 29990 + 0.6 * 29980 = 29984, less 179.904 of fees.
+			name: "sell across levels",
+			side: OrderSideSell,
+			size: "1",
+			want: "29804.096",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{httpClient: mockExecutableBook(t)}
+
+			price, err := client.ExecutablePrice(context.Background(), "BTC-USD", test.side, test.size)
+			if err != nil {
+				t.Fatalf("failed to get executable price: %v", err)
+			}
+
+			if price.String() != test.want {
+				t.Fatalf("got price %s, want %s", price, test.want)
+			}
+		})
+	}
+}
+
+func TestExecutablePriceErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		side OrderSide
+		size string
+		err  error
+	}{
+		{
+			name: "insufficient asks",
+			side: OrderSideBuy,
+			size: "3.6",
+			err:  ErrInsufficientDepth,
+		},
+		{
+			name: "insufficient bids",
+			side: OrderSideSell,
+			size: "1.5",
+			err:  ErrInsufficientDepth,
+		},
+		{
+			name: "zero size",
+			side: OrderSideBuy,
+			size: "0",
+			err:  ErrInvalidOrderConfig,
+		},
+		{
+			name: "unknown side",
+			side: OrderSideUnknown,
+			size: "1",
+			err:  ErrInvalidOrderSide,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{httpClient: mockExecutableBook(t)}
+
+			_, err := client.ExecutablePrice(context.Background(), "BTC-USD", test.side, test.size)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestExecutablePriceDeeperBook(t *testing.T) {
+	t.Parallel()
+
+	// The first 100 asks hold 1 BTC at 30000, and the deeper book another
+	// 1 BTC at 30100.
+	shallow := make([]PriceLevel, 0, executableBookLimit)
+	for i := 0; i < executableBookLimit; i++ {
+		shallow = append(shallow, PriceLevel{Price: "30000", Size: "0.01"})
+	}
+
+	deep := append(append([]PriceLevel{}, shallow...), PriceLevel{Price: "30100", Size: "1"})
+
+	var limits []string
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/api/v3/brokerage/transaction_summary" {
+				return newMockResponse(http.StatusOK, `{"fee_tier": {"taker_fee_rate": "0.006"}}`), nil
+			}
+
+			limit := req.URL.Query().Get("limit")
+			limits = append(limits, limit)
+
+			asks := shallow
+			if limit != strconv.Itoa(executableBookLimit) {
+				asks = deep
+			}
+
+			body, err := json.Marshal(getProductBookResponse{Book: ProductBook{ProductID: "BTC-USD", Asks: asks}})
+			if err != nil {
+				return nil, err
+			}
+
+			return newMockResponse(http.StatusOK, string(body)), nil
+		}),
+	}
+
+	// 1 * 30000 + 1 * 30100 = 60100, plus 360.6 of fees, for 2 BTC.
+	price, err := client.ExecutablePrice(context.Background(), "BTC-USD", OrderSideBuy, "2")
+	if err != nil {
+		t.Fatalf("failed to get executable price: %v", err)
+	}
+
+	if want := "30230.3"; price.String() != want {
+		t.Fatalf("got price %s, want %s", price, want)
+	}
+
+	if want := []string{"100", "1000"}; !reflect.DeepEqual(limits, want) {
+		t.Fatalf("got book limits %v, want %v", limits, want)
+	}
+
+	// The deepest book does not hold 3 BTC either.
+	_, err = client.ExecutablePrice(context.Background(), "BTC-USD", OrderSideBuy, "3")
+	if !errors.Is(err, ErrInsufficientDepth) {
+		t.Fatalf("got %v, want %v", err, ErrInsufficientDepth)
+	}
+}
@@ -0,0 +1,180 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrMissingContractSize is returned by NetExposure when a futures position's
+// product has no contract size to value the position with.
+var ErrMissingContractSize = errors.New("missing contract size")
+
+// NetExposure returns the signed notional exposure of the user's open orders
+// and futures positions, keyed by product ID. Buy orders and long positions
+// add to a product's exposure, sell orders and short positions subtract from
+// it.
+//
+// The exposure is an estimate, based on the following assumptions:
+//
+//   - Limit and stop-limit orders are valued at their limit price for the
+//     part of their base size that has not been filled.
+//   - Market orders are valued at their quote size. Market orders sized in
+//     the base currency have no price and are left out.
+//   - Futures positions are valued at the number of contracts times the
+//     contract size times the current price. The contract size is taken from
+//     the product's FutureProductDetails, see GetProduct, and
+//     ErrMissingContractSize is returned if the product has none.
+func (client *Client) NetExposure(ctx context.Context, opts ...CallOption) (map[string]decimal.Decimal, error) {
+	exposure := make(map[string]decimal.Decimal)
+
+	pager := client.OpenOrdersPager(ctx, "", opts...)
+	for pager.Next() {
+		order := pager.Order()
+
+		notional, ok, err := order.notional()
+		if err != nil {
+			return nil, fmt.Errorf("failed to value order %s: %w", order.OrderID, err)
+		}
+
+		if !ok {
+			continue
+		}
+
+		if order.Side == OrderSideSell {
+			notional = notional.Neg()
+		}
+
+		exposure[order.ProductID] = exposure[order.ProductID].Add(notional)
+	}
+
+	if err := pager.Err(); err != nil {
+		return nil, err
+	}
+
+	positions, err := client.ListFuturesPositions(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	contractSizes := make(map[string]decimal.Decimal)
+
+	for _, position := range positions.Data {
+		contractSize, ok := contractSizes[position.ProductID]
+		if !ok {
+			if contractSize, err = client.contractSize(ctx, position.ProductID, opts); err != nil {
+				return nil, err
+			}
+
+			contractSizes[position.ProductID] = contractSize
+		}
+
+		notional, err := position.notional(contractSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to value position in %s: %w", position.ProductID, err)
+		}
+
+		exposure[position.ProductID] = exposure[position.ProductID].Add(notional)
+	}
+
+	return exposure, nil
+}
+
+// notional returns the unsigned notional value of the order, reporting false
+// if the order's configuration does not determine it.
+func (order HistoricalOrder) notional() (decimal.Decimal, bool, error) {
+	config := order.OrderConfiguration
+
+	var size, price string
+
+	switch {
+	case config.LimitGTC != nil:
+		size, price = config.LimitGTC.BaseSize, config.LimitGTC.Price
+	case config.LimitGTD != nil:
+		size, price = config.LimitGTD.BaseSize, config.LimitGTD.Price
+	case config.StopLimitGTC != nil:
+		size, price = config.StopLimitGTC.BaseSize, config.StopLimitGTC.LimitPrice
+	case config.StopLimitGTD != nil:
+		size, price = config.StopLimitGTD.BaseSize, config.StopLimitGTD.LimitPrice
+	case config.MarketIOC != nil && config.MarketIOC.QuoteSize != "":
+		quote, err := decimal.NewFromString(config.MarketIOC.QuoteSize)
+		if err != nil {
+			return decimal.Decimal{}, false, fmt.Errorf("failed to parse quote size: %w", err)
+		}
+
+		return quote, true, nil
+	default:
+		return decimal.Decimal{}, false, nil
+	}
+
+	notional, err := multiply(size, price)
+	if err != nil {
+		return decimal.Decimal{}, false, err
+	}
+
+	if order.FilledSize != "" {
+		filled, err := multiply(order.FilledSize, price)
+		if err != nil {
+			return decimal.Decimal{}, false, err
+		}
+
+		notional = notional.Sub(filled)
+	}
+
+	return notional, true, nil
+}
+
+// contractSize returns the amount of the underlying asset in one contract of
+// the futures product.
+func (client *Client) contractSize(ctx context.Context, productID string, opts []CallOption) (decimal.Decimal, error) {
+	product, err := client.GetProduct(ctx, productID, opts...)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to get product %s: %w", productID, err)
+	}
+
+	if product.FutureProductDetails == nil || product.FutureProductDetails.ContractSize == "" {
+		return decimal.Decimal{}, fmt.Errorf("%w: %s", ErrMissingContractSize, productID)
+	}
+
+	size, err := decimal.NewFromString(product.FutureProductDetails.ContractSize)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to parse contract size %q of %s: %w",
+			product.FutureProductDetails.ContractSize, productID, err)
+	}
+
+	return size, nil
+}
+
+// notional returns the signed notional value of the position, whose contracts
+// are each of the given size.
+func (position FuturesPosition) notional(contractSize decimal.Decimal) (decimal.Decimal, error) {
+	notional, err := multiply(position.NumberOfContracts, position.CurrentPrice)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	notional = notional.Mul(contractSize)
+
+	if position.Side == FuturesPositionSideShort {
+		notional = notional.Neg()
+	}
+
+	return notional, nil
+}
+
+// multiply returns the product of a size and a price given as decimal strings.
+func multiply(size, price string) (decimal.Decimal, error) {
+	sizeAmount, err := decimal.NewFromString(size)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to parse size %q: %w", size, err)
+	}
+
+	priceAmount, err := decimal.NewFromString(price)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to parse price %q: %w", price, err)
+	}
+
+	return sizeAmount.Mul(priceAmount), nil
+}
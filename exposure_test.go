@@ -0,0 +1,102 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestNetExposure(t *testing.T) {
+	t.Parallel()
+
+	orders := `{"orders": [
+		{
+			"order_id": "1",
+			"product_id": "BTC-USD",
+			"side": "BUY",
+			"order_configuration": {"limit_limit_gtc": {"base_size": "0.75", "limit_price": "20000"}},
+			"filled_size": "0.25"
+		},
+		{
+			"order_id": "2",
+			"product_id": "BTC-USD",
+			"side": "SELL",
+			"order_configuration": {"stop_limit_stop_limit_gtc": {
+				"base_size": "0.2", "limit_price": "25000", "stop_price": "24000"
+			}}
+		},
+		{
+			"order_id": "3",
+			"product_id": "ETH-USD",
+			"side": "SELL",
+			"order_configuration": {"limit_limit_gtd": {"base_size": "2", "limit_price": "1500"}}
+		},
+		{
+			"order_id": "4",
+			"product_id": "ETH-USD",
+			"side": "BUY",
+			"order_configuration": {"market_market_ioc": {"quote_size": "100"}}
+		},
+		{
+			"order_id": "5",
+			"product_id": "SOL-USD",
+			"side": "SELL",
+			"order_configuration": {"market_market_ioc": {"base_size": "10"}}
+		}
+	]}`
+
+	positions := `{"positions": [
+		{"product_id": "BIT-28JUL23-CDE", "side": "LONG", "number_of_contracts": "3", "current_price": "30000"},
+		{"product_id": "ET-28JUL23-CDE", "side": "SHORT", "number_of_contracts": "2", "current_price": "1800"}
+	]}`
+
+	contractSizes := map[string]string{"BIT-28JUL23-CDE": "0.01", "ET-28JUL23-CDE": "0.1"}
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/api/v3/brokerage/cfm/positions" {
+				return newMockResponse(http.StatusOK, positions), nil
+			}
+
+			if productID := strings.TrimPrefix(req.URL.Path, "/api/v3/brokerage/products/"); productID != req.URL.Path {
+				return newMockResponse(http.StatusOK, `{"product_id": "`+productID+`", `+
+					`"future_product_details": {"contract_size": "`+contractSizes[productID]+`"}}`), nil
+			}
+
+			return newMockResponse(http.StatusOK, orders), nil
+		}),
+	}
+
+	got, err := client.NetExposure(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get net exposure: %v", err)
+	}
+
+	want := map[string]string{
+		"BTC-USD":         "5000",
+		"ETH-USD":         "-2900",
+		"BIT-28JUL23-CDE": "900",
+		"ET-28JUL23-CDE":  "-360",
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got exposure %v, want %v", got, want)
+	}
+
+	for productID, value := range want {
+		if !got[productID].Equal(decimal.RequireFromString(value)) {
+			t.Fatalf("got %s exposure %s, want %s", productID, got[productID], value)
+		}
+	}
+
+	// A position whose product has no contract size cannot be valued.
+	delete(contractSizes, "ET-28JUL23-CDE")
+
+	if _, err := client.NetExposure(context.Background()); !errors.Is(err, ErrMissingContractSize) {
+		t.Fatalf("got %v, want %v", err, ErrMissingContractSize)
+	}
+}
@@ -0,0 +1,449 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrInvalidFillPrice is returned when a fill whose size is in the quote
+// currency cannot be converted to the base currency because its price is zero.
+var ErrInvalidFillPrice = errors.New("invalid fill price")
+
+// LiquidityIndicator represents whether a fill added liquidity to the order
+// book (maker) or removed it (taker).
+type LiquidityIndicator string
+
+const (
+	// LiquidityIndicatorUnknown represents an unknown liquidity indicator.
+	LiquidityIndicatorUnknown LiquidityIndicator = "UNKNOWN_LIQUIDITY_INDICATOR"
+
+	// LiquidityIndicatorMaker represents a fill that added liquidity.
+	LiquidityIndicatorMaker LiquidityIndicator = "MAKER"
+
+	// LiquidityIndicatorTaker represents a fill that removed liquidity.
+	LiquidityIndicatorTaker LiquidityIndicator = "TAKER"
+)
+
+// IsMaker reports whether the liquidity indicator is that of a maker.
+func (indicator LiquidityIndicator) IsMaker() bool {
+	return indicator == LiquidityIndicatorMaker
+}
+
+// Fill represents a partial or complete execution of an order.
+type Fill struct {
+	EntryID            string             `json:"entry_id"`
+	TradeID            string             `json:"trade_id"`
+	OrderID            string             `json:"order_id"`
+	TradeTime          time.Time          `json:"trade_time"`
+	TradeType          string             `json:"trade_type"`
+	Price              string             `json:"price"`
+	Size               string             `json:"size"`
+	SizeInQuote        bool               `json:"size_in_quote"`
+	Commission         string             `json:"commission"`
+	ProductID          string             `json:"product_id"`
+	SequenceTimestamp  time.Time          `json:"sequence_timestamp"`
+	LiquidityIndicator LiquidityIndicator `json:"liquidity_indicator"`
+	UserID             string             `json:"user_id"`
+	Side               OrderSide          `json:"side"`
+}
+
+// BaseSize returns the size of the fill in the base currency. A size in the
+// quote currency, see SizeInQuote, is divided by the fill's price, and
+// ErrInvalidFillPrice is returned if the price is zero.
+func (fill Fill) BaseSize() (decimal.Decimal, error) {
+	size, price, err := fill.sizeAndPrice()
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	if !fill.SizeInQuote {
+		return size, nil
+	}
+
+	if price.IsZero() {
+		return decimal.Decimal{}, fmt.Errorf("%w: fill %s has a zero price", ErrInvalidFillPrice, fill.key())
+	}
+
+	return size.Div(price), nil
+}
+
+// QuoteSize returns the size of the fill in the quote currency, which is its
+// value. A size in the base currency, see SizeInQuote, is multiplied by the
+// fill's price.
+func (fill Fill) QuoteSize() (decimal.Decimal, error) {
+	size, price, err := fill.sizeAndPrice()
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	if fill.SizeInQuote {
+		return size, nil
+	}
+
+	return size.Mul(price), nil
+}
+
+// sizeAndPrice returns the size and price of the fill as decimals.
+func (fill Fill) sizeAndPrice() (decimal.Decimal, decimal.Decimal, error) {
+	size, err := decimal.NewFromString(fill.Size)
+	if err != nil {
+		return decimal.Decimal{}, decimal.Decimal{}, fmt.Errorf("failed to parse size %q of fill %s: %w",
+			fill.Size, fill.key(), err)
+	}
+
+	price, err := decimal.NewFromString(fill.Price)
+	if err != nil {
+		return decimal.Decimal{}, decimal.Decimal{}, fmt.Errorf("failed to parse price %q of fill %s: %w",
+			fill.Price, fill.key(), err)
+	}
+
+	return size, price, nil
+}
+
+// key returns a key that uniquely identifies the fill.
+func (fill Fill) key() string {
+	return fill.TradeID + "/" + fill.EntryID
+}
+
+// Fills represents a collection of fills along with metadata.
+type Fills struct {
+	Data   []Fill `json:"fills"`
+	Cursor string `json:"cursor"`
+}
+
+// FillSortBy represents the order in which fills are listed.
+type FillSortBy string
+
+const (
+	// FillSortByUnknown represents an unknown sort order.
+	FillSortByUnknown FillSortBy = "UNKNOWN_SORT_BY"
+
+	// FillSortByPrice sorts fills by price.
+	FillSortByPrice FillSortBy = "PRICE"
+
+	// FillSortByTradeTime sorts fills by trade time.
+	FillSortByTradeTime FillSortBy = "TRADE_TIME"
+)
+
+// ListFillsParams are the query parameters used to filter the fills returned
+// by ListFills. Zero values are omitted from the request.
+type ListFillsParams struct {
+	OrderID   string
+	ProductID string
+	Limit     int32
+	Cursor    string
+	SortBy    FillSortBy
+
+	// StartSequenceTimestamp and EndSequenceTimestamp bound the sequence
+	// timestamps of the fills.
+	StartSequenceTimestamp time.Time
+	EndSequenceTimestamp   time.Time
+}
+
+// query returns the URL query values for the parameters.
+func (params ListFillsParams) query() url.Values {
+	query := url.Values{}
+
+	if params.OrderID != "" {
+		query.Set("order_id", params.OrderID)
+	}
+
+	if params.ProductID != "" {
+		query.Set("product_id", params.ProductID)
+	}
+
+	if params.Limit > 0 {
+		formatBase := 10
+		query.Set("limit", strconv.FormatInt(int64(params.Limit), formatBase))
+	}
+
+	if params.Cursor != "" {
+		query.Set("cursor", params.Cursor)
+	}
+
+	if params.SortBy != "" {
+		query.Set("sort_by", string(params.SortBy))
+	}
+
+	if !params.StartSequenceTimestamp.IsZero() {
+		query.Set("start_sequence_timestamp", params.StartSequenceTimestamp.UTC().Format(time.RFC3339Nano))
+	}
+
+	if !params.EndSequenceTimestamp.IsZero() {
+		query.Set("end_sequence_timestamp", params.EndSequenceTimestamp.UTC().Format(time.RFC3339Nano))
+	}
+
+	return query
+}
+
+// ListFills returns a page of fills matching the given parameters. A limit
+// greater than the endpoint's maximum is handled according to the client's
+// LimitPolicy. The Data of an empty page is an empty slice, never nil.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_getfills
+func (client *Client) ListFills(ctx context.Context, params ListFillsParams, opts ...CallOption) (*Fills, error) {
+	path := []string{"brokerage", "orders", "historical", "fills"}
+
+	var err error
+	if params.Limit, err = client.checkLimit(params.Limit, maxFillsLimit); err != nil {
+		return nil, err
+	}
+
+	fills := &Fills{}
+	if err := client.do(ctx, http.MethodGet, path, params.query(), nil, fills, opts); err != nil {
+		return nil, err
+	}
+
+	fills.Data = emptyIfNil(fills.Data)
+
+	return fills, nil
+}
+
+// ListFillsAll returns every fill matching the given parameters, following the
+// cursor until the last page. Fills that are repeated at page boundaries are
+// only returned once.
+func (client *Client) ListFillsAll(ctx context.Context, params ListFillsParams, opts ...CallOption) ([]Fill, error) {
+	var (
+		all   []Fill
+		guard cursorGuard
+	)
+
+	seen := make(map[string]bool)
+
+	for {
+		fills, err := client.ListFills(ctx, params, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fill := range fills.Data {
+			if !seen[fill.key()] {
+				seen[fill.key()] = true
+				all = append(all, fill)
+			}
+		}
+
+		if fills.Cursor == "" {
+			return all, nil
+		}
+
+		if err := guard.check(params.Cursor, fills.Cursor, len(fills.Data), true); err != nil {
+			return nil, err
+		}
+
+		params.Cursor = fills.Cursor
+	}
+}
+
+// FillsSummary aggregates the fills of an order.
+type FillsSummary struct {
+	OrderID string
+
+	// Fills is the number of fills.
+	Fills int
+
+	// Size is the total size filled, in the base currency, and Notional
+	// the total value of the fills, in the quote currency.
+	Size     decimal.Decimal
+	Notional decimal.Decimal
+
+	// AveragePrice is the volume-weighted average price of the fills, or
+	// zero if the order has no fills.
+	AveragePrice decimal.Decimal
+
+	// Commission is the total commission paid for the fills.
+	Commission decimal.Decimal
+}
+
+// OrderFillsSummary returns the total size, volume-weighted average price and
+// total commission of the fills of the given order, following the cursor until
+// the last page. Fills whose size is in the quote currency are converted with
+// their price, see Fill.BaseSize. An order without fills has a summary with
+// zero totals.
+func (client *Client) OrderFillsSummary(ctx context.Context, orderID string,
+	opts ...CallOption,
+) (*FillsSummary, error) {
+	fills, err := client.ListFillsAll(ctx, ListFillsParams{OrderID: orderID}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &FillsSummary{OrderID: orderID, Fills: len(fills)}
+
+	for _, fill := range fills {
+		size, err := fill.BaseSize()
+		if err != nil {
+			return nil, err
+		}
+
+		// The size and price were parsed by BaseSize, so they are
+		// valid.
+		notional, _ := fill.QuoteSize()
+
+		commission := decimal.Zero
+		if fill.Commission != "" {
+			if commission, err = decimal.NewFromString(fill.Commission); err != nil {
+				return nil, fmt.Errorf("failed to parse commission of fill %s: %w", fill.key(), err)
+			}
+		}
+
+		summary.Size = summary.Size.Add(size)
+		summary.Notional = summary.Notional.Add(notional)
+		summary.Commission = summary.Commission.Add(commission)
+	}
+
+	if !summary.Size.IsZero() {
+		summary.AveragePrice = summary.Notional.Div(summary.Size)
+	}
+
+	return summary, nil
+}
+
+// defaultFillPollInterval is the poll interval used by a FillWatcher when a
+// non-positive interval is given.
+const defaultFillPollInterval = time.Second
+
+// FillWatcher polls the fills for an order and emits each fill once, until the
+// order reaches a terminal status.
+type FillWatcher struct {
+	fills chan Fill
+	err   error
+
+	// lastSeq is the latest sequence timestamp emitted, and seen is the
+	// set of fills emitted with that timestamp.
+	lastSeq time.Time
+	seen    map[string]bool
+}
+
+// WatchFills starts polling the fills for the given order every interval. New
+// fills are emitted in sequence timestamp order on the watcher's Fills
+// channel. The channel is closed once the order reaches a terminal status and
+// its final fills have been emitted, or when polling fails or the context is
+// cancelled.
+func (client *Client) WatchFills(ctx context.Context, orderID string, interval time.Duration,
+	opts ...CallOption,
+) *FillWatcher {
+	if interval <= 0 {
+		interval = defaultFillPollInterval
+	}
+
+	watcher := &FillWatcher{
+		fills: make(chan Fill),
+		seen:  make(map[string]bool),
+	}
+
+	go watcher.run(ctx, client, orderID, interval, opts)
+
+	return watcher
+}
+
+// Fills returns the channel on which new fills are emitted.
+func (watcher *FillWatcher) Fills() <-chan Fill {
+	return watcher.fills
+}
+
+// Err returns the error that stopped the watcher, if any. It must only be
+// called after the Fills channel has been closed.
+func (watcher *FillWatcher) Err() error {
+	return watcher.err
+}
+
+// run polls the order until it is terminal, closing the fills channel on
+// return.
+func (watcher *FillWatcher) run(ctx context.Context, client *Client, orderID string, interval time.Duration,
+	opts []CallOption,
+) {
+	defer close(watcher.fills)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		done, err := watcher.poll(ctx, client, orderID, opts)
+		if err != nil {
+			watcher.err = err
+
+			return
+		}
+
+		if done {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			watcher.err = ctx.Err()
+
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll emits any new fills for the order and reports whether the order is
+// terminal. The order is fetched before its fills so that the fills of a
+// terminal order are complete.
+func (watcher *FillWatcher) poll(ctx context.Context, client *Client, orderID string,
+	opts []CallOption,
+) (bool, error) {
+	order, err := client.GetOrder(ctx, orderID, opts...)
+	if err != nil {
+		return false, err
+	}
+
+	// Only the fills from the last one emitted are listed, which is still
+	// listed again and skipped as seen.
+	params := ListFillsParams{OrderID: orderID, StartSequenceTimestamp: watcher.lastSeq}
+
+	fills, err := client.ListFillsAll(ctx, params, opts...)
+	if err != nil {
+		return false, err
+	}
+
+	sort.SliceStable(fills, func(i, j int) bool {
+		return fills[i].SequenceTimestamp.Before(fills[j].SequenceTimestamp)
+	})
+
+	for _, fill := range fills {
+		if !watcher.isNew(fill) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case watcher.fills <- fill:
+		}
+	}
+
+	return order.Status.IsTerminal(), nil
+}
+
+// isNew reports whether the fill has not been emitted before, recording it as
+// emitted if so. Fills must be checked in sequence timestamp order.
+func (watcher *FillWatcher) isNew(fill Fill) bool {
+	if fill.SequenceTimestamp.Before(watcher.lastSeq) {
+		return false
+	}
+
+	if fill.SequenceTimestamp.After(watcher.lastSeq) {
+		watcher.lastSeq = fill.SequenceTimestamp
+		watcher.seen = make(map[string]bool)
+	}
+
+	if watcher.seen[fill.key()] {
+		return false
+	}
+
+	watcher.seen[fill.key()] = true
+
+	return true
+}
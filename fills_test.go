@@ -0,0 +1,441 @@
+package coinbase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestListFills(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response []byte
+		want     *Fills
+		err      error
+	}{
+		{
+			name: "nil",
+			err:  io.EOF, // end of file, nothing in response
+		},
+		{
+			name:     "empty slice",
+			response: []byte(`{}`),
+			want:     &Fills{Data: []Fill{}},
+		},
+		{
+			name: "single",
+			response: []byte(`
+{
+  "fills": [{
+    "entry_id": "22222-2222222-22222222",
+    "trade_id": "1111-11111-111111",
+    "order_id": "0000-000000-000000",
+    "trade_time": "2021-05-31T09:59:59Z",
+    "trade_type": "FILL",
+    "price": "10000.00",
+    "size": "0.001",
+    "commission": "1.25",
+    "product_id": "BTC-USD",
+    "sequence_timestamp": "2021-05-31T09:58:59Z",
+    "liquidity_indicator": "MAKER",
+    "user_id": "3333-333333-3333333",
+    "side": "BUY"
+  }],
+  "cursor": "789100"
+}`),
+			want: &Fills{
+				Data: []Fill{
+					{
+						EntryID:            "22222-2222222-22222222",
+						TradeID:            "1111-11111-111111",
+						OrderID:            "0000-000000-000000",
+						TradeTime:          time.Date(2021, 5, 31, 9, 59, 59, 0, time.UTC),
+						TradeType:          "FILL",
+						Price:              "10000.00",
+						Size:               "0.001",
+						Commission:         "1.25",
+						ProductID:          "BTC-USD",
+						SequenceTimestamp:  time.Date(2021, 5, 31, 9, 58, 59, 0, time.UTC),
+						LiquidityIndicator: LiquidityIndicatorMaker,
+						UserID:             "3333-333333-3333333",
+						Side:               OrderSideBuy,
+					},
+				},
+				Cursor: "789100",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{
+				httpClient: &mockClient{
+					response:   test.response,
+					statusCode: http.StatusOK,
+				},
+			}
+
+			got, err := client.ListFills(context.Background(), ListFillsParams{})
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestListFillsAll(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, time.July, 2, 0, 0, 0, 0, time.UTC)
+
+	// The second page repeats the last fill of the first page.
+	pages := map[string]string{
+		"": `{"fills": [
+			{"trade_id": "1", "entry_id": "a"},
+			{"trade_id": "2", "entry_id": "b"}
+		], "cursor": "page-2"}`,
+		"page-2": `{"fills": [
+			{"trade_id": "2", "entry_id": "b"},
+			{"trade_id": "2", "entry_id": "c"},
+			{"trade_id": "3", "entry_id": "d"}
+		], "cursor": ""}`,
+	}
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			query := req.URL.Query()
+
+			want := url.Values{
+				"product_id":               {"BTC-USD"},
+				"sort_by":                  {"TRADE_TIME"},
+				"start_sequence_timestamp": {"2023-07-01T00:00:00Z"},
+				"end_sequence_timestamp":   {"2023-07-02T00:00:00Z"},
+			}
+
+			if cursor := query.Get("cursor"); cursor != "" {
+				want.Set("cursor", cursor)
+			}
+
+			if !reflect.DeepEqual(query, want) {
+				t.Errorf("got query %v, want %v", query, want)
+			}
+
+			return newMockResponse(http.StatusOK, pages[query.Get("cursor")]), nil
+		}),
+	}
+
+	fills, err := client.ListFillsAll(context.Background(), ListFillsParams{
+		ProductID:              "BTC-USD",
+		SortBy:                 FillSortByTradeTime,
+		StartSequenceTimestamp: start,
+		EndSequenceTimestamp:   end,
+	})
+	if err != nil {
+		t.Fatalf("failed to list fills: %v", err)
+	}
+
+	var got []string
+	for _, fill := range fills {
+		got = append(got, fill.key())
+	}
+
+	if want := []string{"1/a", "2/b", "2/c", "3/d"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got fills %v, want %v", got, want)
+	}
+}
+
+func TestOrderFillsSummary(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		pages map[string]string
+		want  FillsSummary
+	}{
+		{
+			name:  "no fills",
+			pages: map[string]string{"": `{"fills": [], "cursor": ""}`},
+			want:  FillsSummary{OrderID: "order-1"},
+		},
+		{
+			name: "multiple fills",
+			pages: map[string]string{
+				"": `{"fills": [
+					{"trade_id": "1", "price": "100", "size": "1", "commission": "0.5"},
+					{"trade_id": "2", "price": "110", "size": "2", "commission": "1.1"}
+				], "cursor": "page-2"}`,
+				"page-2": `{"fills": [
+					{"trade_id": "3", "price": "130", "size": "1", "commission": "0.65"}
+				], "cursor": ""}`,
+			},
+			want: FillsSummary{
+				OrderID:      "order-1",
+				Fills:        3,
+				Size:         decimal.RequireFromString("4"),
+				Notional:     decimal.RequireFromString("450"),
+				AveragePrice: decimal.RequireFromString("112.5"),
+				Commission:   decimal.RequireFromString("2.25"),
+			},
+		},
+		{
+			name: "sizes in quote",
+			pages: map[string]string{
+				"": `{"fills": [
+					{"trade_id": "1", "price": "100", "size": "1"},
+					{"trade_id": "2", "price": "200", "size": "300", "size_in_quote": true}
+				], "cursor": ""}`,
+			},
+			want: FillsSummary{
+				OrderID:      "order-1",
+				Fills:        2,
+				Size:         decimal.RequireFromString("2.5"),
+				Notional:     decimal.RequireFromString("400"),
+				AveragePrice: decimal.RequireFromString("160"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{
+				httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+					if got := req.URL.Query().Get("order_id"); got != "order-1" {
+						t.Errorf("got order ID %q, want %q", got, "order-1")
+					}
+
+					return newMockResponse(http.StatusOK, test.pages[req.URL.Query().Get("cursor")]), nil
+				}),
+			}
+
+			got, err := client.OrderFillsSummary(context.Background(), "order-1")
+			if err != nil {
+				t.Fatalf("failed to summarize fills: %v", err)
+			}
+
+			if got.OrderID != test.want.OrderID || got.Fills != test.want.Fills ||
+				!got.Size.Equal(test.want.Size) || !got.Notional.Equal(test.want.Notional) ||
+				!got.AveragePrice.Equal(test.want.AveragePrice) || !got.Commission.Equal(test.want.Commission) {
+				t.Fatalf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFillSizes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		fill  Fill
+		base  string
+		quote string
+		err   error
+	}{
+		{
+			name:  "size in base",
+			fill:  Fill{Price: "25000", Size: "0.02"},
+			base:  "0.02",
+			quote: "500",
+		},
+		{
+			name:  "size in quote",
+			fill:  Fill{Price: "25000", Size: "500", SizeInQuote: true},
+			base:  "0.02",
+			quote: "500",
+		},
+		{
+			name: "size in quote at a zero price",
+			fill: Fill{Price: "0", Size: "500", SizeInQuote: true},
+			err:  ErrInvalidFillPrice,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			base, err := test.fill.BaseSize()
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got base size error %v, want %v", err, test.err)
+			}
+
+			if test.err != nil {
+				return
+			}
+
+			if want := decimal.RequireFromString(test.base); !base.Equal(want) {
+				t.Fatalf("got base size %s, want %s", base, want)
+			}
+
+			quote, err := test.fill.QuoteSize()
+			if err != nil {
+				t.Fatalf("failed to get quote size: %v", err)
+			}
+
+			if want := decimal.RequireFromString(test.quote); !quote.Equal(want) {
+				t.Fatalf("got quote size %s, want %s", quote, want)
+			}
+		})
+	}
+
+	var fill Fill
+	if err := json.Unmarshal([]byte(`{"size": "500", "size_in_quote": true}`), &fill); err != nil {
+		t.Fatalf("failed to decode fill: %v", err)
+	}
+
+	if !fill.SizeInQuote {
+		t.Fatalf("got size in base, want size in quote")
+	}
+}
+
+func TestLiquidityIndicatorIsMaker(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		indicator LiquidityIndicator
+		want      bool
+	}{
+		{indicator: LiquidityIndicatorMaker, want: true},
+		{indicator: LiquidityIndicatorTaker, want: false},
+		{indicator: LiquidityIndicatorUnknown, want: false},
+		{indicator: "", want: false},
+	}
+
+	for _, test := range tests {
+		if got := test.indicator.IsMaker(); got != test.want {
+			t.Fatalf("%q: got %t, want %t", test.indicator, got, test.want)
+		}
+	}
+}
+
+func TestWatchFills(t *testing.T) {
+	t.Parallel()
+
+	// The first poll sees an open order with one fill. The second poll
+	// sees a filled order whose fills span two pages, overlapping with the
+	// fill seen on the first poll and with each other. Each poll only
+	// lists the fills from the last one seen.
+	polls := []struct {
+		status string
+		start  string
+		pages  map[string]string
+	}{
+		{
+			status: "OPEN",
+			pages: map[string]string{
+				"": `{"fills": [
+					{"entry_id": "1", "trade_id": "t1", "sequence_timestamp": "2021-05-31T09:00:00Z"}
+				]}`,
+			},
+		},
+		{
+			status: "FILLED",
+			start:  "2021-05-31T09:00:00Z",
+			pages: map[string]string{
+				"": `{"fills": [
+					{"entry_id": "3", "trade_id": "t3", "sequence_timestamp": "2021-05-31T09:00:01Z"},
+					{"entry_id": "2", "trade_id": "t2", "sequence_timestamp": "2021-05-31T09:00:01Z"}
+				], "cursor": "page-2"}`,
+				"page-2": `{"fills": [
+					{"entry_id": "2", "trade_id": "t2", "sequence_timestamp": "2021-05-31T09:00:01Z"},
+					{"entry_id": "1", "trade_id": "t1", "sequence_timestamp": "2021-05-31T09:00:00Z"}
+				]}`,
+			},
+		},
+	}
+
+	poll := -1
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			switch req.URL.Path {
+			case "/api/v3/brokerage/orders/historical/order-1":
+				poll++
+
+				return newMockResponse(http.StatusOK, `{"order": {"status": "`+polls[poll].status+`"}}`), nil
+			case "/api/v3/brokerage/orders/historical/fills":
+				if got := req.URL.Query().Get("order_id"); got != "order-1" {
+					t.Errorf("got order_id %q, want %q", got, "order-1")
+				}
+
+				if got := req.URL.Query().Get("start_sequence_timestamp"); got != polls[poll].start {
+					t.Errorf("got start_sequence_timestamp %q, want %q", got, polls[poll].start)
+				}
+
+				return newMockResponse(http.StatusOK, polls[poll].pages[req.URL.Query().Get("cursor")]), nil
+			}
+
+			t.Errorf("unexpected request path %q", req.URL.Path)
+
+			return newMockResponse(http.StatusNotFound, ""), nil
+		}),
+	}
+
+	watcher := client.WatchFills(context.Background(), "order-1", time.Millisecond)
+
+	var got []string
+	for fill := range watcher.Fills() {
+		got = append(got, fill.EntryID)
+	}
+
+	if err := watcher.Err(); err != nil {
+		t.Fatalf("failed to watch fills: %v", err)
+	}
+
+	if want := []string{"1", "3", "2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestWatchFillsContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/api/v3/brokerage/orders/historical/fills" {
+				return newMockResponse(http.StatusOK, `{}`), nil
+			}
+
+			return newMockResponse(http.StatusOK, `{"order": {"status": "OPEN"}}`), nil
+		}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	watcher := client.WatchFills(ctx, "order-1", time.Millisecond)
+
+	cancel()
+
+	for range watcher.Fills() {
+		t.Fatalf("got a fill for an order without fills")
+	}
+
+	if err := watcher.Err(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want %v", err, context.Canceled)
+	}
+}
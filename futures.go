@@ -0,0 +1,168 @@
+package coinbase
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// FuturesPositionSide represents the side of a futures position.
+type FuturesPositionSide string
+
+const (
+	// FuturesPositionSideUnknown represents a position of an unknown side.
+	FuturesPositionSideUnknown FuturesPositionSide = "UNKNOWN"
+
+	// FuturesPositionSideLong represents a long position.
+	FuturesPositionSideLong FuturesPositionSide = "LONG"
+
+	// FuturesPositionSideShort represents a short position.
+	FuturesPositionSideShort FuturesPositionSide = "SHORT"
+)
+
+// FuturesPosition represents an open position in a futures product.
+type FuturesPosition struct {
+	ProductID         string              `json:"product_id"`
+	ExpirationTime    time.Time           `json:"expiration_time"`
+	Side              FuturesPositionSide `json:"side"`
+	NumberOfContracts string              `json:"number_of_contracts"`
+	CurrentPrice      string              `json:"current_price"`
+	AvgEntryPrice     string              `json:"avg_entry_price"`
+	UnrealizedPNL     string              `json:"unrealized_pnl"`
+	DailyRealizedPNL  string              `json:"daily_realized_pnl"`
+}
+
+// FuturesPositions represents a collection of futures positions.
+type FuturesPositions struct {
+	Data []FuturesPosition `json:"positions"`
+}
+
+// ListFuturesPositions returns the open positions of the user's futures
+// account.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_getfcmpositions
+func (client *Client) ListFuturesPositions(ctx context.Context, opts ...CallOption) (*FuturesPositions, error) {
+	path := []string{"brokerage", "cfm", "positions"}
+
+	positions := &FuturesPositions{}
+	if err := client.do(ctx, http.MethodGet, path, nil, nil, positions, opts); err != nil {
+		return nil, err
+	}
+
+	return positions, nil
+}
+
+// MarginWindowType represents a margin window of the futures commission
+// merchant, which sets the margin required for futures positions.
+type MarginWindowType string
+
+const (
+	// MarginWindowTypeUnspecified represents an unspecified margin window.
+	MarginWindowTypeUnspecified MarginWindowType = "FCM_MARGIN_WINDOW_TYPE_UNSPECIFIED"
+
+	// MarginWindowTypeOvernight represents the overnight margin window.
+	MarginWindowTypeOvernight MarginWindowType = "FCM_MARGIN_WINDOW_TYPE_OVERNIGHT"
+
+	// MarginWindowTypeWeekend represents the weekend margin window.
+	MarginWindowTypeWeekend MarginWindowType = "FCM_MARGIN_WINDOW_TYPE_WEEKEND"
+
+	// MarginWindowTypeIntraday represents the intraday margin window, with
+	// lower margin requirements.
+	MarginWindowTypeIntraday MarginWindowType = "FCM_MARGIN_WINDOW_TYPE_INTRADAY"
+
+	// MarginWindowTypeTransition represents the transition from the
+	// intraday to the overnight margin window.
+	MarginWindowTypeTransition MarginWindowType = "FCM_MARGIN_WINDOW_TYPE_TRANSITION"
+)
+
+// MarginWindowMeasure represents the margin requirements of the futures account
+// in a margin window.
+type MarginWindowMeasure struct {
+	MarginWindowType            MarginWindowType `json:"margin_window_type"`
+	MarginLevel                 string           `json:"margin_level"`
+	InitialMargin               string           `json:"initial_margin"`
+	MaintenanceMargin           string           `json:"maintenance_margin"`
+	LiquidationBufferPercentage string           `json:"liquidation_buffer_percentage"`
+	TotalHold                   string           `json:"total_hold"`
+	FuturesBuyingPower          string           `json:"futures_buying_power"`
+}
+
+// FuturesBalanceSummary represents the balances and margin of the user's
+// futures account.
+type FuturesBalanceSummary struct {
+	FuturesBuyingPower          Money  `json:"futures_buying_power"`
+	TotalUSDBalance             Money  `json:"total_usd_balance"`
+	CBIUSDBalance               Money  `json:"cbi_usd_balance"`
+	CFMUSDBalance               Money  `json:"cfm_usd_balance"`
+	TotalOpenOrdersHoldAmount   Money  `json:"total_open_orders_hold_amount"`
+	UnrealizedPNL               Money  `json:"unrealized_pnl"`
+	DailyRealizedPNL            Money  `json:"daily_realized_pnl"`
+	InitialMargin               Money  `json:"initial_margin"`
+	AvailableMargin             Money  `json:"available_margin"`
+	LiquidationThreshold        Money  `json:"liquidation_threshold"`
+	LiquidationBufferAmount     Money  `json:"liquidation_buffer_amount"`
+	LiquidationBufferPercentage string `json:"liquidation_buffer_percentage"`
+
+	// IntradayMarginWindowMeasure and OvernightMarginWindowMeasure are the
+	// margin requirements of the intraday and overnight margin windows.
+	IntradayMarginWindowMeasure  MarginWindowMeasure `json:"intraday_margin_window_measure"`
+	OvernightMarginWindowMeasure MarginWindowMeasure `json:"overnight_margin_window_measure"`
+}
+
+// getFuturesBalanceSummaryResponse is the response of
+// GetFuturesBalanceSummary.
+type getFuturesBalanceSummaryResponse struct {
+	BalanceSummary FuturesBalanceSummary `json:"balance_summary"`
+}
+
+// GetFuturesBalanceSummary returns the balances and margin of the user's
+// futures account, including the margin requirements of the intraday and
+// overnight margin windows.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_getfcmbalancesummary
+func (client *Client) GetFuturesBalanceSummary(ctx context.Context,
+	opts ...CallOption,
+) (*FuturesBalanceSummary, error) {
+	path := []string{"brokerage", "cfm", "balance_summary"}
+
+	resp := &getFuturesBalanceSummaryResponse{}
+	if err := client.do(ctx, http.MethodGet, path, nil, nil, resp, opts); err != nil {
+		return nil, err
+	}
+
+	return &resp.BalanceSummary, nil
+}
+
+// PerpetualDetails represents the funding details of a perpetual futures
+// product.
+type PerpetualDetails struct {
+	OpenInterest string    `json:"open_interest"`
+	FundingRate  string    `json:"funding_rate"`
+	FundingTime  time.Time `json:"funding_time"`
+}
+
+// FutureProductDetails represents the contract details of a futures product.
+type FutureProductDetails struct {
+	Venue                  string    `json:"venue"`
+	ContractCode           string    `json:"contract_code"`
+	ContractExpiry         time.Time `json:"contract_expiry"`
+	ContractSize           string    `json:"contract_size"`
+	ContractRootUnit       string    `json:"contract_root_unit"`
+	GroupDescription       string    `json:"group_description"`
+	ContractExpiryTimezone string    `json:"contract_expiry_timezone"`
+	GroupShortDescription  string    `json:"group_short_description"`
+	RiskManagedBy          string    `json:"risk_managed_by"`
+	ContractExpiryType     string    `json:"contract_expiry_type"`
+	ContractDisplayName    string    `json:"contract_display_name"`
+
+	// PerpetualDetails is only set for perpetual futures.
+	PerpetualDetails *PerpetualDetails `json:"perpetual_details,omitempty"`
+}
+
+// FCMTradingSessionDetails represents the trading session of a futures product
+// traded through the futures commission merchant.
+type FCMTradingSessionDetails struct {
+	IsSessionOpen bool      `json:"is_session_open"`
+	OpenTime      time.Time `json:"open_time"`
+	CloseTime     time.Time `json:"close_time"`
+}
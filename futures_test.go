@@ -0,0 +1,115 @@
+package coinbase
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGetFuturesBalanceSummary(t *testing.T) {
+	t.Parallel()
+
+	response := `
+{
+  "balance_summary": {
+    "futures_buying_power": {"value": "1000", "currency": "USD"},
+    "total_usd_balance": {"value": "2000", "currency": "USD"},
+    "cbi_usd_balance": {"value": "1500", "currency": "USD"},
+    "cfm_usd_balance": {"value": "500", "currency": "USD"},
+    "total_open_orders_hold_amount": {"value": "50", "currency": "USD"},
+    "unrealized_pnl": {"value": "-12.5", "currency": "USD"},
+    "daily_realized_pnl": {"value": "3.25", "currency": "USD"},
+    "initial_margin": {"value": "250", "currency": "USD"},
+    "available_margin": {"value": "750", "currency": "USD"},
+    "liquidation_threshold": {"value": "125", "currency": "USD"},
+    "liquidation_buffer_amount": {"value": "375", "currency": "USD"},
+    "liquidation_buffer_percentage": "300",
+    "intraday_margin_window_measure": {
+      "margin_window_type": "FCM_MARGIN_WINDOW_TYPE_INTRADAY",
+      "margin_level": "MARGIN_LEVEL_TYPE_BASE",
+      "initial_margin": "125",
+      "maintenance_margin": "100",
+      "liquidation_buffer_percentage": "400",
+      "total_hold": "50",
+      "futures_buying_power": "1250"
+    },
+    "overnight_margin_window_measure": {
+      "margin_window_type": "FCM_MARGIN_WINDOW_TYPE_OVERNIGHT",
+      "margin_level": "MARGIN_LEVEL_TYPE_BASE",
+      "initial_margin": "250",
+      "maintenance_margin": "200",
+      "liquidation_buffer_percentage": "300",
+      "total_hold": "50",
+      "futures_buying_power": "1000"
+    }
+  }
+}`
+
+	var gotPath string
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			gotPath = req.URL.Path
+
+			return newMockResponse(http.StatusOK, response), nil
+		}),
+	}
+
+	got, err := client.GetFuturesBalanceSummary(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get futures balance summary: %v", err)
+	}
+
+	if want := "/api/v3/brokerage/cfm/balance_summary"; gotPath != want {
+		t.Fatalf("got path %q, want %q", gotPath, want)
+	}
+
+	usd := func(value string) Money {
+		return Money{Value: value, Currency: "USD"}
+	}
+
+	want := &FuturesBalanceSummary{
+		FuturesBuyingPower:          usd("1000"),
+		TotalUSDBalance:             usd("2000"),
+		CBIUSDBalance:               usd("1500"),
+		CFMUSDBalance:               usd("500"),
+		TotalOpenOrdersHoldAmount:   usd("50"),
+		UnrealizedPNL:               usd("-12.5"),
+		DailyRealizedPNL:            usd("3.25"),
+		InitialMargin:               usd("250"),
+		AvailableMargin:             usd("750"),
+		LiquidationThreshold:        usd("125"),
+		LiquidationBufferAmount:     usd("375"),
+		LiquidationBufferPercentage: "300",
+		IntradayMarginWindowMeasure: MarginWindowMeasure{
+			MarginWindowType:            MarginWindowTypeIntraday,
+			MarginLevel:                 "MARGIN_LEVEL_TYPE_BASE",
+			InitialMargin:               "125",
+			MaintenanceMargin:           "100",
+			LiquidationBufferPercentage: "400",
+			TotalHold:                   "50",
+			FuturesBuyingPower:          "1250",
+		},
+		OvernightMarginWindowMeasure: MarginWindowMeasure{
+			MarginWindowType:            MarginWindowTypeOvernight,
+			MarginLevel:                 "MARGIN_LEVEL_TYPE_BASE",
+			InitialMargin:               "250",
+			MaintenanceMargin:           "200",
+			LiquidationBufferPercentage: "300",
+			TotalHold:                   "50",
+			FuturesBuyingPower:          "1000",
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	// Strict decoding fails on any field that is not modeled.
+	client.strictDecoding = true
+
+	if _, err := client.GetFuturesBalanceSummary(context.Background()); err != nil {
+		t.Fatalf("failed to strictly decode futures balance summary: %v", err)
+	}
+}
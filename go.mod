@@ -2,4 +2,8 @@ module github.com/alpstable/coinbase
 
 go 1.19
 
-require github.com/google/uuid v1.3.0
+require (
+	github.com/google/uuid v1.3.0
+	github.com/gorilla/websocket v1.5.0
+	github.com/shopspring/decimal v1.3.1
+)
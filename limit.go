@@ -0,0 +1,76 @@
+package coinbase
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrLimitExceeded is returned by list methods when the requested page limit
+// is greater than the endpoint's documented maximum and the client's limit
+// policy is LimitPolicyError.
+var ErrLimitExceeded = errors.New("limit exceeds maximum")
+
+// The documented maximum page limits for each list endpoint.
+const (
+	maxAccountsLimit int32 = 250
+	maxOrdersLimit   int32 = 1000
+	maxFillsLimit    int32 = 1000
+
+	maxMarketTradesLimit int32 = 1000
+)
+
+// LimitPolicy determines how list methods handle a page limit that is greater
+// than the endpoint's documented maximum, instead of leaving the API to
+// truncate the page silently.
+type LimitPolicy int
+
+const (
+	// LimitPolicyError rejects the request with ErrLimitExceeded. This is
+	// the default policy.
+	LimitPolicyError LimitPolicy = iota
+
+	// LimitPolicyClamp reduces the limit to the endpoint's maximum.
+	LimitPolicyClamp
+)
+
+// WithLimitPolicy sets how list methods handle a page limit that is greater
+// than the endpoint's maximum.
+func WithLimitPolicy(policy LimitPolicy) ClientOption {
+	return func(client *Client) {
+		client.limitPolicy = policy
+	}
+}
+
+// WithDefaultPageSize sets the page limit requested by the cursor-paged list
+// methods, for accounts, orders, fills and market trades, when a call does not
+// set one, instead of leaving each endpoint to its own default. The page size
+// is reduced to each endpoint's maximum, whatever the limit policy. Products
+// are not paged by default, so ListProducts is not affected.
+func WithDefaultPageSize(n int32) ClientOption {
+	return func(client *Client) {
+		client.defaultPageSize = n
+	}
+}
+
+// checkLimit returns the limit to request from an endpoint with the given
+// maximum, according to the client's limit policy. An unset limit is replaced
+// by the client's default page size, if it has one.
+func (client *Client) checkLimit(limit, maxLimit int32) (int32, error) {
+	if limit <= 0 && client.defaultPageSize > 0 {
+		if client.defaultPageSize > maxLimit {
+			return maxLimit, nil
+		}
+
+		return client.defaultPageSize, nil
+	}
+
+	if limit <= maxLimit {
+		return limit, nil
+	}
+
+	if client.limitPolicy == LimitPolicyClamp {
+		return maxLimit, nil
+	}
+
+	return 0, fmt.Errorf("%w: %d is greater than the maximum of %d", ErrLimitExceeded, limit, maxLimit)
+}
@@ -0,0 +1,141 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestCheckLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		policy   LimitPolicy
+		pageSize int32
+		limit    int32
+		want     int32
+		err      error
+	}{
+		{name: "unset", limit: 0, want: 0},
+		{name: "below maximum", limit: 299, want: 299},
+		{name: "maximum", limit: 300, want: 300},
+		{name: "above maximum", limit: 301, err: ErrLimitExceeded},
+		{name: "clamp maximum", policy: LimitPolicyClamp, limit: 300, want: 300},
+		{name: "clamp above maximum", policy: LimitPolicyClamp, limit: 301, want: 300},
+		{name: "default page size", pageSize: 50, limit: 0, want: 50},
+		{name: "default page size overridden", pageSize: 50, limit: 20, want: 20},
+		{name: "default page size above maximum", pageSize: 500, limit: 0, want: 300},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{limitPolicy: test.policy, defaultPageSize: test.pageSize}
+
+			got, err := client.checkLimit(test.limit, 300)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if got != test.want {
+				t.Fatalf("got %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestListLimits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		maxLimit int32
+		list     func(ctx context.Context, client *Client, limit int32) error
+	}{
+		{
+			name:     "accounts",
+			maxLimit: maxAccountsLimit,
+			list: func(ctx context.Context, client *Client, limit int32) error {
+				_, err := client.ListAccounts(ctx, ListAccountsParams{Limit: limit})
+
+				return err
+			},
+		},
+		{
+			name:     "orders",
+			maxLimit: maxOrdersLimit,
+			list: func(ctx context.Context, client *Client, limit int32) error {
+				_, err := client.ListOrders(ctx, ListOrdersParams{Limit: limit})
+
+				return err
+			},
+		},
+		{
+			name:     "fills",
+			maxLimit: maxFillsLimit,
+			list: func(ctx context.Context, client *Client, limit int32) error {
+				_, err := client.ListFills(ctx, ListFillsParams{Limit: limit})
+
+				return err
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var sent string
+
+			client := &Client{
+				httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+					sent = req.URL.Query().Get("limit")
+
+					return newMockResponse(http.StatusOK, `{}`), nil
+				}),
+			}
+
+			ctx := context.Background()
+			maxLimit := strconv.Itoa(int(test.maxLimit))
+
+			if err := test.list(ctx, client, test.maxLimit); err != nil || sent != maxLimit {
+				t.Fatalf("got limit %q and error %v, want %q", sent, err, maxLimit)
+			}
+
+			if err := test.list(ctx, client, test.maxLimit+1); !errors.Is(err, ErrLimitExceeded) {
+				t.Fatalf("got %v, want %v", err, ErrLimitExceeded)
+			}
+
+			client.limitPolicy = LimitPolicyClamp
+
+			if err := test.list(ctx, client, test.maxLimit+1); err != nil || sent != maxLimit {
+				t.Fatalf("got limit %q and error %v, want %q", sent, err, maxLimit)
+			}
+
+			client.limitPolicy = LimitPolicyError
+			client.defaultPageSize = 10
+
+			if err := test.list(ctx, client, 0); err != nil || sent != "10" {
+				t.Fatalf("got default limit %q and error %v, want %q", sent, err, "10")
+			}
+
+			if err := test.list(ctx, client, 5); err != nil || sent != "5" {
+				t.Fatalf("got overridden limit %q and error %v, want %q", sent, err, "5")
+			}
+
+			client.defaultPageSize = test.maxLimit + 1
+
+			if err := test.list(ctx, client, 0); err != nil || sent != maxLimit {
+				t.Fatalf("got default limit %q and error %v, want it clamped to %q", sent, err, maxLimit)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package coinbase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrCurrencyMismatch is returned when adding or subtracting money in
+// different currencies.
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// Money represents an amount of a currency. The value is a decimal string, as
+// returned by the Coinbase API.
+type Money struct {
+	Value    string `json:"value"`
+	Currency string `json:"currency"`
+}
+
+// Amount returns the value of the money as a decimal. Values in scientific
+// notation, such as "1.0E-7" for low-priced assets, are accepted.
+func (money Money) Amount() (decimal.Decimal, error) {
+	amount, err := decimal.NewFromString(money.Value)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to parse %s amount %q: %w", money.Currency, money.Value, err)
+	}
+
+	return amount, nil
+}
+
+// Add returns the sum of the money and other, which must be in the same
+// currency, matched case-insensitively. The zero Money, such as an absent hold,
+// counts as zero in any currency.
+func (money Money) Add(other Money) (Money, error) {
+	return money.combine(other, decimal.Decimal.Add)
+}
+
+// Sub returns the money less other, which must be in the same currency, as by
+// Add.
+func (money Money) Sub(other Money) (Money, error) {
+	return money.combine(other, decimal.Decimal.Sub)
+}
+
+// combine applies the operation to the amounts of the money and other.
+func (money Money) combine(other Money,
+	operation func(decimal.Decimal, decimal.Decimal) decimal.Decimal,
+) (Money, error) {
+	currency := money.Currency
+
+	switch {
+	case money == Money{}:
+		currency = other.Currency
+	case other == Money{}:
+	case !strings.EqualFold(money.Currency, other.Currency):
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, money.Currency, other.Currency)
+	}
+
+	amount, err := money.amountOrZero()
+	if err != nil {
+		return Money{}, err
+	}
+
+	otherAmount, err := other.amountOrZero()
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{Value: operation(amount, otherAmount).String(), Currency: currency}, nil
+}
+
+// amountOrZero is like Amount, but returns zero for an empty value.
+func (money Money) amountOrZero() (decimal.Decimal, error) {
+	if money.Value == "" {
+		return decimal.Zero, nil
+	}
+
+	return money.Amount()
+}
@@ -0,0 +1,111 @@
+package coinbase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMoneyAmount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value string
+		want  decimal.Decimal
+		err   bool
+	}{
+		{value: "1.23", want: decimal.RequireFromString("1.23")},
+		{value: "0", want: decimal.Zero},
+		{value: "-0.5", want: decimal.RequireFromString("-0.5")},
+		{value: "1.0E-7", want: decimal.RequireFromString("0.0000001")},
+		{value: "1e8", want: decimal.RequireFromString("100000000")},
+		{value: "2.5e-10", want: decimal.RequireFromString("0.00000000025")},
+		{value: "-3E-2", want: decimal.RequireFromString("-0.03")},
+		{value: "1E", err: true},
+		{value: "", err: true},
+		{value: "abc", err: true},
+	}
+
+	for _, test := range tests {
+		got, err := Money{Value: test.value, Currency: "BTC"}.Amount()
+		if (err != nil) != test.err {
+			t.Fatalf("%q: got error %v, want error %t", test.value, err, test.err)
+		}
+
+		if !got.Equal(test.want) {
+			t.Fatalf("%q: got %s, want %s", test.value, got, test.want)
+		}
+
+		if got.String() != test.want.String() {
+			t.Fatalf("%q: got string %s, want %s", test.value, got, test.want)
+		}
+	}
+}
+
+func TestMoneyAddSub(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		money   Money
+		other   Money
+		wantAdd Money
+		wantSub Money
+		err     error
+	}{
+		{
+			name:    "matching currencies",
+			money:   Money{Value: "1.5", Currency: "BTC"},
+			other:   Money{Value: "0.25", Currency: "btc"},
+			wantAdd: Money{Value: "1.75", Currency: "BTC"},
+			wantSub: Money{Value: "1.25", Currency: "BTC"},
+		},
+		{
+			name:    "zero money",
+			money:   Money{},
+			other:   Money{Value: "2", Currency: "ETH"},
+			wantAdd: Money{Value: "2", Currency: "ETH"},
+			wantSub: Money{Value: "-2", Currency: "ETH"},
+		},
+		{
+			name:    "empty value",
+			money:   Money{Value: "3", Currency: "USD"},
+			other:   Money{Currency: "USD"},
+			wantAdd: Money{Value: "3", Currency: "USD"},
+			wantSub: Money{Value: "3", Currency: "USD"},
+		},
+		{
+			name:  "mismatched currencies",
+			money: Money{Value: "1", Currency: "BTC"},
+			other: Money{Value: "1", Currency: "ETH"},
+			err:   ErrCurrencyMismatch,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotAdd, err := test.money.Add(test.other)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("add: got %v, want %v", err, test.err)
+			}
+
+			if gotAdd != test.wantAdd {
+				t.Fatalf("add: got %+v, want %+v", gotAdd, test.wantAdd)
+			}
+
+			gotSub, err := test.money.Sub(test.other)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("sub: got %v, want %v", err, test.err)
+			}
+
+			if gotSub != test.wantSub {
+				t.Fatalf("sub: got %+v, want %+v", gotSub, test.wantSub)
+			}
+		})
+	}
+}
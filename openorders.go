@@ -0,0 +1,234 @@
+package coinbase
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultReconcileInterval is the reconcile interval used by an OpenOrdersView
+// when a non-positive interval is given.
+const defaultReconcileInterval = time.Minute
+
+// OpenOrdersView keeps a view of the user's open orders, seeded from
+// ListOrders, kept current by the updates of the user WebSocket channel, and
+// periodically reconciled with ListOrders to heal any update that was missed,
+// such as while the WebSocket client was reconnecting.
+type OpenOrdersView struct {
+	done chan struct{}
+
+	// mu guards the fields below.
+	mu sync.Mutex
+
+	// orders are the open orders by order ID, and closed the time at
+	// which each order that an update closed was closed.
+	orders map[string]viewOrder
+	closed map[string]time.Time
+	err    error
+}
+
+// viewOrder is an order in an OpenOrdersView.
+type viewOrder struct {
+	order HistoricalOrder
+
+	// updated is when the order was last changed by an update, zero if it
+	// is as listed by the last reconcile.
+	updated time.Time
+}
+
+// WatchOpenOrders returns a view of the user's open orders. The view is seeded
+// from ListOrders before WatchOpenOrders returns, then applies the messages of
+// the user channel received from updates and reconciles with ListOrders every
+// interval, until the context is cancelled. Messages from other channels are
+// ignored, so updates can be the Messages channel of a WSClient subscribed to
+// the user channel. If updates is closed, the view is kept by reconciling
+// only.
+//
+// An order changed by an update after a reconcile started keeps the update's
+// state, since the listed state may predate it. Orders added by an update
+// have the fields of a UserOrder only, until the next reconcile lists them.
+func (client *Client) WatchOpenOrders(ctx context.Context, updates <-chan WSMessage, interval time.Duration,
+	opts ...CallOption,
+) (*OpenOrdersView, error) {
+	if interval <= 0 {
+		interval = defaultReconcileInterval
+	}
+
+	view := &OpenOrdersView{
+		done:   make(chan struct{}),
+		orders: make(map[string]viewOrder),
+		closed: make(map[string]time.Time),
+	}
+
+	if err := view.reconcile(ctx, client, opts); err != nil {
+		return nil, err
+	}
+
+	go view.run(ctx, client, updates, interval, opts)
+
+	return view, nil
+}
+
+// OpenOrders returns a snapshot of the open orders, oldest first.
+func (view *OpenOrdersView) OpenOrders() []HistoricalOrder {
+	view.mu.Lock()
+	defer view.mu.Unlock()
+
+	orders := make([]HistoricalOrder, 0, len(view.orders))
+	for _, entry := range view.orders {
+		orders = append(orders, entry.order)
+	}
+
+	sort.Slice(orders, func(i, j int) bool {
+		if !orders[i].CreatedTime.Equal(orders[j].CreatedTime) {
+			return orders[i].CreatedTime.Before(orders[j].CreatedTime)
+		}
+
+		return orders[i].OrderID < orders[j].OrderID
+	})
+
+	return orders
+}
+
+// Err returns the error of the last reconcile, or nil if it succeeded. The view
+// is still kept by updates while reconciling fails.
+func (view *OpenOrdersView) Err() error {
+	view.mu.Lock()
+	defer view.mu.Unlock()
+
+	return view.err
+}
+
+// Done returns a channel that is closed once the view has stopped, after its
+// context is cancelled.
+func (view *OpenOrdersView) Done() <-chan struct{} {
+	return view.done
+}
+
+// run applies updates and reconciles every interval until the context is
+// cancelled.
+func (view *OpenOrdersView) run(ctx context.Context, client *Client, updates <-chan WSMessage,
+	interval time.Duration, opts []CallOption,
+) {
+	defer close(view.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-updates:
+			if !ok {
+				updates = nil
+
+				continue
+			}
+
+			if msg.Channel == string(WSChannelUser) {
+				view.apply(msg)
+			}
+		case <-ticker.C:
+			err := view.reconcile(ctx, client, opts)
+
+			view.mu.Lock()
+			view.err = err
+			view.mu.Unlock()
+		}
+	}
+}
+
+// reconcile replaces the view with the open orders listed by ListOrders, except
+// for the orders changed by an update since the listing started.
+func (view *OpenOrdersView) reconcile(ctx context.Context, client *Client, opts []CallOption) error {
+	started := time.Now()
+
+	var listed []HistoricalOrder
+
+	pager := client.OpenOrdersPager(ctx, "", opts...)
+	for pager.Next() {
+		listed = append(listed, pager.Order())
+	}
+
+	if err := pager.Err(); err != nil {
+		return err
+	}
+
+	view.mu.Lock()
+	defer view.mu.Unlock()
+
+	orders := make(map[string]viewOrder, len(listed))
+
+	for id, entry := range view.orders {
+		if entry.updated.After(started) {
+			orders[id] = entry
+		}
+	}
+
+	for _, order := range listed {
+		if closed, ok := view.closed[order.OrderID]; ok && closed.After(started) {
+			continue
+		}
+
+		if _, ok := orders[order.OrderID]; !ok {
+			orders[order.OrderID] = viewOrder{order: order}
+		}
+	}
+
+	// Orders closed before the listing started are not listed, so they no
+	// longer need to be remembered.
+	for id, closed := range view.closed {
+		if !closed.After(started) {
+			delete(view.closed, id)
+		}
+	}
+
+	view.orders = orders
+
+	return nil
+}
+
+// apply applies the order updates of a message on the user channel. Messages
+// whose events cannot be decoded are ignored, the next reconcile heals the
+// view.
+func (view *OpenOrdersView) apply(msg WSMessage) {
+	events, err := msg.UserEvents()
+	if err != nil {
+		return
+	}
+
+	view.mu.Lock()
+	defer view.mu.Unlock()
+
+	for _, event := range events {
+		for _, update := range event.Orders {
+			now := time.Now()
+
+			if update.Status.IsTerminal() {
+				delete(view.orders, update.OrderID)
+				view.closed[update.OrderID] = now
+
+				continue
+			}
+
+			order := view.orders[update.OrderID].order
+			order.OrderID = update.OrderID
+			order.ClientOrderID = update.ClientOrderID
+			order.ProductID = update.ProductID
+			order.Side = update.OrderSide
+			order.OrderType = update.OrderType
+			order.Status = update.Status
+			order.FilledSize = update.CumulativeQuantity
+			order.AverageFilledPrice = update.AvgPrice
+			order.TotalFees = update.TotalFees
+
+			if order.CreatedTime.IsZero() {
+				order.CreatedTime = update.Time
+			}
+
+			view.orders[update.OrderID] = viewOrder{order: order, updated: now}
+		}
+	}
+}
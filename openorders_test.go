@@ -0,0 +1,157 @@
+package coinbase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// openOrderIDs returns the IDs of the view's open orders.
+func openOrderIDs(view *OpenOrdersView) []string {
+	ids := []string{}
+	for _, order := range view.OpenOrders() {
+		ids = append(ids, order.OrderID)
+	}
+
+	return ids
+}
+
+// waitForOpenOrders waits for the view's open orders to have the given IDs.
+func waitForOpenOrders(t *testing.T, view *OpenOrdersView, want []string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !reflect.DeepEqual(openOrderIDs(view), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("got open orders %v, want %v", openOrderIDs(view), want)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWatchOpenOrders(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu     sync.Mutex
+		listed = `{"orders": [
+			{"order_id": "a", "status": "OPEN", "created_time": "2023-07-01T00:00:00Z"},
+			{"order_id": "b", "status": "OPEN", "created_time": "2023-07-01T00:01:00Z"}
+		], "has_next": false}`
+	)
+
+	setListed := func(body string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		listed = body
+	}
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			if got := req.URL.Query().Get("order_status"); got != string(OrderStatusOpen) {
+				t.Errorf("got order status %q, want %q", got, OrderStatusOpen)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			return newMockResponse(http.StatusOK, listed), nil
+		}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	updates := make(chan WSMessage)
+
+	view, err := client.WatchOpenOrders(ctx, updates, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("failed to watch open orders: %v", err)
+	}
+
+	if got, want := openOrderIDs(view), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got seeded orders %v, want %v", got, want)
+	}
+
+	// Order "a" fills and order "c" is placed, which the listing reflects
+	// too.
+	setListed(`{"orders": [
+		{"order_id": "b", "status": "OPEN", "created_time": "2023-07-01T00:01:00Z"},
+		{"order_id": "c", "status": "OPEN", "created_time": "2023-07-01T00:02:00Z"}
+	], "has_next": false}`)
+
+	updates <- WSMessage{Channel: string(WSChannelHeartbeats), Events: json.RawMessage(`[]`)}
+	updates <- WSMessage{
+		Channel: string(WSChannelUser),
+		Events: json.RawMessage(`[{"type": "update", "orders": [
+			{"order_id": "a", "status": "FILLED", "cumulative_quantity": "1"},
+			{"order_id": "c", "status": "OPEN", "creation_time": "2023-07-01T00:02:00Z"}
+		]}]`),
+	}
+
+	waitForOpenOrders(t, view, []string{"b", "c"})
+
+	// Order "d" is placed while the WebSocket feed is down, so only the
+	// reconcile sees it.
+	close(updates)
+	setListed(`{"orders": [
+		{"order_id": "b", "status": "OPEN", "created_time": "2023-07-01T00:01:00Z"},
+		{"order_id": "c", "status": "OPEN", "created_time": "2023-07-01T00:02:00Z"},
+		{"order_id": "d", "status": "OPEN", "created_time": "2023-07-01T00:03:00Z"}
+	], "has_next": false}`)
+
+	waitForOpenOrders(t, view, []string{"b", "c", "d"})
+
+	if err := view.Err(); err != nil {
+		t.Fatalf("got reconcile error %v, want none", err)
+	}
+
+	cancel()
+
+	select {
+	case <-view.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("view still running after the context was cancelled")
+	}
+}
+
+func TestOpenOrdersViewReconcileKeepsNewerUpdates(t *testing.T) {
+	t.Parallel()
+
+	// The listing predates the updates applied while it was requested: it
+	// still lists "a", which an update filled, and misses "b", which an
+	// update placed.
+	view := &OpenOrdersView{
+		done:   make(chan struct{}),
+		orders: make(map[string]viewOrder),
+		closed: make(map[string]time.Time),
+	}
+
+	client := &Client{
+		httpClient: mockDoFunc(func(*http.Request) (*http.Response, error) {
+			view.apply(WSMessage{
+				Channel: string(WSChannelUser),
+				Events: json.RawMessage(`[{"type": "update", "orders": [
+					{"order_id": "a", "status": "FILLED"},
+					{"order_id": "b", "status": "OPEN"}
+				]}]`),
+			})
+
+			return newMockResponse(http.StatusOK, `{"orders": [{"order_id": "a", "status": "OPEN"}]}`), nil
+		}),
+	}
+
+	if err := view.reconcile(context.Background(), client, nil); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+
+	if got, want := openOrderIDs(view), []string{"b"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got open orders %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,258 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ErrResponseTooLarge is returned when a response body is larger than the
+// limit set with WithMaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithTimeout sets the timeout applied to each request made by the client.
+// The timeout can be overridden for a single request with WithCallTimeout.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(client *Client) {
+		client.timeout = timeout
+	}
+}
+
+// WithoutAutoSign creates the client without signing its requests, so that
+// the "cb-access-*" authentication headers can be added by a proxy that the
+// requests are sent through instead. The API key and secret are ignored and
+// may be empty.
+func WithoutAutoSign() ClientOption {
+	return func(client *Client) {
+		client.withoutAutoSign = true
+	}
+}
+
+// WithTransport sets the HTTP transport that requests are sent with, after they
+// have been signed. It can be used to route requests through a proxy or to
+// inject failures in tests, see the coinbasetest package.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(client *Client) {
+		client.transport = transport
+	}
+}
+
+// WithProxy sends requests through the HTTP proxy at the given URL. Without it,
+// the proxy is read from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment
+// variables, as by the default HTTP transport. The proxy is applied to the
+// transport set WithTransport if it is an *http.Transport, which is cloned,
+// and otherwise that transport is responsible for proxying.
+func WithProxy(proxy *url.URL) ClientOption {
+	return func(client *Client) {
+		client.proxy = proxy
+	}
+}
+
+// baseTransport returns the transport that the client's requests are sent
+// with once they are signed: the transport set WithTransport, or the default
+// HTTP transport, routed through the client's proxy if it has one. Nil means
+// the default HTTP transport.
+func (client *Client) baseTransport() http.RoundTripper {
+	if client.proxy == nil {
+		return client.transport
+	}
+
+	transport := client.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	httpTransport, ok := transport.(*http.Transport)
+	if !ok {
+		return transport
+	}
+
+	httpTransport = httpTransport.Clone()
+	httpTransport.Proxy = http.ProxyURL(client.proxy)
+
+	return httpTransport
+}
+
+// WithSigningPath sets a function that transforms the request path before it
+// is signed, for proxies that rewrite the path of the requests they forward.
+// The signature must be computed over the path that Coinbase receives, while
+// the request is still sent to the original path. The query string is not
+// passed to the function.
+func WithSigningPath(transform func(path string) string) ClientOption {
+	return func(client *Client) {
+		client.signingPath = transform
+	}
+}
+
+// WithSigningPathPrefix signs requests as if their path started with the
+// prefix, for gateways that add the prefix to the path of the requests they
+// forward to Coinbase.
+func WithSigningPathPrefix(prefix string) ClientOption {
+	return WithSigningPath(func(path string) string {
+		return prefix + path
+	})
+}
+
+// WithSignQueryParams sets whether the query string of a request is included in
+// the path that it is signed over, which it is by default. Requests are still
+// sent with their query string, so a key that is rejected as unauthorized on
+// endpoints taking query parameters can be tried with them left out of the
+// signature.
+func WithSignQueryParams(include bool) ClientOption {
+	return func(client *Client) {
+		client.unsignedQuery = !include
+	}
+}
+
+// WithRequestInspector calls inspect with the method, signed path and the
+// exact message signed for each request, which is the concatenation of the
+// timestamp, method, path and body, to debug requests that are rejected as
+// unauthorized. Nothing is redacted: the message does not contain the secret,
+// but it does contain the full request body, so the inspector should not be
+// left logging in production.
+func WithRequestInspector(inspect func(method, path, signedMessage string)) ClientOption {
+	return func(client *Client) {
+		client.inspectRequest = inspect
+	}
+}
+
+// TimestampResolution is the resolution of the timestamp that requests are
+// signed with.
+type TimestampResolution int
+
+const (
+	// TimestampSeconds signs requests with the Unix time in seconds, which
+	// is what Coinbase expects. It is the default.
+	TimestampSeconds TimestampResolution = iota
+
+	// TimestampMillis signs requests with the Unix time in milliseconds.
+	TimestampMillis
+)
+
+// format returns the Unix time of t at the resolution.
+func (resolution TimestampResolution) format(t time.Time) string {
+	formatBase := 10
+	if resolution == TimestampMillis {
+		return strconv.FormatInt(t.UnixMilli(), formatBase)
+	}
+
+	return strconv.FormatInt(t.Unix(), formatBase)
+}
+
+// WithTimestampResolution sets the resolution of the timestamp sent in the
+// "cb-access-timestamp" header and signed with each request, in case Coinbase
+// requires a finer one than seconds, which are used by default.
+func WithTimestampResolution(resolution TimestampResolution) ClientOption {
+	return func(client *Client) {
+		client.timestampResolution = resolution
+	}
+}
+
+// WithMaxResponseBytes limits the size of the response bodies that are read,
+// for both decoded and error responses, so that a faulty proxy cannot exhaust
+// memory with a huge body. A request whose response body is larger than n
+// bytes fails with ErrResponseTooLarge. By default bodies are not limited.
+func WithMaxResponseBytes(n int64) ClientOption {
+	return func(client *Client) {
+		client.maxResponseBytes = n
+	}
+}
+
+// maxBytesReader reads up to "remaining" bytes from the reader, returning
+// ErrResponseTooLarge once it has more.
+type maxBytesReader struct {
+	reader    io.Reader
+	remaining int64
+	limit     int64
+}
+
+// Read implements the "io.Reader" interface.
+func (reader *maxBytesReader) Read(buf []byte) (int, error) {
+	// Read one byte more than remains, to tell a body that is exactly
+	// at the limit from one that is over it.
+	if int64(len(buf)) > reader.remaining+1 {
+		buf = buf[:reader.remaining+1]
+	}
+
+	n, err := reader.reader.Read(buf)
+	if int64(n) > reader.remaining {
+		n = int(reader.remaining)
+		reader.remaining = 0
+
+		return n, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, reader.limit)
+	}
+
+	reader.remaining -= int64(n)
+
+	return n, err //nolint:wrapcheck
+}
+
+// WithStrictDecoding makes responses with fields that this package does not
+// model fail to decode, so that changes to the API's responses are noticed.
+// It is meant for tests and debugging, since by default unknown fields are
+// ignored so that new fields do not break the client.
+func WithStrictDecoding() ClientOption {
+	return func(client *Client) {
+		client.strictDecoding = true
+	}
+}
+
+// callOptions are the settings for a single call to the Coinbase API.
+type callOptions struct {
+	timeout time.Duration
+}
+
+// CallOption configures a single call to the Coinbase API.
+type CallOption func(*callOptions)
+
+// WithCallTimeout overrides the client's timeout for a single call. If the
+// call's context has an earlier deadline, the context's deadline is used
+// instead.
+func WithCallTimeout(timeout time.Duration) CallOption {
+	return func(opts *callOptions) {
+		opts.timeout = timeout
+	}
+}
+
+// DefaultContext returns a background context that is cancelled once the
+// client's timeout elapses, see WithTimeout, so that calls made with it are
+// bounded even when they are made up of several requests, such as a pager. If
+// the client has no timeout the context has no deadline. The cancel function
+// should be called once the calls are done to release the context's
+// resources.
+func (client *Client) DefaultContext() (context.Context, context.CancelFunc) {
+	return client.newCallOptions(nil).withTimeout(context.Background())
+}
+
+// newCallOptions returns the settings for a call, starting from the client's
+// defaults and applying the given options in order.
+func (client *Client) newCallOptions(opts []CallOption) *callOptions {
+	callOpts := &callOptions{
+		timeout: client.timeout,
+	}
+
+	for _, opt := range opts {
+		opt(callOpts)
+	}
+
+	return callOpts
+}
+
+// withTimeout returns a copy of the context that is cancelled once the
+// call's timeout elapses. If the call has no timeout, the context is returned
+// unchanged.
+func (opts *callOptions) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if opts.timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, opts.timeout)
+}
@@ -0,0 +1,618 @@
+package coinbase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithCallTimeout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		clientTimeout time.Duration
+		ctxTimeout    time.Duration
+		opts          []CallOption
+		want          time.Duration // zero means no deadline
+	}{
+		{
+			name: "no timeout",
+		},
+		{
+			name:          "client timeout",
+			clientTimeout: time.Minute,
+			want:          time.Minute,
+		},
+		{
+			name:          "call timeout shorter than client timeout",
+			clientTimeout: time.Hour,
+			opts:          []CallOption{WithCallTimeout(time.Minute)},
+			want:          time.Minute,
+		},
+		{
+			name:          "call timeout longer than client timeout",
+			clientTimeout: time.Minute,
+			opts:          []CallOption{WithCallTimeout(time.Hour)},
+			want:          time.Hour,
+		},
+		{
+			name:       "context deadline shorter than call timeout",
+			ctxTimeout: time.Minute,
+			opts:       []CallOption{WithCallTimeout(time.Hour)},
+			want:       time.Minute,
+		},
+		{
+			name:       "call timeout shorter than context deadline",
+			ctxTimeout: time.Hour,
+			opts:       []CallOption{WithCallTimeout(time.Minute)},
+			want:       time.Minute,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				deadline    time.Time
+				hasDeadline bool
+			)
+
+			client := &Client{
+				httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+					deadline, hasDeadline = req.Context().Deadline()
+
+					return newMockResponse(http.StatusOK, `{}`), nil
+				}),
+				timeout: test.clientTimeout,
+			}
+
+			ctx := context.Background()
+
+			if test.ctxTimeout > 0 {
+				var cancel context.CancelFunc
+
+				ctx, cancel = context.WithTimeout(ctx, test.ctxTimeout)
+				defer cancel()
+			}
+
+			if _, err := client.Accounts(ctx, test.opts...); err != nil {
+				t.Fatalf("failed to get accounts: %v", err)
+			}
+
+			if test.want == 0 {
+				if hasDeadline {
+					t.Fatalf("got deadline %v, want none", deadline)
+				}
+
+				return
+			}
+
+			if !hasDeadline {
+				t.Fatalf("got no deadline, want %v", test.want)
+			}
+
+			if got := time.Until(deadline); got > test.want || got < test.want-time.Second {
+				t.Fatalf("got deadline in %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWithoutAutoSign(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewClient("", ""); !errors.Is(err, errInvalidRoundTripArgs) {
+		t.Fatalf("got %v, want %v", err, errInvalidRoundTripArgs)
+	}
+
+	client, err := NewClient("", "", WithoutAutoSign())
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	httpClient, ok := client.httpClient.(*http.Client)
+	if !ok || httpClient.Transport != nil {
+		t.Fatalf("got HTTP client %#v, want one with the default transport", client.httpClient)
+	}
+
+	var header http.Header
+
+	httpClient.Transport = &roundTripper{roundTrip: func(req *http.Request) (*http.Response, error) {
+		header = req.Header
+
+		return newMockResponse(http.StatusOK, `{"accounts": []}`), nil
+	}}
+
+	if _, err := client.Accounts(context.Background()); err != nil {
+		t.Fatalf("failed to list accounts: %v", err)
+	}
+
+	for _, key := range []string{"cb-access-key", "cb-access-sign", "cb-access-timestamp"} {
+		if value := header.Get(key); value != "" {
+			t.Fatalf("got %s header %q, want none", key, value)
+		}
+	}
+}
+
+func TestWithStrictDecoding(t *testing.T) {
+	t.Parallel()
+
+	response := []byte(`{"accounts": [{"uuid": "a", "new_field": "value"}]}`)
+
+	lenient := &Client{
+		httpClient: &mockClient{response: response, statusCode: http.StatusOK},
+	}
+
+	accounts, err := lenient.Accounts(context.Background())
+	if err != nil {
+		t.Fatalf("failed to decode with an unknown field: %v", err)
+	}
+
+	if len(accounts.Data) != 1 || accounts.Data[0].UUID != "a" {
+		t.Fatalf("got accounts %+v", accounts)
+	}
+
+	strict := &Client{
+		httpClient: &mockClient{response: response, statusCode: http.StatusOK},
+	}
+
+	WithStrictDecoding()(strict)
+
+	if _, err := strict.Accounts(context.Background()); err == nil || !strings.Contains(err.Error(), "new_field") {
+		t.Fatalf("got %v, want an unknown field error", err)
+	}
+}
+
+func TestWithSigningPathPrefix(t *testing.T) {
+	t.Parallel()
+
+	var req *http.Request
+
+	transport := &roundTripper{roundTrip: func(r *http.Request) (*http.Response, error) {
+		req = r
+
+		return newMockResponse(http.StatusOK, `{"accounts": []}`), nil
+	}}
+
+	client, err := NewClient("key", "secret", WithSigningPathPrefix("/gateway"), WithTransport(transport))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if _, err := client.Accounts(context.Background()); err != nil {
+		t.Fatalf("failed to list accounts: %v", err)
+	}
+
+	if want := "/api/v3/brokerage/accounts"; req.URL.Path != want {
+		t.Fatalf("got path %q, want %q", req.URL.Path, want)
+	}
+
+	signed := "/gateway/api/v3/brokerage/accounts"
+	if req.URL.RawQuery != "" {
+		signed += "?" + req.URL.RawQuery
+	}
+
+	msg := req.Header.Get("cb-access-timestamp") + http.MethodGet + signed
+	if got, want := req.Header.Get("cb-access-sign"), sign("secret", msg); got != want {
+		t.Fatalf("got signature %q, want %q", got, want)
+	}
+}
+
+func TestDefaultContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := (&Client{timeout: time.Minute}).DefaultContext()
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		t.Fatal("got no deadline, want one in a minute")
+	}
+
+	if got := time.Until(deadline); got > time.Minute || got < time.Minute-time.Second {
+		t.Fatalf("got deadline in %v, want %v", got, time.Minute)
+	}
+
+	ctx, cancel = (&Client{}).DefaultContext()
+	defer cancel()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		t.Fatalf("got deadline %v, want none without a client timeout", deadline)
+	}
+}
+
+// rotatingProvider is a CredentialProvider that returns the next of its
+// secrets on each call.
+type rotatingProvider struct {
+	secrets []string
+	calls   int32
+}
+
+func (provider *rotatingProvider) Credentials(context.Context) (string, string, error) {
+	calls := atomic.AddInt32(&provider.calls, 1)
+
+	return "key", provider.secrets[int(calls-1)%len(provider.secrets)], nil
+}
+
+func TestWithCredentialProvider(t *testing.T) {
+	t.Parallel()
+
+	provider := &rotatingProvider{secrets: []string{"secret-1", "secret-2"}}
+
+	var req *http.Request
+
+	transport := &roundTripper{roundTrip: func(r *http.Request) (*http.Response, error) {
+		req = r
+
+		return newMockResponse(http.StatusOK, `{"accounts": []}`), nil
+	}}
+
+	client, err := NewClient("", "", WithCredentialProvider(provider), WithTransport(transport))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// signedWith returns the secret that the last request was signed with.
+	signedWith := func() string {
+		msg := req.Header.Get("cb-access-timestamp") + req.Method + req.URL.Path
+
+		for _, secret := range provider.secrets {
+			if sign(secret, msg) == req.Header.Get("cb-access-sign") {
+				return secret
+			}
+		}
+
+		return ""
+	}
+
+	for _, want := range []string{"secret-1", "secret-1"} {
+		if _, err := client.Accounts(context.Background()); err != nil {
+			t.Fatalf("failed to list accounts: %v", err)
+		}
+
+		if got := signedWith(); got != want {
+			t.Fatalf("got request signed with %q, want %q", got, want)
+		}
+	}
+
+	if got := atomic.LoadInt32(&provider.calls); got != 1 {
+		t.Fatalf("got %d calls to the provider within the TTL, want 1", got)
+	}
+
+	client.credentials.mu.Lock()
+	client.credentials.fetched = time.Now().Add(-2 * credentialsTTL)
+	client.credentials.mu.Unlock()
+
+	if _, err := client.Accounts(context.Background()); err != nil {
+		t.Fatalf("failed to list accounts: %v", err)
+	}
+
+	if got, want := signedWith(), "secret-2"; got != want {
+		t.Fatalf("got request signed with %q after the TTL, want %q", got, want)
+	}
+}
+
+func TestWithRequestInspector(t *testing.T) {
+	t.Parallel()
+
+	var (
+		req                         *http.Request
+		method, path, signedMessage string
+	)
+
+	transport := &roundTripper{roundTrip: func(r *http.Request) (*http.Response, error) {
+		req = r
+
+		return newMockResponse(http.StatusOK, `{"success": true}`), nil
+	}}
+
+	inspect := func(m, p, msg string) {
+		method, path, signedMessage = m, p, msg
+	}
+
+	client, err := NewClient("key", "secret", WithRequestInspector(inspect), WithTransport(transport))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	orderReq := OrderRequest{
+		ClientOrderID: "a",
+		Configuration: OrderConfig{MarketIOC: &MarketIOCConfig{QuoteSize: "10"}},
+	}
+
+	if _, err := client.CreateOrder(context.Background(), orderReq); err != nil {
+		t.Fatalf("failed to create order: %v", err)
+	}
+
+	if method != http.MethodPost || path != "/api/v3/brokerage/orders" {
+		t.Fatalf("got %s %s, want %s %s", method, path, http.MethodPost, "/api/v3/brokerage/orders")
+	}
+
+	body, err := json.Marshal(orderReq)
+	if err != nil {
+		t.Fatalf("failed to marshal order request: %v", err)
+	}
+
+	want := req.Header.Get("cb-access-timestamp") + method + path + string(body)
+	if signedMessage != want {
+		t.Fatalf("got signed message %q, want %q", signedMessage, want)
+	}
+
+	if got := req.Header.Get("cb-access-sign"); got != sign("secret", signedMessage) {
+		t.Fatalf("got signature %q, want that of the inspected message", got)
+	}
+}
+
+func TestWithTimestampResolution(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name string
+		opts []ClientOption
+		unit time.Duration
+	}{
+		{name: "default", unit: time.Second},
+		{name: "seconds", opts: []ClientOption{WithTimestampResolution(TimestampSeconds)}, unit: time.Second},
+		{name: "millis", opts: []ClientOption{WithTimestampResolution(TimestampMillis)}, unit: time.Millisecond},
+	} {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				req           *http.Request
+				signedMessage string
+			)
+
+			transport := &roundTripper{roundTrip: func(r *http.Request) (*http.Response, error) {
+				req = r
+
+				return newMockResponse(http.StatusOK, `{"accounts": []}`), nil
+			}}
+
+			inspect := func(_, _, msg string) {
+				signedMessage = msg
+			}
+
+			opts := append([]ClientOption{WithRequestInspector(inspect), WithTransport(transport)}, test.opts...)
+
+			client, err := NewClient("key", "secret", opts...)
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			before := time.Now().Truncate(test.unit)
+
+			if _, err := client.Accounts(context.Background()); err != nil {
+				t.Fatalf("failed to list accounts: %v", err)
+			}
+
+			timestamp := req.Header.Get("cb-access-timestamp")
+
+			units, err := strconv.ParseInt(timestamp, 10, 64)
+			if err != nil {
+				t.Fatalf("failed to parse timestamp %q: %v", timestamp, err)
+			}
+
+			if got := time.Unix(0, units*int64(test.unit)); got.Before(before) || got.After(time.Now()) {
+				t.Fatalf("got timestamp %q, want the time of the request in units of %v", timestamp, test.unit)
+			}
+
+			path := req.URL.Path
+			if req.URL.RawQuery != "" {
+				path += "?" + req.URL.RawQuery
+			}
+
+			if want := timestamp + http.MethodGet + path; signedMessage != want {
+				t.Fatalf("got signed message %q, want %q", signedMessage, want)
+			}
+
+			if got, want := req.Header.Get("cb-access-sign"), sign("secret", signedMessage); got != want {
+				t.Fatalf("got signature %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestWithSignQueryParams(t *testing.T) {
+	t.Parallel()
+
+	const path = "/api/v3/brokerage/orders/historical/batch"
+
+	for _, test := range []struct {
+		name string
+		opts []ClientOption
+		path string
+	}{
+		{name: "default", path: path + "?limit=10"},
+		{name: "included", opts: []ClientOption{WithSignQueryParams(true)}, path: path + "?limit=10"},
+		{name: "omitted", opts: []ClientOption{WithSignQueryParams(false)}, path: path},
+	} {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				req           *http.Request
+				signedMessage string
+			)
+
+			transport := &roundTripper{roundTrip: func(r *http.Request) (*http.Response, error) {
+				req = r
+
+				return newMockResponse(http.StatusOK, `{}`), nil
+			}}
+
+			inspect := func(_, _, msg string) {
+				signedMessage = msg
+			}
+
+			opts := append([]ClientOption{WithRequestInspector(inspect), WithTransport(transport)}, test.opts...)
+
+			client, err := NewClient("key", "secret", opts...)
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			query := url.Values{"limit": []string{"10"}}
+
+			err = client.Do(context.Background(), http.MethodGet, "brokerage/orders/historical/batch", query, nil, nil)
+			if err != nil {
+				t.Fatalf("failed to list orders: %v", err)
+			}
+
+			if got, want := req.URL.RawQuery, "limit=10"; got != want {
+				t.Fatalf("got query %q sent, want %q", got, want)
+			}
+
+			want := req.Header.Get("cb-access-timestamp") + http.MethodGet + test.path
+			if signedMessage != want {
+				t.Fatalf("got signed message %q, want %q", signedMessage, want)
+			}
+
+			if got := req.Header.Get("cb-access-sign"); got != sign("secret", want) {
+				t.Fatalf("got signature %q, want that of %q", got, want)
+			}
+		})
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	t.Parallel()
+
+	proxy := &url.URL{Scheme: "http", Host: "proxy.example.com:3128"}
+
+	// proxyOf returns the proxy that the transport sends requests to
+	// Coinbase through.
+	proxyOf := func(t *testing.T, transport http.RoundTripper) *url.URL {
+		t.Helper()
+
+		httpTransport, ok := transport.(*http.Transport)
+		if !ok {
+			t.Fatalf("got transport %T, want *http.Transport", transport)
+		}
+
+		if httpTransport.Proxy == nil {
+			t.Fatalf("got transport without a proxy func")
+		}
+
+		req, err := http.NewRequest(http.MethodGet, "https://api.coinbase.com/api/v3/brokerage/accounts", nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+
+		got, err := httpTransport.Proxy(req)
+		if err != nil {
+			t.Fatalf("failed to get proxy: %v", err)
+		}
+
+		return got
+	}
+
+	t.Run("environment", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewClient("key", "secret")
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+
+		// Requests are sent with the default transport, which reads
+		// the proxy from the environment.
+		if got := client.signConfig().transport; got != nil {
+			t.Fatalf("got transport %v, want the default transport", got)
+		}
+
+		proxyOf(t, http.DefaultTransport)
+	})
+
+	t.Run("explicit", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewClient("key", "secret", WithProxy(proxy))
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+
+		if got := proxyOf(t, client.signConfig().transport); got.String() != proxy.String() {
+			t.Fatalf("got proxy %v, want %v", got, proxy)
+		}
+	})
+
+	t.Run("custom transport", func(t *testing.T) {
+		t.Parallel()
+
+		transport := &http.Transport{}
+
+		client, err := NewClient("", "", WithoutAutoSign(), WithTransport(transport), WithProxy(proxy))
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+
+		httpClient, ok := client.httpClient.(*http.Client)
+		if !ok {
+			t.Fatalf("got HTTP client %T, want *http.Client", client.httpClient)
+		}
+
+		if got := proxyOf(t, httpClient.Transport); got.String() != proxy.String() {
+			t.Fatalf("got proxy %v, want %v", got, proxy)
+		}
+
+		if transport.Proxy != nil {
+			t.Fatalf("got the given transport modified, want it cloned")
+		}
+	})
+}
+
+func TestWithMaxResponseBytes(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"accounts": [{"uuid": "` + strings.Repeat("a", 64) + `"}]}`)
+
+	tests := []struct {
+		name       string
+		statusCode int
+		maxBytes   int64
+		err        error
+	}{
+		{name: "oversized", statusCode: http.StatusOK, maxBytes: 32, err: ErrResponseTooLarge},
+		{name: "oversized error", statusCode: http.StatusBadGateway, maxBytes: 32, err: ErrResponseTooLarge},
+		{name: "at the limit", statusCode: http.StatusOK, maxBytes: int64(len(body))},
+		{name: "error at the limit", statusCode: http.StatusBadGateway, maxBytes: int64(len(body)), err: ErrStatusNotOK},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{
+				httpClient:       &mockClient{response: body, statusCode: test.statusCode},
+				maxResponseBytes: test.maxBytes,
+			}
+
+			_, err := client.Accounts(context.Background())
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if errors.Is(test.err, ErrStatusNotOK) && errors.Is(err, ErrResponseTooLarge) {
+				t.Fatalf("got %v, want a status error with the whole body", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package coinbase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// OrderByQuote places a market order for the given amount of the product's
+// quote currency, such as "100" to trade $100 of BTC-USD, whatever the side.
+//
+// A buy order is placed for the quote amount as is. Since the API only sells
+// a base size at market, a sell order is placed for the quote amount divided
+// by the product's best bid from GetBestBidAsk, rounded down to a multiple of
+// the product's base increment so that the order never sells more than the
+// quote amount at that bid. The amount actually received depends on the price
+// the order fills at. ErrInvalidOrderConfig is returned if the rounded base
+// size is zero.
+func (client *Client) OrderByQuote(ctx context.Context, productID string, side OrderSide, quoteAmount string,
+	opts ...CallOption,
+) (*Order, error) {
+	product, err := client.GetProduct(ctx, productID, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	builder := NewOrderBuilder(NewClientOrderID()).Product(product)
+
+	switch side {
+	case OrderSideBuy:
+		builder.Buy().MarketQuoteSize(quoteAmount)
+	case OrderSideSell:
+		baseSize, err := client.quoteToBase(ctx, *product, quoteAmount, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		builder.Sell().MarketBaseSize(baseSize.String())
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidOrderSide, side)
+	}
+
+	req, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.CreateOrder(ctx, req, opts...)
+}
+
+// quoteToBase converts an amount of the product's quote currency into a base
+// size at the product's best bid, rounded down to the base increment.
+func (client *Client) quoteToBase(ctx context.Context, product Product, quoteAmount string,
+	opts []CallOption,
+) (decimal.Decimal, error) {
+	quote, err := decimal.NewFromString(quoteAmount)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to parse quote amount %q: %w", quoteAmount, err)
+	}
+
+	books, err := client.GetBestBidAsk(ctx, []string{product.ProductID}, opts...)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to get best bid: %w", err)
+	}
+
+	if len(books.Data) == 0 || len(books.Data[0].Bids) == 0 {
+		return decimal.Decimal{}, fmt.Errorf("%w: %s has no bid", ErrMissingBidAsk, product.ProductID)
+	}
+
+	bid, err := decimal.NewFromString(books.Data[0].Bids[0].Price)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to parse bid %q: %w", books.Data[0].Bids[0].Price, err)
+	}
+
+	if bid.IsZero() {
+		return decimal.Decimal{}, fmt.Errorf("%w: %s has a zero bid", ErrMissingBidAsk, product.ProductID)
+	}
+
+	base, err := roundToIncrement(quote.Div(bid), product.BaseIncrement, decimal.Decimal.Floor)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	if !base.IsPositive() {
+		return decimal.Decimal{}, fmt.Errorf("%w: %s %s is less than one base increment of %s",
+			ErrInvalidOrderConfig, quoteAmount, product.quoteCurrency(), product.ProductID)
+	}
+
+	return base, nil
+}
@@ -0,0 +1,132 @@
+package coinbase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// mockOrderByQuote serves the product, its best bid and ask and the order
+// created by OrderByQuote, recording the order request in "req".
+func mockOrderByQuote(t *testing.T, req *OrderRequest) mockDoFunc {
+	t.Helper()
+
+	return func(httpReq *http.Request) (*http.Response, error) {
+		switch httpReq.URL.Path {
+		case "/api/v3/brokerage/products/BTC-USD":
+			return newMockResponse(http.StatusOK,
+				`{"product_id": "BTC-USD", "base_increment": "0.00000001", "status": "online"}`), nil
+		case "/api/v3/brokerage/best_bid_ask":
+			return newMockResponse(http.StatusOK, `{"pricebooks": [{"product_id": "BTC-USD", `+
+				`"bids": [{"price": "29000", "size": "1"}], "asks": [{"price": "29001", "size": "1"}]}]}`), nil
+		}
+
+		body, err := io.ReadAll(httpReq.Body)
+		if err != nil {
+			t.Fatalf("failed to read order request: %v", err)
+		}
+
+		if err := json.Unmarshal(body, req); err != nil {
+			t.Fatalf("failed to decode order request: %v", err)
+		}
+
+		return newMockResponse(http.StatusOK, `{"success": true, "order_id": "a"}`), nil
+	}
+}
+
+func TestOrderByQuote(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		side OrderSide
+		want OrderConfig
+	}{
+		{
+			name: "buy",
+			side: OrderSideBuy,
+			want: OrderConfig{MarketIOC: &MarketIOCConfig{QuoteSize: "100"}},
+		},
+		{
+			// 100 / 29000 = 0.003448275862..., rounded down to the
+			// base increment.
+			name: "sell",
+			side: OrderSideSell,
+			want: OrderConfig{MarketIOC: &MarketIOCConfig{BaseSize: "0.00344827"}},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var req OrderRequest
+
+			client := &Client{httpClient: mockOrderByQuote(t, &req)}
+
+			order, err := client.OrderByQuote(context.Background(), "BTC-USD", test.side, "100")
+			if err != nil {
+				t.Fatalf("failed to place order: %v", err)
+			}
+
+			if order.OrderID != "a" {
+				t.Fatalf("got order ID %q, want %q", order.OrderID, "a")
+			}
+
+			if req.ProductID != "BTC-USD" || req.Side != test.side || req.ClientOrderID == "" {
+				t.Fatalf("got order request %+v", req)
+			}
+
+			if !reflect.DeepEqual(req.Configuration, test.want) {
+				t.Fatalf("got configuration %+v, want %+v", req.Configuration, test.want)
+			}
+		})
+	}
+}
+
+func TestOrderByQuoteErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		side        OrderSide
+		quoteAmount string
+		err         error
+	}{
+		{
+			name:        "less than one increment",
+			side:        OrderSideSell,
+			quoteAmount: "0.0001",
+			err:         ErrInvalidOrderConfig,
+		},
+		{
+			name:        "unknown side",
+			side:        OrderSideUnknown,
+			quoteAmount: "100",
+			err:         ErrInvalidOrderSide,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var req OrderRequest
+
+			client := &Client{httpClient: mockOrderByQuote(t, &req)}
+
+			_, err := client.OrderByQuote(context.Background(), "BTC-USD", test.side, test.quoteAmount)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package coinbase
+
+import "context"
+
+// OrderPager iterates over the orders returned by ListOrders, lazily
+// requesting the next page once the current one has been consumed.
+type OrderPager struct {
+	ctx    context.Context //nolint:containedctx
+	client *Client
+	params ListOrdersParams
+	opts   []CallOption
+
+	page  []HistoricalOrder
+	order HistoricalOrder
+	guard cursorGuard
+	done  bool
+	err   error
+}
+
+// OrdersPager returns a pager over every order matching the given parameters.
+func (client *Client) OrdersPager(ctx context.Context, params ListOrdersParams, opts ...CallOption) *OrderPager {
+	return &OrderPager{
+		ctx:    ctx,
+		client: client,
+		params: params,
+		opts:   opts,
+	}
+}
+
+// OpenOrdersPager returns a pager over every open order for the given product.
+// If the product ID is empty, open orders for all products are returned.
+func (client *Client) OpenOrdersPager(ctx context.Context, productID string, opts ...CallOption) *OrderPager {
+	params := ListOrdersParams{
+		ProductID:   productID,
+		OrderStatus: []OrderStatus{OrderStatusOpen},
+	}
+
+	return client.OrdersPager(ctx, params, opts...)
+}
+
+// Next advances the pager to the next order, which is then available through
+// the Order method. It returns false when there are no more orders or when an
+// error occurred, which is available through the Err method.
+func (pager *OrderPager) Next() bool {
+	for len(pager.page) == 0 {
+		if pager.done || pager.err != nil {
+			return false
+		}
+
+		orders, err := pager.client.ListOrders(pager.ctx, pager.params, pager.opts...)
+		if err != nil {
+			pager.err = err
+
+			return false
+		}
+
+		hasNext := orders.HasNext && orders.Cursor != ""
+		if err := pager.guard.check(pager.params.Cursor, orders.Cursor, len(orders.Data), hasNext); err != nil {
+			pager.err = err
+
+			return false
+		}
+
+		pager.page = orders.Data
+		pager.params.Cursor = orders.Cursor
+		pager.done = !hasNext
+	}
+
+	pager.order, pager.page = pager.page[0], pager.page[1:]
+
+	return true
+}
+
+// Order returns the current order.
+func (pager *OrderPager) Order() HistoricalOrder {
+	return pager.order
+}
+
+// Err returns the error, if any, that stopped the pager.
+func (pager *OrderPager) Err() error {
+	return pager.err
+}
+
+// AccountPager iterates over the accounts returned by ListAccounts, lazily
+// requesting the next page once the current one has been consumed.
+type AccountPager struct {
+	ctx    context.Context //nolint:containedctx
+	client *Client
+	params ListAccountsParams
+	opts   []CallOption
+
+	page    []Account
+	account Account
+	guard   cursorGuard
+	done    bool
+	err     error
+}
+
+// AccountsPager returns a pager over every account of the authenticated user.
+func (client *Client) AccountsPager(ctx context.Context, opts ...CallOption) *AccountPager {
+	return &AccountPager{
+		ctx:    ctx,
+		client: client,
+		opts:   opts,
+	}
+}
+
+// Next advances the pager to the next account, which is then available
+// through the Account method. It returns false when there are no more
+// accounts or when an error occurred, which is available through the Err
+// method.
+func (pager *AccountPager) Next() bool {
+	for len(pager.page) == 0 {
+		if pager.done || pager.err != nil {
+			return false
+		}
+
+		accounts, err := pager.client.ListAccounts(pager.ctx, pager.params, pager.opts...)
+		if err != nil {
+			pager.err = err
+
+			return false
+		}
+
+		hasNext := accounts.HasNext && accounts.Cursor != ""
+		if err := pager.guard.check(pager.params.Cursor, accounts.Cursor, len(accounts.Data), hasNext); err != nil {
+			pager.err = err
+
+			return false
+		}
+
+		pager.page = accounts.Data
+		pager.params.Cursor = accounts.Cursor
+		pager.done = !hasNext
+	}
+
+	pager.account, pager.page = pager.page[0], pager.page[1:]
+
+	return true
+}
+
+// Account returns the current account.
+func (pager *AccountPager) Account() Account {
+	return pager.account
+}
+
+// Err returns the error, if any, that stopped the pager.
+func (pager *AccountPager) Err() error {
+	return pager.err
+}
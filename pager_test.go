@@ -0,0 +1,124 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestOpenOrdersPager(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]string{
+		"":       `{"orders": [{"order_id": "a"}, {"order_id": "b"}], "has_next": true, "cursor": "page-2"}`,
+		"page-2": `{"orders": [], "has_next": true, "cursor": "page-3"}`,
+		"page-3": `{"orders": [{"order_id": "c"}], "has_next": false, "cursor": ""}`,
+	}
+
+	requests := 0
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			requests++
+
+			query := req.URL.Query()
+			if got := query.Get("product_id"); got != "BTC-USD" {
+				t.Errorf("got product_id %q, want %q", got, "BTC-USD")
+			}
+
+			if got := query.Get("order_status"); got != string(OrderStatusOpen) {
+				t.Errorf("got order_status %q, want %q", got, OrderStatusOpen)
+			}
+
+			return newMockResponse(http.StatusOK, pages[query.Get("cursor")]), nil
+		}),
+	}
+
+	pager := client.OpenOrdersPager(context.Background(), "BTC-USD")
+
+	var got []string
+
+	for pager.Next() {
+		got = append(got, pager.Order().OrderID)
+
+		if len(got) == 1 && requests != 1 {
+			t.Fatalf("got %d requests after the first order, want 1", requests)
+		}
+	}
+
+	if err := pager.Err(); err != nil {
+		t.Fatalf("failed to page orders: %v", err)
+	}
+
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if requests != len(pages) {
+		t.Fatalf("got %d requests, want %d", requests, len(pages))
+	}
+
+	if pager.Next() {
+		t.Fatalf("got another order after the pager was exhausted")
+	}
+}
+
+func TestOpenOrdersPagerError(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Query().Get("cursor") == "" {
+				return newMockResponse(http.StatusOK, `{"orders": [{"order_id": "a"}], "has_next": true, "cursor": "next"}`), nil
+			}
+
+			return newMockResponse(http.StatusInternalServerError, ""), nil
+		}),
+	}
+
+	pager := client.OpenOrdersPager(context.Background(), "")
+
+	if !pager.Next() || pager.Order().OrderID != "a" {
+		t.Fatalf("failed to get the first order")
+	}
+
+	if pager.Next() {
+		t.Fatalf("got an order after a failed request")
+	}
+
+	if err := pager.Err(); !errors.Is(err, ErrStatusNotOK) {
+		t.Fatalf("got %v, want %v", err, ErrStatusNotOK)
+	}
+}
+
+func TestAccountsPager(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]string{
+		"":       `{"accounts": [{"uuid": "a"}, {"uuid": "b"}], "has_next": true, "cursor": "page-2"}`,
+		"page-2": `{"accounts": [{"uuid": "c"}], "has_next": false, "cursor": ""}`,
+	}
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			return newMockResponse(http.StatusOK, pages[req.URL.Query().Get("cursor")]), nil
+		}),
+	}
+
+	pager := client.AccountsPager(context.Background())
+
+	var got []string
+	for pager.Next() {
+		got = append(got, pager.Account().UUID)
+	}
+
+	if err := pager.Err(); err != nil {
+		t.Fatalf("failed to page accounts: %v", err)
+	}
+
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,126 @@
+package coinbase
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxEmptyPages is the number of consecutive empty pages that claim to have a
+// next page after which pagination is considered stalled.
+const maxEmptyPages = 3
+
+// ErrPaginationStalled is returned when walking the pages of a list endpoint
+// would loop, because the API returned the cursor of the page just requested
+// or kept returning empty pages that claim to have a next page.
+var ErrPaginationStalled = errors.New("pagination stalled")
+
+// cursorGuard tracks the pages walked with a cursor to detect pagination that
+// does not make progress.
+type cursorGuard struct {
+	emptyPages int
+}
+
+// check returns ErrPaginationStalled if the page requested with the given
+// cursor does not lead to a new page. The page had size items and, if hasNext
+// is set, the next cursor.
+func (guard *cursorGuard) check(cursor, next string, size int, hasNext bool) error {
+	if !hasNext {
+		return nil
+	}
+
+	if next == cursor {
+		return fmt.Errorf("%w: cursor %q repeated", ErrPaginationStalled, cursor)
+	}
+
+	if size > 0 {
+		guard.emptyPages = 0
+
+		return nil
+	}
+
+	if guard.emptyPages++; guard.emptyPages >= maxEmptyPages {
+		return fmt.Errorf("%w: %d consecutive empty pages", ErrPaginationStalled, guard.emptyPages)
+	}
+
+	return nil
+}
+
+// headerPaginated is implemented by the paginated collections whose cursor can
+// also be read from the response headers, for endpoints that paginate through
+// headers rather than the response body.
+type headerPaginated interface {
+	setHeaderCursor(cursor string)
+}
+
+// headerCursor returns the cursor of the next page given by the response
+// headers, or an empty string if there is none. The Cb-After header is used if
+// present, otherwise the "cursor" or "after" query parameter of the Link
+// header's "next" URL. Cb-Before, which points to the previous page, is not
+// used since pagers only move forward.
+func headerCursor(header http.Header) string {
+	if after := header.Get("Cb-After"); after != "" {
+		return after
+	}
+
+	for _, link := range strings.Split(header.Get("Link"), ",") {
+		target, params, found := strings.Cut(link, ";")
+		if !found || !isNextLink(params) {
+			continue
+		}
+
+		next, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+		if err != nil {
+			continue
+		}
+
+		if cursor := next.Query().Get("cursor"); cursor != "" {
+			return cursor
+		}
+
+		return next.Query().Get("after")
+	}
+
+	return ""
+}
+
+// isNextLink reports whether the parameters of a Link header value mark it as
+// the link to the next page.
+func isNextLink(params string) bool {
+	for _, param := range strings.Split(params, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if strings.EqualFold(key, "rel") && strings.EqualFold(strings.Trim(value, `"`), "next") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setHeaderCursor sets the cursor from the response headers, unless the
+// response body already had one.
+func (accounts *Accounts) setHeaderCursor(cursor string) {
+	if accounts.Cursor == "" {
+		accounts.Cursor = cursor
+		accounts.HasNext = true
+	}
+}
+
+// setHeaderCursor sets the cursor from the response headers, unless the
+// response body already had one.
+func (orders *Orders) setHeaderCursor(cursor string) {
+	if orders.Cursor == "" {
+		orders.Cursor = cursor
+		orders.HasNext = true
+	}
+}
+
+// setHeaderCursor sets the cursor from the response headers, unless the
+// response body already had one.
+func (fills *Fills) setHeaderCursor(cursor string) {
+	if fills.Cursor == "" {
+		fills.Cursor = cursor
+	}
+}
@@ -0,0 +1,179 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestHeaderCursor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   string
+	}{
+		{
+			name:   "nil",
+			header: nil,
+			want:   "",
+		},
+		{
+			name:   "cb-after",
+			header: http.Header{"Cb-After": {"abc"}, "Cb-Before": {"xyz"}},
+			want:   "abc",
+		},
+		{
+			name: "link cursor",
+			header: http.Header{"Link": {
+				`<https://api.coinbase.com/orders?cursor=prev>; rel="prev", ` +
+					`<https://api.coinbase.com/orders?cursor=next&limit=10>; rel="next"`,
+			}},
+			want: "next",
+		},
+		{
+			name:   "link after",
+			header: http.Header{"Link": {`<https://api.exchange.coinbase.com/fills?after=42>; rel=next`}},
+			want:   "42",
+		},
+		{
+			name:   "link without next",
+			header: http.Header{"Link": {`<https://api.coinbase.com/orders?cursor=prev>; rel="prev"`}},
+			want:   "",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := headerCursor(test.header); got != test.want {
+				t.Fatalf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestOrdersPagerHeaderCursor(t *testing.T) {
+	t.Parallel()
+
+	var cursors []string
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			cursor := req.URL.Query().Get("cursor")
+			cursors = append(cursors, cursor)
+
+			if cursor == "" {
+				resp := newMockResponse(http.StatusOK, `{"orders": [{"order_id": "1"}]}`)
+				resp.Header = http.Header{"Cb-After": {"page-2"}}
+
+				return resp, nil
+			}
+
+			return newMockResponse(http.StatusOK, `{"orders": [{"order_id": "2"}]}`), nil
+		}),
+	}
+
+	pager := client.OrdersPager(context.Background(), ListOrdersParams{})
+
+	var ids []string
+	for pager.Next() {
+		ids = append(ids, pager.Order().OrderID)
+	}
+
+	if err := pager.Err(); err != nil {
+		t.Fatalf("failed to page orders: %v", err)
+	}
+
+	if want := []string{"1", "2"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("got orders %v, want %v", ids, want)
+	}
+
+	if want := []string{"", "page-2"}; !reflect.DeepEqual(cursors, want) {
+		t.Fatalf("got cursors %v, want %v", cursors, want)
+	}
+}
+
+func TestPaginationStalled(t *testing.T) {
+	t.Parallel()
+
+	t.Run("repeating order cursor", func(t *testing.T) {
+		t.Parallel()
+
+		requests := 0
+
+		client := &Client{
+			httpClient: mockDoFunc(func(*http.Request) (*http.Response, error) {
+				requests++
+
+				return newMockResponse(http.StatusOK,
+					`{"orders": [{"order_id": "a"}], "has_next": true, "cursor": "stale"}`), nil
+			}),
+		}
+
+		pager := client.OrdersPager(context.Background(), ListOrdersParams{})
+		for pager.Next() {
+			if requests > 10 {
+				t.Fatalf("pager did not stop on a repeating cursor")
+			}
+		}
+
+		if err := pager.Err(); !errors.Is(err, ErrPaginationStalled) {
+			t.Fatalf("got %v, want %v", err, ErrPaginationStalled)
+		}
+
+		if requests != 2 {
+			t.Fatalf("got %d requests, want 2", requests)
+		}
+	})
+
+	t.Run("empty account pages", func(t *testing.T) {
+		t.Parallel()
+
+		requests := 0
+
+		client := &Client{
+			httpClient: mockDoFunc(func(*http.Request) (*http.Response, error) {
+				requests++
+
+				return newMockResponse(http.StatusOK,
+					fmt.Sprintf(`{"accounts": [], "has_next": true, "cursor": "page-%d"}`, requests)), nil
+			}),
+		}
+
+		pager := client.AccountsPager(context.Background())
+		if pager.Next() {
+			t.Fatalf("got an account from empty pages")
+		}
+
+		if err := pager.Err(); !errors.Is(err, ErrPaginationStalled) {
+			t.Fatalf("got %v, want %v", err, ErrPaginationStalled)
+		}
+
+		if requests != maxEmptyPages {
+			t.Fatalf("got %d requests, want %d", requests, maxEmptyPages)
+		}
+	})
+
+	t.Run("repeating fill cursor", func(t *testing.T) {
+		t.Parallel()
+
+		client := &Client{
+			httpClient: mockDoFunc(func(*http.Request) (*http.Response, error) {
+				return newMockResponse(http.StatusOK, `{"fills": [{"entry_id": "1"}], "cursor": "stale"}`), nil
+			}),
+		}
+
+		_, err := client.ListFillsAll(context.Background(), ListFillsParams{Cursor: "stale"})
+		if !errors.Is(err, ErrPaginationStalled) {
+			t.Fatalf("got %v, want %v", err, ErrPaginationStalled)
+		}
+	})
+}
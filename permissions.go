@@ -0,0 +1,86 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// ErrNoTradePermission is returned by the methods that create or cancel orders
+// when the client checks the permissions of its API key, see
+// WithTradePermissionCheck, and the key cannot trade.
+var ErrNoTradePermission = errors.New("API key has no trade permission")
+
+// APIKeyPermissions represents the permissions of the API key that requests
+// are signed with.
+type APIKeyPermissions struct {
+	CanView       bool          `json:"can_view"`
+	CanTrade      bool          `json:"can_trade"`
+	CanTransfer   bool          `json:"can_transfer"`
+	PortfolioUUID string        `json:"portfolio_uuid"`
+	PortfolioType PortfolioType `json:"portfolio_type"`
+}
+
+// GetAPIKeyPermissions returns the permissions of the API key that requests are
+// signed with.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_getapikeypermissions
+func (client *Client) GetAPIKeyPermissions(ctx context.Context, opts ...CallOption) (*APIKeyPermissions, error) {
+	path := []string{"brokerage", "key_permissions"}
+
+	permissions := &APIKeyPermissions{}
+	if err := client.do(ctx, http.MethodGet, path, nil, nil, permissions, opts); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// WithTradePermissionCheck checks the permissions of the API key with
+// GetAPIKeyPermissions before the client first creates or cancels orders, so
+// that a read-only key fails fast with ErrNoTradePermission instead of sending
+// the request and being rejected with a generic status error. The permissions
+// are cached once they have been read, so they are read at most once. If they
+// cannot be read, the order request is sent anyway and the check is made again
+// by the next one.
+func WithTradePermissionCheck() ClientOption {
+	return func(client *Client) {
+		client.permissions = &permissionsCache{}
+	}
+}
+
+// permissionsCache holds the permissions of a client's API key once they have
+// been read.
+type permissionsCache struct {
+	mu       sync.Mutex
+	checked  bool
+	canTrade bool
+}
+
+// checkTradePermission returns ErrNoTradePermission if the client checks the
+// permissions of its API key and the key is known to be unable to trade.
+func (client *Client) checkTradePermission(ctx context.Context, opts []CallOption) error {
+	cache := client.permissions
+	if cache == nil {
+		return nil
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if !cache.checked {
+		permissions, err := client.GetAPIKeyPermissions(ctx, opts...)
+		if err != nil {
+			return nil //nolint:nilerr // the order request is sent if the permissions are unknown.
+		}
+
+		cache.checked, cache.canTrade = true, permissions.CanTrade
+	}
+
+	if !cache.canTrade {
+		return ErrNoTradePermission
+	}
+
+	return nil
+}
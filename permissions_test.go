@@ -0,0 +1,138 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGetAPIKeyPermissions(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{
+		httpClient: &mockClient{
+			response: []byte(`{"can_view": true, "can_trade": false, "can_transfer": false,
+				"portfolio_uuid": "1111-000000-000000", "portfolio_type": "DEFAULT"}`),
+			statusCode: http.StatusOK,
+		},
+	}
+
+	got, err := client.GetAPIKeyPermissions(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get API key permissions: %v", err)
+	}
+
+	want := &APIKeyPermissions{CanView: true, PortfolioUUID: "1111-000000-000000", PortfolioType: PortfolioTypeDefault}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestTradePermissionCheck(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		permissions string
+		status      int
+		check       bool
+		err         error
+		checks      int32
+		orders      int32
+	}{
+		{
+			name:        "unchecked",
+			permissions: `{"can_view": true, "can_trade": false}`,
+			status:      http.StatusOK,
+			orders:      4,
+		},
+		{
+			name:        "permissioned",
+			permissions: `{"can_view": true, "can_trade": true}`,
+			status:      http.StatusOK,
+			check:       true,
+			checks:      1,
+			orders:      4,
+		},
+		{
+			name:        "read-only",
+			permissions: `{"can_view": true, "can_trade": false}`,
+			status:      http.StatusOK,
+			check:       true,
+			err:         ErrNoTradePermission,
+			checks:      1,
+		},
+		{
+			// The permissions cannot be read, so every order request
+			// is sent and checks again.
+			name:   "unknown",
+			status: http.StatusForbidden,
+			check:  true,
+			checks: 4,
+			orders: 4,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var checks, orders int32
+
+			client := &Client{
+				httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+					switch req.URL.Path {
+					case "/api/v3/brokerage/key_permissions":
+						atomic.AddInt32(&checks, 1)
+
+						return newMockResponse(test.status, test.permissions), nil
+					case "/api/v3/brokerage/orders":
+						atomic.AddInt32(&orders, 1)
+
+						return newMockResponse(http.StatusOK, `{"success": true, "order_id": "a"}`), nil
+					default:
+						atomic.AddInt32(&orders, 1)
+
+						return newMockResponse(http.StatusOK, `{"results": []}`), nil
+					}
+				}),
+			}
+
+			if test.check {
+				WithTradePermissionCheck()(client)
+			}
+
+			orderReq := OrderRequest{
+				ClientOrderID: "a",
+				ProductID:     "BTC-USD",
+				Side:          OrderSideBuy,
+				Configuration: OrderConfig{MarketIOC: &MarketIOCConfig{QuoteSize: "10"}},
+			}
+
+			ctx := context.Background()
+
+			for i := 0; i < 2; i++ {
+				if _, err := client.CreateOrder(ctx, orderReq); !errors.Is(err, test.err) {
+					t.Fatalf("got create error %v, want %v", err, test.err)
+				}
+
+				if _, err := client.CancelOrders(ctx, []string{"a"}); !errors.Is(err, test.err) {
+					t.Fatalf("got cancel error %v, want %v", err, test.err)
+				}
+			}
+
+			if got := atomic.LoadInt32(&checks); got != test.checks {
+				t.Fatalf("got %d permission checks, want %d", got, test.checks)
+			}
+
+			if got := atomic.LoadInt32(&orders); got != test.orders {
+				t.Fatalf("got %d order requests, want %d", got, test.orders)
+			}
+		})
+	}
+}
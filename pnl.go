@@ -0,0 +1,131 @@
+package coinbase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// MissingPricesError is returned when holdings cannot be valued because some of
+// their products have no best bid. It wraps ErrMissingBidAsk.
+type MissingPricesError struct {
+	// ProductIDs are the products without a best bid, sorted.
+	ProductIDs []string
+}
+
+// Error implements the error interface.
+func (err *MissingPricesError) Error() string {
+	return fmt.Sprintf("%v: no bid for %s", ErrMissingBidAsk, strings.Join(err.ProductIDs, ", "))
+}
+
+// Unwrap returns ErrMissingBidAsk.
+func (err *MissingPricesError) Unwrap() error {
+	return ErrMissingBidAsk
+}
+
+// UnrealizedPnL returns the unrealized profit or loss of the user's spot
+// holdings against the given average cost basis, keyed by product ID. The cost
+// basis is keyed by product ID, such as "BTC-USD", and is the average price paid
+// for the base currency in the quote currency, in which the profit or loss is
+// returned. Holdings are the available balance and hold of every account in
+// the base currency, valued at the product's best bid, which is the price they
+// could be sold at. If some of the products have no best bid, a
+// *MissingPricesError listing them is returned.
+func (client *Client) UnrealizedPnL(ctx context.Context, costBasis map[string]string,
+	opts ...CallOption,
+) (map[string]decimal.Decimal, error) {
+	pnl := make(map[string]decimal.Decimal, len(costBasis))
+	if len(costBasis) == 0 {
+		return pnl, nil
+	}
+
+	holdings, err := client.holdings(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	productIDs := make([]string, 0, len(costBasis))
+	for productID := range costBasis {
+		productIDs = append(productIDs, productID)
+	}
+
+	sort.Strings(productIDs)
+
+	books, err := client.GetBestBidAsk(ctx, productIDs, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get best bids: %w", err)
+	}
+
+	bids := make(map[string]string, len(books.Data))
+	for _, book := range books.Data {
+		if len(book.Bids) > 0 {
+			bids[book.ProductID] = book.Bids[0].Price
+		}
+	}
+
+	var missing []string
+
+	for _, productID := range productIDs {
+		bid, ok := bids[productID]
+		if !ok {
+			missing = append(missing, productID)
+
+			continue
+		}
+
+		cost, err := decimal.NewFromString(costBasis[productID])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cost basis of %s: %w", productID, err)
+		}
+
+		price, err := decimal.NewFromString(bid)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse bid of %s: %w", productID, err)
+		}
+
+		base, _, _ := strings.Cut(productID, "-")
+		pnl[productID] = price.Sub(cost).Mul(holdings[strings.ToUpper(base)])
+	}
+
+	if len(missing) > 0 {
+		return nil, &MissingPricesError{ProductIDs: missing}
+	}
+
+	return pnl, nil
+}
+
+// holdings returns the available balance and hold of the user's accounts
+// summed by currency.
+func (client *Client) holdings(ctx context.Context, opts []CallOption) (map[string]decimal.Decimal, error) {
+	accounts, err := client.NonZeroAccounts(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	holdings := make(map[string]decimal.Decimal)
+
+	for _, account := range accounts {
+		available, err := account.AvailableBalance.amountOrZero()
+		if err != nil {
+			return nil, err
+		}
+
+		hold, err := account.Hold.amountOrZero()
+		if err != nil {
+			return nil, err
+		}
+
+		currency := account.AvailableBalance.Currency
+		if currency == "" {
+			currency = account.Currency
+		}
+
+		currency = strings.ToUpper(currency)
+		holdings[currency] = holdings[currency].Add(available).Add(hold)
+	}
+
+	return holdings, nil
+}
@@ -0,0 +1,116 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestUnrealizedPnL(t *testing.T) {
+	t.Parallel()
+
+	accounts := `{"accounts": [
+		{"uuid": "btc-1", "available_balance": {"value": "0.5", "currency": "BTC"},
+			"hold": {"value": "0.25", "currency": "BTC"}},
+		{"uuid": "btc-2", "available_balance": {"value": "0.25", "currency": "BTC"}},
+		{"uuid": "eth", "available_balance": {"value": "2", "currency": "ETH"}}
+	], "has_next": false}`
+
+	books := `{"pricebooks": [
+		{"product_id": "BTC-USD", "bids": [{"price": "30000", "size": "1"}], "asks": [{"price": "30010", "size": "1"}]},
+		{"product_id": "ETH-USD", "bids": [{"price": "1500", "size": "1"}], "asks": [{"price": "1501", "size": "1"}]},
+		{"product_id": "ADA-USD", "bids": [{"price": "0.3", "size": "1"}], "asks": [{"price": "0.31", "size": "1"}]},
+		{"product_id": "SOL-USD", "bids": [], "asks": [{"price": "20", "size": "1"}]}
+	]}`
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/api/v3/brokerage/best_bid_ask" {
+				return newMockResponse(http.StatusOK, books), nil
+			}
+
+			return newMockResponse(http.StatusOK, accounts), nil
+		}),
+	}
+
+	tests := []struct {
+		name      string
+		costBasis map[string]string
+		want      map[string]string
+		err       error
+	}{
+		{
+			name:      "no cost basis",
+			costBasis: map[string]string{},
+			want:      map[string]string{},
+		},
+		{
+			name:      "profit and loss",
+			costBasis: map[string]string{"BTC-USD": "25000", "ETH-USD": "2000"},
+			want:      map[string]string{"BTC-USD": "5000", "ETH-USD": "-1000"},
+		},
+		{
+			name:      "not held",
+			costBasis: map[string]string{"ADA-USD": "0.5"},
+			want:      map[string]string{"ADA-USD": "0"},
+		},
+		{
+			name:      "missing bid",
+			costBasis: map[string]string{"BTC-USD": "25000", "SOL-USD": "10"},
+			err:       ErrMissingBidAsk,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			pnl, err := client.UnrealizedPnL(context.Background(), test.costBasis)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if test.err != nil {
+				return
+			}
+
+			got := make(map[string]string, len(pnl))
+			for productID, amount := range pnl {
+				got[productID] = amount.String()
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestUnrealizedPnLMissingPrices(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/api/v3/brokerage/best_bid_ask" {
+				return newMockResponse(http.StatusOK, `{"pricebooks": [{"product_id": "BTC-USD", "bids": []}]}`), nil
+			}
+
+			return newMockResponse(http.StatusOK, `{"accounts": []}`), nil
+		}),
+	}
+
+	_, err := client.UnrealizedPnL(context.Background(), map[string]string{"SOL-USD": "10", "BTC-USD": "1"})
+
+	missing := &MissingPricesError{}
+	if !errors.As(err, &missing) {
+		t.Fatalf("got %v, want a *MissingPricesError", err)
+	}
+
+	if want := []string{"BTC-USD", "SOL-USD"}; !reflect.DeepEqual(missing.ProductIDs, want) {
+		t.Fatalf("got products %v, want %v", missing.ProductIDs, want)
+	}
+}
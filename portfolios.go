@@ -0,0 +1,143 @@
+package coinbase
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// PortfolioType represents the type of a portfolio.
+type PortfolioType string
+
+const (
+	// PortfolioTypeUndefined represents a portfolio of an undefined type.
+	PortfolioTypeUndefined PortfolioType = "UNDEFINED"
+
+	// PortfolioTypeDefault represents the user's default portfolio.
+	PortfolioTypeDefault PortfolioType = "DEFAULT"
+
+	// PortfolioTypeConsumer represents a portfolio created by the user.
+	PortfolioTypeConsumer PortfolioType = "CONSUMER"
+
+	// PortfolioTypeINTX represents an international exchange portfolio.
+	PortfolioTypeINTX PortfolioType = "INTX"
+)
+
+// Portfolio represents a portfolio of the user, which holds its own accounts
+// and orders.
+type Portfolio struct {
+	Name    string        `json:"name"`
+	UUID    string        `json:"uuid"`
+	Type    PortfolioType `json:"type"`
+	Deleted bool          `json:"deleted"`
+}
+
+// Portfolios represents a collection of portfolios.
+type Portfolios struct {
+	Data []Portfolio `json:"portfolios"`
+}
+
+// ByType returns the portfolios of the given type, in their original order.
+func (portfolios Portfolios) ByType(portfolioType PortfolioType) []Portfolio {
+	var matching []Portfolio
+
+	for _, portfolio := range portfolios.Data {
+		if portfolio.Type == portfolioType {
+			matching = append(matching, portfolio)
+		}
+	}
+
+	return matching
+}
+
+// ListPortfoliosParams are the query parameters used to filter the portfolios
+// returned by ListPortfolios. Zero values are omitted from the request.
+type ListPortfoliosParams struct {
+	PortfolioType PortfolioType
+}
+
+// query returns the URL query values for the parameters.
+func (params ListPortfoliosParams) query() url.Values {
+	query := url.Values{}
+
+	if params.PortfolioType != "" {
+		query.Set("portfolio_type", string(params.PortfolioType))
+	}
+
+	return query
+}
+
+// ListPortfolios returns the user's portfolios matching the given parameters.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_getportfolios
+func (client *Client) ListPortfolios(ctx context.Context, params ListPortfoliosParams,
+	opts ...CallOption,
+) (*Portfolios, error) {
+	path := []string{"brokerage", "portfolios"}
+
+	portfolios := &Portfolios{}
+	if err := client.do(ctx, http.MethodGet, path, params.query(), nil, portfolios, opts); err != nil {
+		return nil, err
+	}
+
+	return portfolios, nil
+}
+
+// PortfolioBalances are the total balances of a portfolio, in the user's fiat
+// currency.
+type PortfolioBalances struct {
+	TotalBalance               Money `json:"total_balance"`
+	TotalFuturesBalance        Money `json:"total_futures_balance"`
+	TotalCashEquivalentBalance Money `json:"total_cash_equivalent_balance"`
+	TotalCryptoBalance         Money `json:"total_crypto_balance"`
+	FuturesUnrealizedPNL       Money `json:"futures_unrealized_pnl"`
+	PerpUnrealizedPNL          Money `json:"perp_unrealized_pnl"`
+}
+
+// SpotPosition represents the holding of an asset in a portfolio. Fiat amounts
+// are in the user's fiat currency, and Allocation is the fraction of the
+// portfolio's balance that the position makes up.
+type SpotPosition struct {
+	Asset                  string  `json:"asset"`
+	AssetUUID              string  `json:"asset_uuid"`
+	AccountUUID            string  `json:"account_uuid"`
+	TotalBalanceFiat       float64 `json:"total_balance_fiat"`
+	TotalBalanceCrypto     float64 `json:"total_balance_crypto"`
+	AvailableToTradeFiat   float64 `json:"available_to_trade_fiat"`
+	AvailableToTradeCrypto float64 `json:"available_to_trade_crypto"`
+	Allocation             float64 `json:"allocation"`
+	OneDayChange           float64 `json:"one_day_change"`
+	CostBasis              Money   `json:"cost_basis"`
+	AverageEntryPrice      Money   `json:"average_entry_price"`
+	UnrealizedPNL          float64 `json:"unrealized_pnl"`
+	IsCash                 bool    `json:"is_cash"`
+}
+
+// PortfolioBreakdown represents the balances and positions of a portfolio.
+type PortfolioBreakdown struct {
+	Portfolio         Portfolio         `json:"portfolio"`
+	PortfolioBalances PortfolioBalances `json:"portfolio_balances"`
+	SpotPositions     []SpotPosition    `json:"spot_positions"`
+}
+
+// getPortfolioBreakdownResponse is the response of GetPortfolioBreakdown.
+type getPortfolioBreakdownResponse struct {
+	Breakdown PortfolioBreakdown `json:"breakdown"`
+}
+
+// GetPortfolioBreakdown returns the balances and spot positions of the
+// portfolio with the given UUID.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_getportfoliobreakdown
+func (client *Client) GetPortfolioBreakdown(ctx context.Context, portfolioUUID string,
+	opts ...CallOption,
+) (*PortfolioBreakdown, error) {
+	path := []string{"brokerage", "portfolios", portfolioUUID}
+
+	resp := &getPortfolioBreakdownResponse{}
+	if err := client.do(ctx, http.MethodGet, path, nil, nil, resp, opts); err != nil {
+		return nil, err
+	}
+
+	return &resp.Breakdown, nil
+}
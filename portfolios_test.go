@@ -0,0 +1,221 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestListPortfolios(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response []byte
+		params   ListPortfoliosParams
+		query    string
+		want     *Portfolios
+		err      error
+	}{
+		{
+			name: "nil",
+			err:  io.EOF, // end of file, nothing in response
+		},
+		{
+			name:     "empty",
+			response: []byte(`{}`),
+			want:     &Portfolios{},
+		},
+		{
+			name: "by type",
+			response: []byte(`
+{
+  "portfolios": [
+    {
+      "name": "Default",
+      "uuid": "1111-000000-000000",
+      "type": "DEFAULT",
+      "deleted": false
+    }
+  ]
+}`),
+			params: ListPortfoliosParams{PortfolioType: PortfolioTypeDefault},
+			query:  "portfolio_type=DEFAULT",
+			want: &Portfolios{
+				Data: []Portfolio{
+					{Name: "Default", UUID: "1111-000000-000000", Type: PortfolioTypeDefault},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotQuery string
+
+			client := &Client{
+				httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+					gotQuery = req.URL.RawQuery
+
+					return newMockResponse(http.StatusOK, string(test.response)), nil
+				}),
+			}
+
+			got, err := client.ListPortfolios(context.Background(), test.params)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+
+			if gotQuery != test.query {
+				t.Fatalf("got query %q, want %q", gotQuery, test.query)
+			}
+		})
+	}
+}
+
+func TestPortfoliosByType(t *testing.T) {
+	t.Parallel()
+
+	portfolios := Portfolios{
+		Data: []Portfolio{
+			{UUID: "a", Type: PortfolioTypeDefault},
+			{UUID: "b", Type: PortfolioTypeConsumer},
+			{UUID: "c", Type: PortfolioTypeConsumer},
+		},
+	}
+
+	got := portfolios.ByType(PortfolioTypeConsumer)
+	if want := portfolios.Data[1:]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if got := portfolios.ByType(PortfolioTypeINTX); got != nil {
+		t.Fatalf("got %v, want none", got)
+	}
+}
+
+func TestGetPortfolioBreakdown(t *testing.T) {
+	t.Parallel()
+
+	response := `
+{
+  "breakdown": {
+    "portfolio": {
+      "name": "Default",
+      "uuid": "1111-000000-000000",
+      "type": "DEFAULT",
+      "deleted": false
+    },
+    "portfolio_balances": {
+      "total_balance": {"value": "1525.5", "currency": "USD"},
+      "total_futures_balance": {"value": "0", "currency": "USD"},
+      "total_cash_equivalent_balance": {"value": "25.5", "currency": "USD"},
+      "total_crypto_balance": {"value": "1500", "currency": "USD"},
+      "futures_unrealized_pnl": {"value": "0", "currency": "USD"},
+      "perp_unrealized_pnl": {"value": "0", "currency": "USD"}
+    },
+    "spot_positions": [
+      {
+        "asset": "BTC",
+        "account_uuid": "2222-000000-000000",
+        "total_balance_fiat": 1500,
+        "total_balance_crypto": 0.05,
+        "available_to_trade_fiat": 1000,
+        "allocation": 0.9833,
+        "one_day_change": -0.012,
+        "cost_basis": {"value": "1200", "currency": "USD"},
+        "asset_img_url": "https://example.com/btc.png",
+        "is_cash": false,
+        "average_entry_price": {"value": "24000", "currency": "USD"},
+        "asset_uuid": "5b71fc48-3dd3-540c-809b-f8c94d0e68b5",
+        "available_to_trade_crypto": 0.0333,
+        "unrealized_pnl": 300
+      },
+      {
+        "asset": "USD",
+        "account_uuid": "3333-000000-000000",
+        "total_balance_fiat": 25.5,
+        "total_balance_crypto": 25.5,
+        "available_to_trade_fiat": 25.5,
+        "allocation": 0.0167,
+        "cost_basis": {"value": "25.5", "currency": "USD"},
+        "is_cash": true
+      }
+    ],
+    "perp_positions": [],
+    "futures_positions": []
+  }
+}`
+
+	var gotPath string
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			gotPath = req.URL.Path
+
+			return newMockResponse(http.StatusOK, response), nil
+		}),
+	}
+
+	got, err := client.GetPortfolioBreakdown(context.Background(), "1111-000000-000000")
+	if err != nil {
+		t.Fatalf("failed to get portfolio breakdown: %v", err)
+	}
+
+	if want := "/api/v3/brokerage/portfolios/1111-000000-000000"; gotPath != want {
+		t.Fatalf("got path %q, want %q", gotPath, want)
+	}
+
+	want := &PortfolioBreakdown{
+		Portfolio: Portfolio{Name: "Default", UUID: "1111-000000-000000", Type: PortfolioTypeDefault},
+		PortfolioBalances: PortfolioBalances{
+			TotalBalance:               Money{Value: "1525.5", Currency: "USD"},
+			TotalFuturesBalance:        Money{Value: "0", Currency: "USD"},
+			TotalCashEquivalentBalance: Money{Value: "25.5", Currency: "USD"},
+			TotalCryptoBalance:         Money{Value: "1500", Currency: "USD"},
+			FuturesUnrealizedPNL:       Money{Value: "0", Currency: "USD"},
+			PerpUnrealizedPNL:          Money{Value: "0", Currency: "USD"},
+		},
+		SpotPositions: []SpotPosition{
+			{
+				Asset:                  "BTC",
+				AssetUUID:              "5b71fc48-3dd3-540c-809b-f8c94d0e68b5",
+				AccountUUID:            "2222-000000-000000",
+				TotalBalanceFiat:       1500,
+				TotalBalanceCrypto:     0.05,
+				AvailableToTradeFiat:   1000,
+				AvailableToTradeCrypto: 0.0333,
+				Allocation:             0.9833,
+				OneDayChange:           -0.012,
+				CostBasis:              Money{Value: "1200", Currency: "USD"},
+				AverageEntryPrice:      Money{Value: "24000", Currency: "USD"},
+				UnrealizedPNL:          300,
+			},
+			{
+				Asset:                "USD",
+				AccountUUID:          "3333-000000-000000",
+				TotalBalanceFiat:     25.5,
+				TotalBalanceCrypto:   25.5,
+				AvailableToTradeFiat: 25.5,
+				Allocation:           0.0167,
+				CostBasis:            Money{Value: "25.5", Currency: "USD"},
+				IsCash:               true,
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
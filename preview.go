@@ -0,0 +1,87 @@
+package coinbase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// PreviewResult is the response from previewing an order. The amounts are
+// decimal strings in the product's quote currency, except for the sizes.
+type PreviewResult struct {
+	PreviewID           string   `json:"preview_id"`
+	OrderTotal          string   `json:"order_total"`
+	CommissionTotal     string   `json:"commission_total"`
+	TotalValueAfterFees string   `json:"total_value_after_fees"`
+	QuoteSize           string   `json:"quote_size"`
+	BaseSize            string   `json:"base_size"`
+	BestBid             string   `json:"best_bid"`
+	BestAsk             string   `json:"best_ask"`
+	IsMax               bool     `json:"is_max"`
+	OrderMarginTotal    string   `json:"order_margin_total"`
+	Leverage            string   `json:"leverage"`
+	LongLeverage        string   `json:"long_leverage"`
+	ShortLeverage       string   `json:"short_leverage"`
+	Errs                []string `json:"errs"`
+	Warning             []string `json:"warning"`
+
+	// Slippage is the expected price impact of the order as a fraction of
+	// the price, such as "0.0015" for 0.15%.
+	Slippage string `json:"slippage"`
+}
+
+// ExceedsSlippage reports whether the preview's slippage is more than maxBps
+// basis points, such as 50 for 0.5%, so that orders with a high market impact
+// can be rejected before they are created. An empty slippage is zero, while a
+// slippage that cannot be parsed exceeds any maximum, so that the guard fails
+// closed.
+func (result PreviewResult) ExceedsSlippage(maxBps float64) bool {
+	value := result.Slippage
+	if value == "" {
+		value = "0"
+	}
+
+	slippage, err := decimal.NewFromString(value)
+	if err != nil {
+		return true
+	}
+
+	// The comparison is negated so that a NaN maximum is exceeded.
+	bps, _ := slippage.Mul(decimal.NewFromInt(basisPointsPerUnit)).Float64()
+
+	return !(bps <= maxBps)
+}
+
+// PreviewOrder previews the order without creating it, returning its expected
+// total, fees and slippage. The preview ID of the result can be passed to
+// CreateOrderFromPreview to create the order as previewed. The order request
+// is validated before it is sent, see OrderRequest.Validate.
+// ErrPostOnlyWouldCross is returned if the preview's errors reject a post-only
+// order that would cross the book, as CreateOrder does for the order itself.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_previeworder
+func (client *Client) PreviewOrder(ctx context.Context, orderReq OrderRequest,
+	opts ...CallOption,
+) (*PreviewResult, error) {
+	if err := orderReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	path := []string{"brokerage", "orders", "preview"}
+
+	result := &PreviewResult{}
+	if err := client.do(ctx, http.MethodPost, path, nil, orderReq, result, opts); err != nil {
+		return nil, err
+	}
+
+	for _, reason := range result.Errs {
+		if strings.Contains(strings.ToUpper(reason), postOnlyReason) {
+			return nil, fmt.Errorf("%w: %s", ErrPostOnlyWouldCross, reason)
+		}
+	}
+
+	return result, nil
+}
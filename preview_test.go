@@ -0,0 +1,183 @@
+package coinbase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestPreviewOrder(t *testing.T) {
+	t.Parallel()
+
+	response := `
+{
+  "order_total": "10050.25",
+  "commission_total": "60.15",
+  "total_value_after_fees": "9990.10",
+  "errs": [],
+  "warning": ["BIG_ORDER"],
+  "quote_size": "10050.25",
+  "base_size": "0.33",
+  "best_bid": "30100.5",
+  "best_ask": "30110",
+  "is_max": false,
+  "order_margin_total": "0",
+  "leverage": "",
+  "long_leverage": "",
+  "short_leverage": "",
+  "slippage": "0.0075",
+  "preview_id": "b40bbff9-17ce-4726-8b64-9de7ae57ad26"
+}`
+
+	var (
+		gotPath string
+		gotReq  OrderRequest
+	)
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			gotPath = req.URL.Path
+
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fatalf("failed to read request: %v", err)
+			}
+
+			if err := json.Unmarshal(body, &gotReq); err != nil {
+				t.Fatalf("failed to decode request: %v", err)
+			}
+
+			return newMockResponse(http.StatusOK, response), nil
+		}),
+	}
+
+	orderReq := OrderRequest{
+		ClientOrderID: "a",
+		ProductID:     "BTC-USD",
+		Side:          OrderSideBuy,
+		Configuration: OrderConfig{MarketIOC: &MarketIOCConfig{QuoteSize: "10050.25"}},
+	}
+
+	got, err := client.PreviewOrder(context.Background(), orderReq)
+	if err != nil {
+		t.Fatalf("failed to preview order: %v", err)
+	}
+
+	if want := "/api/v3/brokerage/orders/preview"; gotPath != want {
+		t.Fatalf("got path %q, want %q", gotPath, want)
+	}
+
+	if !reflect.DeepEqual(gotReq, orderReq) {
+		t.Fatalf("got request %+v, want %+v", gotReq, orderReq)
+	}
+
+	want := &PreviewResult{
+		PreviewID:           "b40bbff9-17ce-4726-8b64-9de7ae57ad26",
+		OrderTotal:          "10050.25",
+		CommissionTotal:     "60.15",
+		TotalValueAfterFees: "9990.10",
+		QuoteSize:           "10050.25",
+		BaseSize:            "0.33",
+		BestBid:             "30100.5",
+		BestAsk:             "30110",
+		OrderMarginTotal:    "0",
+		Errs:                []string{},
+		Warning:             []string{"BIG_ORDER"},
+		Slippage:            "0.0075",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	// The slippage of 0.75% is 75 basis points.
+	if !got.ExceedsSlippage(50) {
+		t.Fatalf("got slippage %s within 50 bps, want it exceeded", got.Slippage)
+	}
+
+	if got.ExceedsSlippage(75) {
+		t.Fatalf("got slippage %s exceeding 75 bps, want it within", got.Slippage)
+	}
+}
+
+func TestPreviewOrderInvalid(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{
+		httpClient: mockDoFunc(func(*http.Request) (*http.Response, error) {
+			t.Fatalf("got a request for an invalid order")
+
+			return nil, nil
+		}),
+	}
+
+	_, err := client.PreviewOrder(context.Background(), OrderRequest{ClientOrderID: "a"})
+	if !errors.Is(err, ErrInvalidOrderConfig) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidOrderConfig)
+	}
+}
+
+func TestPreviewOrderPostOnly(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{
+		httpClient: &mockClient{
+			response:   []byte(`{"errs": ["PREVIEW_INVALID_LIMIT_PRICE_POST_ONLY"], "order_total": "0"}`),
+			statusCode: http.StatusOK,
+		},
+	}
+
+	orderReq := OrderRequest{
+		ClientOrderID: "0000-00000-000000",
+		ProductID:     "BTC-USD",
+		Side:          OrderSideBuy,
+		Configuration: OrderConfig{LimitGTC: &LimitGTCConfig{BaseSize: "0.1", Price: "31000", PostOnly: true}},
+	}
+
+	result, err := client.PreviewOrder(context.Background(), orderReq)
+	if !errors.Is(err, ErrPostOnlyWouldCross) {
+		t.Fatalf("got %v, want %v", err, ErrPostOnlyWouldCross)
+	}
+
+	if result != nil {
+		t.Fatalf("got preview %+v, want nil", result)
+	}
+}
+
+func TestPreviewResultExceedsSlippage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		slippage string
+		maxBps   float64
+		want     bool
+	}{
+		{name: "below", slippage: "0.0012", maxBps: 20, want: false},
+		{name: "at", slippage: "0.002", maxBps: 20, want: false},
+		{name: "above", slippage: "0.00201", maxBps: 20, want: true},
+		{name: "empty", slippage: "", maxBps: 0, want: false},
+		{name: "negative maximum", slippage: "", maxBps: -1, want: true},
+		{name: "unparseable", slippage: "n/a", maxBps: 1000, want: true},
+		{name: "NaN maximum", slippage: "0.001", maxBps: math.NaN(), want: true},
+		{name: "infinite maximum", slippage: "0.5", maxBps: math.Inf(1), want: false},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := PreviewResult{Slippage: test.slippage}
+			if got := result.ExceedsSlippage(test.maxBps); got != test.want {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// productStatusOnline is the status of a product that is open for trading.
+const productStatusOnline = "online"
+
+// ErrProductNotTradable is returned when an order is built for a product that
+// cannot currently be traded.
+var ErrProductNotTradable = errors.New("product not tradable")
+
+// ProductType represents the type of a product.
+type ProductType string
+
+const (
+	// ProductTypeUnknown represents a product of an unknown type.
+	ProductTypeUnknown ProductType = "UNKNOWN_PRODUCT_TYPE"
+
+	// ProductTypeSpot represents a spot product.
+	ProductTypeSpot ProductType = "SPOT"
+
+	// ProductTypeFuture represents a futures product.
+	ProductTypeFuture ProductType = "FUTURE"
+)
+
+// Product represents a market that can be traded on Coinbase.
+type Product struct {
+	ProductID                 string      `json:"product_id"`
+	Price                     string      `json:"price"`
+	PricePercentageChange24h  string      `json:"price_percentage_change_24h"`
+	Volume24h                 string      `json:"volume_24h"`
+	VolumePercentageChange24h string      `json:"volume_percentage_change_24h"`
+	BaseIncrement             string      `json:"base_increment"`
+	QuoteIncrement            string      `json:"quote_increment"`
+	QuoteMinSize              string      `json:"quote_min_size"`
+	QuoteMaxSize              string      `json:"quote_max_size"`
+	BaseMinSize               string      `json:"base_min_size"`
+	BaseMaxSize               string      `json:"base_max_size"`
+	BaseName                  string      `json:"base_name"`
+	QuoteName                 string      `json:"quote_name"`
+	Watched                   bool        `json:"watched"`
+	IsDisabled                bool        `json:"is_disabled"`
+	New                       bool        `json:"new"`
+	Status                    string      `json:"status"`
+	CancelOnly                bool        `json:"cancel_only"`
+	LimitOnly                 bool        `json:"limit_only"`
+	PostOnly                  bool        `json:"post_only"`
+	TradingDisabled           bool        `json:"trading_disabled"`
+	AuctionMode               bool        `json:"auction_mode"`
+	ProductType               ProductType `json:"product_type"`
+	QuoteCurrencyID           string      `json:"quote_currency_id"`
+	BaseCurrencyID            string      `json:"base_currency_id"`
+	MidMarketPrice            string      `json:"mid_market_price"`
+	BaseDisplaySymbol         string      `json:"base_display_symbol"`
+	QuoteDisplaySymbol        string      `json:"quote_display_symbol"`
+
+	// FutureProductDetails is only set for futures products.
+	FutureProductDetails *FutureProductDetails `json:"future_product_details,omitempty"`
+
+	// FCMTradingSessionDetails is only set for futures products, which
+	// can only be traded while their session is open.
+	FCMTradingSessionDetails *FCMTradingSessionDetails `json:"fcm_trading_session_details,omitempty"`
+}
+
+// CanTrade returns ErrProductNotTradable if trading in the product is disabled
+// or its status is not online, as happens during maintenance.
+func (product Product) CanTrade() error {
+	if product.TradingDisabled {
+		return fmt.Errorf("%w: trading in %s is disabled", ErrProductNotTradable, product.ProductID)
+	}
+
+	if !strings.EqualFold(product.Status, productStatusOnline) {
+		return fmt.Errorf("%w: %s has status %q", ErrProductNotTradable, product.ProductID, product.Status)
+	}
+
+	return nil
+}
+
+// IsTradingSessionOpen reports whether the product's trading session is open.
+// Products without a trading session, such as spot products, trade around the
+// clock and are always reported as open.
+func (product Product) IsTradingSessionOpen() bool {
+	if product.FCMTradingSessionDetails == nil {
+		return true
+	}
+
+	return product.FCMTradingSessionDetails.IsSessionOpen
+}
+
+// Products represents a page of products along with the total number of
+// products matching the request.
+type Products struct {
+	Data        []Product `json:"products"`
+	NumProducts int32     `json:"num_products"`
+}
+
+// ListProductsParams are the query parameters used to filter the products
+// returned by ListProducts. Zero values are omitted from the request.
+type ListProductsParams struct {
+	ProductType ProductType
+	ProductIDs  []string
+
+	// Limit is the number of products to return and Offset the number of
+	// products to skip, since products are paged by offset rather than by
+	// cursor.
+	Limit  int32
+	Offset int32
+}
+
+// query returns the URL query values for the parameters.
+func (params ListProductsParams) query() url.Values {
+	query := url.Values{}
+
+	if params.ProductType != "" {
+		query.Set("product_type", string(params.ProductType))
+	}
+
+	for _, productID := range params.ProductIDs {
+		query.Add("product_ids", productID)
+	}
+
+	formatBase := 10
+
+	if params.Limit > 0 {
+		query.Set("limit", strconv.FormatInt(int64(params.Limit), formatBase))
+	}
+
+	if params.Offset > 0 {
+		query.Set("offset", strconv.FormatInt(int64(params.Offset), formatBase))
+	}
+
+	return query
+}
+
+// ListProducts returns the products matching the given parameters. The Data of
+// an empty result is an empty slice, never nil.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_getproducts
+func (client *Client) ListProducts(ctx context.Context, params ListProductsParams,
+	opts ...CallOption,
+) (*Products, error) {
+	path := []string{"brokerage", "products"}
+
+	products := &Products{}
+	if err := client.do(ctx, http.MethodGet, path, params.query(), nil, products, opts); err != nil {
+		return nil, err
+	}
+
+	products.Data = emptyIfNil(products.Data)
+
+	return products, nil
+}
+
+// GetProduct returns the product with the given ID.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_getproduct
+func (client *Client) GetProduct(ctx context.Context, productID string, opts ...CallOption) (*Product, error) {
+	path := []string{"brokerage", "products", productID}
+
+	product := &Product{}
+	if err := client.do(ctx, http.MethodGet, path, nil, nil, product, opts); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// ProductsAll returns every product matching the given parameters, walking the
+// pages from the parameters' offset until num_products have been seen. A
+// product that moves between pages while they are walked is only returned
+// once.
+func (client *Client) ProductsAll(ctx context.Context, params ListProductsParams,
+	opts ...CallOption,
+) ([]Product, error) {
+	var all []Product
+
+	seen := make(map[string]bool)
+
+	for {
+		products, err := client.ListProducts(ctx, params, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, product := range products.Data {
+			if !seen[product.ProductID] {
+				seen[product.ProductID] = true
+				all = append(all, product)
+			}
+		}
+
+		params.Offset += int32(len(products.Data))
+
+		if len(products.Data) == 0 || params.Offset >= products.NumProducts {
+			return all, nil
+		}
+	}
+}
+
+// ListProductsByQuote returns the products quoted in the given currency,
+// matched case-insensitively. A product's quote currency is taken from its
+// quote_currency_id when present and from the suffix of its product ID
+// otherwise.
+func (client *Client) ListProductsByQuote(ctx context.Context, quote string,
+	opts ...CallOption,
+) ([]Product, error) {
+	products, err := client.ProductsAll(ctx, ListProductsParams{}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var quoted []Product
+
+	for _, product := range products {
+		if strings.EqualFold(product.quoteCurrency(), quote) {
+			quoted = append(quoted, product)
+		}
+	}
+
+	return quoted, nil
+}
+
+// quoteCurrency returns the currency the product is quoted in.
+func (product Product) quoteCurrency() string {
+	if product.QuoteCurrencyID != "" {
+		return product.QuoteCurrencyID
+	}
+
+	if i := strings.LastIndex(product.ProductID, "-"); i >= 0 {
+		return product.ProductID[i+1:]
+	}
+
+	return ""
+}
@@ -0,0 +1,363 @@
+package coinbase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListProducts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response []byte
+		want     *Products
+		err      error
+	}{
+		{
+			name: "nil",
+			err:  io.EOF, // end of file, nothing in response
+		},
+		{
+			name:     "empty slice",
+			response: []byte(`{}`),
+			want:     &Products{Data: []Product{}},
+		},
+		{
+			name: "single",
+			response: []byte(`
+{
+  "products": [{
+    "product_id": "BTC-USD",
+    "price": "140.21",
+    "base_increment": "0.00000001",
+    "quote_increment": "0.01",
+    "base_name": "Bitcoin",
+    "quote_name": "US Dollar",
+    "status": "online",
+    "trading_disabled": false,
+    "product_type": "SPOT",
+    "quote_currency_id": "USD",
+    "base_currency_id": "BTC"
+  }]
+}`),
+			want: &Products{
+				Data: []Product{
+					{
+						ProductID:       "BTC-USD",
+						Price:           "140.21",
+						BaseIncrement:   "0.00000001",
+						QuoteIncrement:  "0.01",
+						BaseName:        "Bitcoin",
+						QuoteName:       "US Dollar",
+						Status:          "online",
+						ProductType:     ProductTypeSpot,
+						QuoteCurrencyID: "USD",
+						BaseCurrencyID:  "BTC",
+					},
+				},
+			},
+		},
+		{
+			name: "future",
+			response: []byte(`
+{
+  "products": [{
+    "product_id": "BIT-28JUL23-CDE",
+    "product_type": "FUTURE",
+    "quote_currency_id": "USD",
+    "future_product_details": {
+      "venue": "cde",
+      "contract_code": "BIT",
+      "contract_expiry": "2023-07-28T15:00:00Z",
+      "contract_size": "0.01",
+      "contract_root_unit": "BTC",
+      "group_description": "Nano Bitcoin Futures",
+      "contract_expiry_timezone": "Europe/London",
+      "group_short_description": "Nano BTC",
+      "risk_managed_by": "MANAGED_BY_FCM",
+      "contract_expiry_type": "EXPIRING",
+      "contract_display_name": "BTC 28 JUL 23",
+      "perpetual_details": {
+        "open_interest": "1000",
+        "funding_rate": "0.0001",
+        "funding_time": "2023-07-28T08:00:00Z"
+      }
+    }
+  }],
+  "num_products": 1
+}`),
+			want: &Products{
+				Data: []Product{
+					{
+						ProductID:       "BIT-28JUL23-CDE",
+						ProductType:     ProductTypeFuture,
+						QuoteCurrencyID: "USD",
+						FutureProductDetails: &FutureProductDetails{
+							Venue:                  "cde",
+							ContractCode:           "BIT",
+							ContractExpiry:         time.Date(2023, time.July, 28, 15, 0, 0, 0, time.UTC),
+							ContractSize:           "0.01",
+							ContractRootUnit:       "BTC",
+							GroupDescription:       "Nano Bitcoin Futures",
+							ContractExpiryTimezone: "Europe/London",
+							GroupShortDescription:  "Nano BTC",
+							RiskManagedBy:          "MANAGED_BY_FCM",
+							ContractExpiryType:     "EXPIRING",
+							ContractDisplayName:    "BTC 28 JUL 23",
+							PerpetualDetails: &PerpetualDetails{
+								OpenInterest: "1000",
+								FundingRate:  "0.0001",
+								FundingTime:  time.Date(2023, time.July, 28, 8, 0, 0, 0, time.UTC),
+							},
+						},
+					},
+				},
+				NumProducts: 1,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{
+				httpClient: &mockClient{
+					response:   test.response,
+					statusCode: http.StatusOK,
+				},
+			}
+
+			got, err := client.ListProducts(context.Background(), ListProductsParams{})
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGetProduct(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response []byte
+		want     *Product
+		err      error
+	}{
+		{
+			name: "nil",
+			err:  io.EOF, // end of file, nothing in response
+		},
+		{
+			name:     "empty",
+			response: []byte(`{}`),
+			want:     &Product{},
+		},
+		{
+			name:     "single",
+			response: []byte(`{"product_id": "BTC-USD", "base_increment": "0.00000001", "status": "online"}`),
+			want:     &Product{ProductID: "BTC-USD", BaseIncrement: "0.00000001", Status: "online"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotPath string
+
+			client := &Client{
+				httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+					gotPath = req.URL.Path
+
+					return newMockResponse(http.StatusOK, string(test.response)), nil
+				}),
+			}
+
+			got, err := client.GetProduct(context.Background(), "BTC-USD")
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+
+			if want := "/api/v3/brokerage/products/BTC-USD"; gotPath != want {
+				t.Fatalf("got path %q, want %q", gotPath, want)
+			}
+		})
+	}
+}
+
+func TestListProductsByQuote(t *testing.T) {
+	t.Parallel()
+
+	response := `{"products": [
+		{"product_id": "BTC-USD", "quote_currency_id": "USD"},
+		{"product_id": "ETH-EUR", "quote_currency_id": "EUR"},
+		{"product_id": "SOL-USD"},
+		{"product_id": "USDC-EUR", "quote_currency_id": "EUR"},
+		{"product_id": "BIT-28JUL23-CDE", "quote_currency_id": "USD"}
+	]}`
+
+	client := &Client{
+		httpClient: &mockClient{
+			response:   []byte(response),
+			statusCode: http.StatusOK,
+		},
+	}
+
+	products, err := client.ListProductsByQuote(context.Background(), "usd")
+	if err != nil {
+		t.Fatalf("failed to list products: %v", err)
+	}
+
+	var got []string
+	for _, product := range products {
+		got = append(got, product.ProductID)
+	}
+
+	if want := []string{"BTC-USD", "SOL-USD", "BIT-28JUL23-CDE"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestProductsAll(t *testing.T) {
+	t.Parallel()
+
+	// The third page repeats a product from the second, as happens when a
+	// product is listed while the pages are walked.
+	pages := map[string]string{
+		"":  `{"products": [{"product_id": "A-USD"}, {"product_id": "B-USD"}], "num_products": 5}`,
+		"2": `{"products": [{"product_id": "C-USD"}, {"product_id": "D-USD"}], "num_products": 5}`,
+		"4": `{"products": [{"product_id": "D-USD"}, {"product_id": "E-USD"}], "num_products": 5}`,
+	}
+
+	var offsets []string
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			offset := req.URL.Query().Get("offset")
+			offsets = append(offsets, offset)
+
+			if limit := req.URL.Query().Get("limit"); limit != "2" {
+				t.Errorf("got limit %q, want 2", limit)
+			}
+
+			return newMockResponse(http.StatusOK, pages[offset]), nil
+		}),
+	}
+
+	products, err := client.ProductsAll(context.Background(), ListProductsParams{Limit: 2})
+	if err != nil {
+		t.Fatalf("failed to list products: %v", err)
+	}
+
+	var got []string
+	for _, product := range products {
+		got = append(got, product.ProductID)
+	}
+
+	if want := []string{"A-USD", "B-USD", "C-USD", "D-USD", "E-USD"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if want := []string{"", "2", "4"}; !reflect.DeepEqual(offsets, want) {
+		t.Fatalf("got offsets %v, want %v", offsets, want)
+	}
+}
+
+func TestProductIsTradingSessionOpen(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response string
+		want     bool
+	}{
+		{
+			name:     "spot",
+			response: `{"product_id": "BTC-USD"}`,
+			want:     true,
+		},
+		{
+			name: "open session",
+			response: `{"product_id": "BIT-28JUL23-CDE", "fcm_trading_session_details": {
+				"is_session_open": true,
+				"open_time": "2023-07-27T22:00:00Z",
+				"close_time": "2023-07-28T21:00:00Z"
+			}}`,
+			want: true,
+		},
+		{
+			name: "closed session",
+			response: `{"product_id": "BIT-28JUL23-CDE", "fcm_trading_session_details": {
+				"is_session_open": false,
+				"open_time": "2023-07-28T22:00:00Z",
+				"close_time": "2023-07-29T21:00:00Z"
+			}}`,
+			want: false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			product := Product{}
+			if err := json.Unmarshal([]byte(test.response), &product); err != nil {
+				t.Fatalf("failed to decode product: %v", err)
+			}
+
+			if got := product.IsTradingSessionOpen(); got != test.want {
+				t.Fatalf("got %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestProductCanTrade(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		product Product
+		err     error
+	}{
+		{name: "online", product: Product{Status: "online"}},
+		{name: "upper case status", product: Product{Status: "ONLINE"}},
+		{name: "trading disabled", product: Product{Status: "online", TradingDisabled: true}, err: ErrProductNotTradable},
+		{name: "offline", product: Product{Status: "offline"}, err: ErrProductNotTradable},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := test.product.CanTrade(); !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+		})
+	}
+}
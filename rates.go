@@ -0,0 +1,229 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrMissingExchangeRate is wrapped by MissingRatesError.
+var ErrMissingExchangeRate = errors.New("missing exchange rate")
+
+// MissingRatesError is returned when balances cannot be converted to a
+// currency because there is no exchange rate for some of their currencies.
+type MissingRatesError struct {
+	// Quote is the currency that the balances were being converted to.
+	Quote string
+
+	// Currencies are the currencies without an exchange rate, sorted.
+	Currencies []string
+}
+
+// Error implements the error interface.
+func (err *MissingRatesError) Error() string {
+	return fmt.Sprintf("%v: no %s rate for %s", ErrMissingExchangeRate, err.Quote, strings.Join(err.Currencies, ", "))
+}
+
+// Unwrap returns ErrMissingExchangeRate.
+func (err *MissingRatesError) Unwrap() error {
+	return ErrMissingExchangeRate
+}
+
+// ExchangeRates represents the exchange rates from a base currency. Rates are
+// keyed by currency, and each is the amount of that currency that one unit of
+// the base currency buys.
+type ExchangeRates struct {
+	Currency string            `json:"currency"`
+	Rates    map[string]string `json:"rates"`
+}
+
+// exchangeRatesResponse is the response from getting exchange rates.
+type exchangeRatesResponse struct {
+	Data ExchangeRates `json:"data"`
+}
+
+// ExchangeRates returns the exchange rates from the given base currency, such
+// as "USD".
+//
+// https://docs.cloud.coinbase.com/sign-in-with-coinbase/docs/api-exchange-rates
+func (client *Client) ExchangeRates(ctx context.Context, currency string, opts ...CallOption) (*ExchangeRates, error) {
+	query := url.Values{}
+	query.Set("currency", strings.ToUpper(currency))
+
+	path := []string{"exchange-rates"}
+
+	resp := &exchangeRatesResponse{}
+	if err := client.doWithBase(ctx, apiV2, http.MethodGet, path, query, nil, resp, opts); err != nil {
+		return nil, err
+	}
+
+	return &resp.Data, nil
+}
+
+// TotalBalance returns the total value of every account of the authenticated
+// user, including both the available and held balances, converted to the
+// given quote currency using the current exchange rates. If a non-zero balance
+// is in a currency without an exchange rate, a *MissingRatesError listing
+// those currencies is returned.
+func (client *Client) TotalBalance(ctx context.Context, quoteCurrency string,
+	opts ...CallOption,
+) (decimal.Decimal, error) {
+	quoteCurrency = strings.ToUpper(quoteCurrency)
+
+	rates, err := client.ExchangeRates(ctx, quoteCurrency, opts...)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to get exchange rates: %w", err)
+	}
+
+	total := decimal.Zero
+	missing := make(map[string]bool)
+	pager := client.AccountsPager(ctx, opts...)
+
+	for pager.Next() {
+		account := pager.Account()
+
+		for _, money := range []Money{account.AvailableBalance, account.Hold} {
+			value, ok, err := rates.convert(money, quoteCurrency)
+			if err != nil {
+				return decimal.Decimal{}, err
+			}
+
+			if !ok {
+				missing[strings.ToUpper(money.Currency)] = true
+
+				continue
+			}
+
+			total = total.Add(value)
+		}
+	}
+
+	if err := pager.Err(); err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	if len(missing) > 0 {
+		currencies := make([]string, 0, len(missing))
+		for currency := range missing {
+			currencies = append(currencies, currency)
+		}
+
+		sort.Strings(currencies)
+
+		return decimal.Decimal{}, &MissingRatesError{Quote: quoteCurrency, Currencies: currencies}
+	}
+
+	return total, nil
+}
+
+// convert returns the value of the money in the quote currency, which must be
+// the base currency of the rates. It returns false if the money is non-zero
+// and there is no rate for its currency.
+func (rates *ExchangeRates) convert(money Money, quoteCurrency string) (decimal.Decimal, bool, error) {
+	if money.Value == "" {
+		return decimal.Zero, true, nil
+	}
+
+	amount, err := money.Amount()
+	if err != nil {
+		return decimal.Decimal{}, false, err
+	}
+
+	if amount.IsZero() || strings.EqualFold(money.Currency, quoteCurrency) {
+		return amount, true, nil
+	}
+
+	raw, ok := rates.Rates[strings.ToUpper(money.Currency)]
+	if !ok {
+		return decimal.Decimal{}, false, nil
+	}
+
+	rate, err := decimal.NewFromString(raw)
+	if err != nil {
+		return decimal.Decimal{}, false, fmt.Errorf("failed to parse %s rate %q: %w", money.Currency, raw, err)
+	}
+
+	if rate.IsZero() {
+		return decimal.Decimal{}, false, nil
+	}
+
+	return amount.Div(rate), true, nil
+}
+
+// AccountsMap pages through all of the user's accounts and returns their
+// available balances keyed by currency. The balances of accounts that share a
+// currency are summed.
+func (client *Client) AccountsMap(ctx context.Context, opts ...CallOption) (map[string]AvailableMoney, error) {
+	totals := make(map[string]decimal.Decimal)
+	pager := client.AccountsPager(ctx, opts...)
+
+	for pager.Next() {
+		account := pager.Account()
+
+		currency := account.AvailableBalance.Currency
+		if currency == "" {
+			currency = account.Currency
+		}
+
+		amount := decimal.Zero
+
+		if account.AvailableBalance.Value != "" {
+			var err error
+			if amount, err = account.AvailableBalance.Amount(); err != nil {
+				return nil, err
+			}
+		}
+
+		totals[currency] = totals[currency].Add(amount)
+	}
+
+	if err := pager.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	balances := make(map[string]AvailableMoney, len(totals))
+	for currency, total := range totals {
+		balances[currency] = AvailableMoney{Value: total.String(), Currency: currency}
+	}
+
+	return balances, nil
+}
+
+// NonZeroAccounts pages through all of the user's accounts and returns those
+// whose available balance and hold add up to more than zero, leaving out empty
+// wallets. An empty value counts as zero.
+func (client *Client) NonZeroAccounts(ctx context.Context, opts ...CallOption) ([]Account, error) {
+	var accounts []Account
+
+	pager := client.AccountsPager(ctx, opts...)
+
+	for pager.Next() {
+		account := pager.Account()
+
+		available, err := account.AvailableBalance.amountOrZero()
+		if err != nil {
+			return nil, err
+		}
+
+		hold, err := account.Hold.amountOrZero()
+		if err != nil {
+			return nil, err
+		}
+
+		if available.Add(hold).IsPositive() {
+			accounts = append(accounts, account)
+		}
+	}
+
+	if err := pager.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	return accounts, nil
+}
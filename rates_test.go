@@ -0,0 +1,238 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestExchangeRates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response []byte
+		want     *ExchangeRates
+		err      error
+	}{
+		{
+			name: "nil",
+			err:  io.EOF, // end of file, nothing in response
+		},
+		{
+			name:     "empty",
+			response: []byte(`{}`),
+			want:     &ExchangeRates{},
+		},
+		{
+			name: "rates",
+			response: []byte(`
+{
+  "data": {
+    "currency": "USD",
+    "rates": {
+      "BTC": "0.00004",
+      "ETH": "0.0005",
+      "USD": "1.0"
+    }
+  }
+}`),
+			want: &ExchangeRates{
+				Currency: "USD",
+				Rates: map[string]string{
+					"BTC": "0.00004",
+					"ETH": "0.0005",
+					"USD": "1.0",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotURL string
+
+			client := &Client{
+				httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+					gotURL = req.URL.String()
+
+					return newMockResponse(http.StatusOK, string(test.response)), nil
+				}),
+			}
+
+			got, err := client.ExchangeRates(context.Background(), "usd")
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+
+			if want := "https://api.coinbase.com/v2/exchange-rates?currency=USD"; gotURL != want {
+				t.Fatalf("got URL %q, want %q", gotURL, want)
+			}
+		})
+	}
+}
+
+// mockBalances returns a mock HTTP client serving the given exchange rates
+// and a single page of accounts.
+func mockBalances(rates, accounts string) mockDoFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/v2/exchange-rates" {
+			return newMockResponse(http.StatusOK, rates), nil
+		}
+
+		return newMockResponse(http.StatusOK, accounts), nil
+	}
+}
+
+func TestTotalBalance(t *testing.T) {
+	t.Parallel()
+
+	rates := `{"data": {"currency": "USD", "rates": {"BTC": "0.00004", "ETH": "0.0005", "USD": "1"}}}`
+	accounts := `{"accounts": [
+		{
+			"currency": "BTC",
+			"available_balance": {"value": "0.5", "currency": "BTC"},
+			"hold": {"value": "0.1", "currency": "BTC"}
+		},
+		{
+			"currency": "ETH",
+			"available_balance": {"value": "2", "currency": "ETH"},
+			"hold": {"value": "0", "currency": "ETH"}
+		},
+		{
+			"currency": "USD",
+			"available_balance": {"value": "100.25", "currency": "USD"},
+			"hold": {"value": "", "currency": "USD"}
+		},
+		{
+			"currency": "DOGE",
+			"available_balance": {"value": "0", "currency": "DOGE"},
+			"hold": {"value": "0", "currency": "DOGE"}
+		}
+	]}`
+
+	client := &Client{httpClient: mockBalances(rates, accounts)}
+
+	got, err := client.TotalBalance(context.Background(), "usd")
+	if err != nil {
+		t.Fatalf("failed to get total balance: %v", err)
+	}
+
+	// 0.6 BTC at 25000 + 2 ETH at 2000 + 100.25 USD, with DOGE ignored
+	// since it has no balance.
+	want := decimal.RequireFromString("19100.25")
+	if !got.Equal(want) {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestTotalBalanceMissingRates(t *testing.T) {
+	t.Parallel()
+
+	rates := `{"data": {"currency": "USD", "rates": {"BTC": "0.00004"}}}`
+	accounts := `{"accounts": [
+		{"currency": "BTC", "available_balance": {"value": "1", "currency": "BTC"}},
+		{"currency": "XRP", "available_balance": {"value": "10", "currency": "XRP"}},
+		{"currency": "SOL", "hold": {"value": "3", "currency": "SOL"}}
+	]}`
+
+	client := &Client{httpClient: mockBalances(rates, accounts)}
+
+	_, err := client.TotalBalance(context.Background(), "USD")
+	if !errors.Is(err, ErrMissingExchangeRate) {
+		t.Fatalf("got %v, want %v", err, ErrMissingExchangeRate)
+	}
+
+	missing := &MissingRatesError{}
+	if !errors.As(err, &missing) {
+		t.Fatalf("got %T, want %T", err, missing)
+	}
+
+	if want := []string{"SOL", "XRP"}; !reflect.DeepEqual(missing.Currencies, want) {
+		t.Fatalf("got %v, want %v", missing.Currencies, want)
+	}
+}
+
+func TestAccountsMap(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]string{
+		"": `{"accounts": [
+			{"currency": "BTC", "available_balance": {"value": "0.5", "currency": "BTC"}},
+			{"currency": "USD", "available_balance": {"value": "100.25", "currency": "USD"}}
+		], "has_next": true, "cursor": "page-2"}`,
+		"page-2": `{"accounts": [
+			{"currency": "BTC", "available_balance": {"value": "0.25", "currency": "BTC"}},
+			{"currency": "ETH", "available_balance": {"value": "", "currency": "ETH"}}
+		], "has_next": false, "cursor": ""}`,
+	}
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			return newMockResponse(http.StatusOK, pages[req.URL.Query().Get("cursor")]), nil
+		}),
+	}
+
+	got, err := client.AccountsMap(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get accounts map: %v", err)
+	}
+
+	want := map[string]AvailableMoney{
+		"BTC": {Value: "0.75", Currency: "BTC"},
+		"ETH": {Value: "0", Currency: "ETH"},
+		"USD": {Value: "100.25", Currency: "USD"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestNonZeroAccounts(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]string{
+		"": `{"accounts": [
+			{"uuid": "btc", "available_balance": {"value": "0.5", "currency": "BTC"}, "hold": {"value": "0", "currency": "BTC"}},
+			{"uuid": "eth", "available_balance": {"value": "0", "currency": "ETH"}, "hold": {"value": "0", "currency": "ETH"}},
+			{"uuid": "usd", "available_balance": {"value": "", "currency": "USD"}, "hold": {"value": "12.5", "currency": "USD"}}
+		], "has_next": true, "cursor": "page-2"}`,
+		"page-2": `{"accounts": [
+			{"uuid": "sol", "available_balance": {"value": "", "currency": "SOL"}},
+			{"uuid": "dust", "available_balance": {"value": "0.00000001", "currency": "DOGE"}}
+		], "has_next": false, "cursor": ""}`,
+	}
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			return newMockResponse(http.StatusOK, pages[req.URL.Query().Get("cursor")]), nil
+		}),
+	}
+
+	accounts, err := client.NonZeroAccounts(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get non-zero accounts: %v", err)
+	}
+
+	got := make([]string, len(accounts))
+	for i, account := range accounts {
+		got[i] = account.UUID
+	}
+
+	if want := []string{"btc", "usd", "dust"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got accounts %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,173 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// replaceCancelTimeout is how long ReplaceOrder waits for the order to
+	// replace to be cancelled, and replaceCancelPollInterval how often it
+	// looks the order up while waiting.
+	replaceCancelTimeout      = 10 * time.Second
+	replaceCancelPollInterval = 100 * time.Millisecond
+)
+
+// ErrCancelFailed is returned by ReplaceOrder when the order to replace could
+// not be cancelled, such as because it has already been filled, or was not
+// seen to be cancelled in time, in which case the replacement is not created.
+var ErrCancelFailed = errors.New("order was not cancelled")
+
+// cancelError is returned when the order to replace was not seen to be
+// cancelled. It matches ErrCancelFailed and unwraps to the error looking the
+// order up, if any.
+type cancelError struct {
+	orderID string
+	reason  string
+	err     error
+}
+
+func (err *cancelError) Error() string {
+	if err.err != nil {
+		return fmt.Sprintf("%s: %s: %s: %s", ErrCancelFailed, err.orderID, err.reason, err.err)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", ErrCancelFailed, err.orderID, err.reason)
+}
+
+func (err *cancelError) Unwrap() error {
+	return err.err
+}
+
+func (err *cancelError) Is(target error) bool {
+	return target == ErrCancelFailed
+}
+
+// ErrReplacementFailed is returned by ReplaceOrder when the order to replace
+// was cancelled but its replacement could not be created, which leaves neither
+// order open.
+var ErrReplacementFailed = errors.New("order was cancelled but its replacement was not created")
+
+// replacementError is returned when the replacement of a cancelled order
+// failed. It matches ErrReplacementFailed and unwraps to the error creating
+// the replacement, if it was not rejected in the response instead.
+type replacementError struct {
+	orderID string
+	err     error
+
+	// response is the error response rejecting the replacement.
+	response ErrorResponse
+}
+
+func (err *replacementError) Error() string {
+	if err.err != nil {
+		return fmt.Sprintf("%s: %s: %s", ErrReplacementFailed, err.orderID, err.err)
+	}
+
+	return fmt.Sprintf("%s: %s: %s: %s", ErrReplacementFailed, err.orderID, err.response.Error, err.response.Message)
+}
+
+func (err *replacementError) Unwrap() error {
+	return err.err
+}
+
+func (err *replacementError) Is(target error) bool {
+	return target == ErrReplacementFailed
+}
+
+// ReplaceOrderResult represents the results of the two steps of ReplaceOrder.
+type ReplaceOrderResult struct {
+	// Cancel is the result of cancelling the order to replace.
+	Cancel CancelOrderResult
+
+	// Order is the response to creating the replacement, or nil if it
+	// was not created.
+	Order *Order
+}
+
+// ReplaceOrder replaces an order by cancelling it and, once the cancel has been
+// confirmed, creating the new order. The cancel is confirmed by looking the
+// order up with GetOrder until its status is CANCELLED, or another terminal
+// status in which it can no longer fill, since the cancel request being
+// accepted does not mean that the order has been cancelled. Since Coinbase has
+// no atomic replace, the old order may fill before it is cancelled, in which
+// case ErrCancelFailed is returned and the new order is not created, as it is
+// if the order is not cancelled within ten seconds.
+//
+// If the cancel succeeds but the new order fails, an error matching
+// ErrReplacementFailed is returned, which also wraps the reason, such as
+// ErrPostOnlyWouldCross, so that it is not mistaken for a failure that left the
+// old order open. The result is returned along with the error
+// whenever the cancel was sent, and holds the response to the new order if one
+// was received.
+func (client *Client) ReplaceOrder(ctx context.Context, orderID string, newReq OrderRequest,
+	opts ...CallOption,
+) (*ReplaceOrderResult, error) {
+	if err := newReq.Validate(); err != nil {
+		return nil, err
+	}
+
+	cancelled, err := client.CancelOrders(ctx, []string{orderID}, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel order %s: %w", orderID, err)
+	}
+
+	result := &ReplaceOrderResult{Cancel: CancelOrderResult{OrderID: orderID}}
+
+	for _, res := range cancelled.Results {
+		if res.OrderID == orderID {
+			result.Cancel = res
+		}
+	}
+
+	if !result.Cancel.Success {
+		return result, fmt.Errorf("%w: %s: %s", ErrCancelFailed, orderID, result.Cancel.FailureReason)
+	}
+
+	if err := client.waitForCancel(ctx, orderID, opts); err != nil {
+		return result, err
+	}
+
+	order, err := client.CreateOrder(ctx, newReq, opts...)
+	if err != nil {
+		return result, &replacementError{orderID: orderID, err: err}
+	}
+
+	result.Order = order
+
+	if !order.Success {
+		return result, &replacementError{orderID: orderID, response: order.ErrorResponse}
+	}
+
+	return result, nil
+}
+
+// waitForCancel looks the order up until it can no longer fill, returning an
+// error matching ErrCancelFailed if it was filled or is still live once
+// replaceCancelTimeout has passed.
+func (client *Client) waitForCancel(ctx context.Context, orderID string, opts []CallOption) error {
+	ctx, cancel := context.WithTimeout(ctx, replaceCancelTimeout)
+	defer cancel()
+
+	for {
+		order, err := client.GetOrder(ctx, orderID, opts...)
+		if err != nil {
+			return &cancelError{orderID: orderID, reason: "failed to confirm the cancel", err: err}
+		}
+
+		switch {
+		case order.Status == OrderStatusFilled:
+			return &cancelError{orderID: orderID, reason: "order was filled"}
+		case order.Status.IsTerminal():
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return &cancelError{orderID: orderID, reason: "order is still " + string(order.Status), err: ctx.Err()}
+		case <-time.After(replaceCancelPollInterval):
+		}
+	}
+}
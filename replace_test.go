@@ -0,0 +1,200 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// mockOrderStatuses returns a response for getting the order "old" with the
+// next of the statuses, repeating the last one.
+func mockOrderStatuses(statuses []OrderStatus) func() *http.Response {
+	var calls int32
+
+	return func() *http.Response {
+		i := int(atomic.AddInt32(&calls, 1)) - 1
+		if i >= len(statuses) {
+			i = len(statuses) - 1
+		}
+
+		return newMockResponse(http.StatusOK, `{"order": {"order_id": "old", "status": "`+string(statuses[i])+`"}}`)
+	}
+}
+
+func TestReplaceOrder(t *testing.T) {
+	t.Parallel()
+
+	newReq := OrderRequest{
+		ClientOrderID: "new",
+		ProductID:     "BTC-USD",
+		Side:          OrderSideBuy,
+		Configuration: OrderConfig{LimitGTC: &LimitGTCConfig{BaseSize: "1", Price: "100", PostOnly: true}},
+	}
+
+	tests := []struct {
+		name       string
+		cancel     string
+		create     string
+		createCode int
+		statuses   []OrderStatus // the statuses of the old order, CANCELLED if nil
+		err        error
+		wantOrder  bool // whether the result has the new order's response
+		wantCreate bool // whether the new order was sent
+	}{
+		{
+			name:       "replaced",
+			cancel:     `{"results": [{"success": true, "order_id": "old"}]}`,
+			create:     `{"success": true, "order_id": "new"}`,
+			createCode: http.StatusOK,
+			wantOrder:  true,
+			wantCreate: true,
+		},
+		{
+			name:       "replaced once cancel is processed",
+			cancel:     `{"results": [{"success": true, "order_id": "old"}]}`,
+			create:     `{"success": true, "order_id": "new"}`,
+			createCode: http.StatusOK,
+			statuses:   []OrderStatus{OrderStatusCancelQueued, OrderStatusOpen, OrderStatusCancelled},
+			wantOrder:  true,
+			wantCreate: true,
+		},
+		{
+			name:     "filled before cancel",
+			cancel:   `{"results": [{"success": true, "order_id": "old"}]}`,
+			statuses: []OrderStatus{OrderStatusCancelQueued, OrderStatusFilled},
+			err:      ErrCancelFailed,
+		},
+		{
+			name:   "cancel failed",
+			cancel: `{"results": [{"success": false, "failure_reason": "UNKNOWN_CANCEL_ORDER", "order_id": "old"}]}`,
+			err:    ErrCancelFailed,
+		},
+		{
+			name:       "create failed",
+			cancel:     `{"results": [{"success": true, "order_id": "old"}]}`,
+			create:     `{"error": "INTERNAL"}`,
+			createCode: http.StatusBadRequest,
+			err:        ErrStatusNotOK,
+			wantCreate: true,
+		},
+		{
+			name:   "create rejected",
+			cancel: `{"results": [{"success": true, "order_id": "old"}]}`,
+			create: `{"success": false, "error_response": ` +
+				`{"error": "INSUFFICIENT_FUND", "message": "Insufficient balance in source account"}}`,
+			createCode: http.StatusOK,
+			err:        ErrReplacementFailed,
+			wantOrder:  true,
+			wantCreate: true,
+		},
+		{
+			name:   "create would cross",
+			cancel: `{"results": [{"success": true, "order_id": "old"}]}`,
+			create: `{"success": false, "error_response": ` +
+				`{"error": "INVALID_LIMIT_PRICE_POST_ONLY", "message": "would cross"}}`,
+			createCode: http.StatusOK,
+			err:        ErrPostOnlyWouldCross,
+			wantCreate: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var created bool
+
+			statuses := test.statuses
+			if statuses == nil {
+				statuses = []OrderStatus{OrderStatusCancelled}
+			}
+
+			getOrder := mockOrderStatuses(statuses)
+
+			client := &Client{
+				httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+					switch req.URL.Path {
+					case "/api/v3/brokerage/orders/batch_cancel":
+						return newMockResponse(http.StatusOK, test.cancel), nil
+					case "/api/v3/brokerage/orders/historical/old":
+						return getOrder(), nil
+					}
+
+					created = true
+
+					return newMockResponse(test.createCode, test.create), nil
+				}),
+			}
+
+			result, err := client.ReplaceOrder(context.Background(), "old", newReq)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if created != test.wantCreate {
+				t.Fatalf("got new order sent %t, want %t", created, test.wantCreate)
+			}
+
+			// Every failure after the cancel succeeded is a failed
+			// replacement.
+			if test.wantCreate && test.err != nil && !errors.Is(err, ErrReplacementFailed) {
+				t.Fatalf("got %v, want %v", err, ErrReplacementFailed)
+			}
+
+			if result == nil || result.Cancel.OrderID != "old" {
+				t.Fatalf("got result %+v, want one for the cancelled order", result)
+			}
+
+			if (result.Order != nil) != test.wantOrder {
+				t.Fatalf("got order %+v, want one %t", result.Order, test.wantOrder)
+			}
+		})
+	}
+}
+
+func TestReplaceOrderCancelTimeout(t *testing.T) {
+	t.Parallel()
+
+	var created bool
+
+	getOrder := mockOrderStatuses([]OrderStatus{OrderStatusCancelQueued})
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			switch req.URL.Path {
+			case "/api/v3/brokerage/orders/batch_cancel":
+				return newMockResponse(http.StatusOK, `{"results": [{"success": true, "order_id": "old"}]}`), nil
+			case "/api/v3/brokerage/orders/historical/old":
+				return getOrder(), nil
+			}
+
+			created = true
+
+			return newMockResponse(http.StatusOK, `{"success": true, "order_id": "new"}`), nil
+		}),
+	}
+
+	newReq := OrderRequest{
+		ClientOrderID: "new",
+		ProductID:     "BTC-USD",
+		Side:          OrderSideBuy,
+		Configuration: OrderConfig{LimitGTC: &LimitGTCConfig{BaseSize: "1", Price: "100"}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+
+	_, err := client.ReplaceOrder(ctx, "old", newReq)
+	if !errors.Is(err, ErrCancelFailed) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want %v after the deadline", err, ErrCancelFailed)
+	}
+
+	if created {
+		t.Fatalf("got new order sent while the old order was live")
+	}
+}
@@ -0,0 +1,181 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRetryWait is the wait before the first retry of a request,
+	// which doubles for each further retry up to maxRetryWait.
+	defaultRetryWait = 100 * time.Millisecond
+	maxRetryWait     = 5 * time.Second
+
+	// defaultMaintenanceRetryWait is the wait before the first retry of a
+	// request that failed with ErrServiceUnavailable, which doubles for
+	// each further retry up to maxMaintenanceRetryWait.
+	defaultMaintenanceRetryWait = 2 * time.Second
+	maxMaintenanceRetryWait     = time.Minute
+
+	// retryBudgetBurst is the number of retries a retry budget holds when
+	// it is created, and the most it can accumulate.
+	retryBudgetBurst = 10
+)
+
+// ErrRetryBudgetExhausted is returned when a request fails with a retryable
+// error but the client's retry budget has no retries left. The error also
+// wraps the error of the last attempt.
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// WithMaxRetries retries each request up to n times when it fails with a
+// network error, a 429 or a 5xx status code, waiting with exponential backoff
+// between attempts. By default requests are not retried.
+func WithMaxRetries(n int) ClientOption {
+	return func(client *Client) {
+		client.maxRetries = n
+	}
+}
+
+// WithMaintenanceRetryWait sets the wait before the first retry of a request
+// that failed with ErrServiceUnavailable, which Coinbase returns during
+// maintenance, overriding the default of two seconds. The wait doubles for each
+// further retry, up to a minute, so that retries outlast a short maintenance
+// window without hammering the API.
+func WithMaintenanceRetryWait(wait time.Duration) ClientOption {
+	return func(client *Client) {
+		client.maintenanceRetryWait = wait
+	}
+}
+
+// WithRetryBudget caps the retries made by the client as a whole, so that a
+// sustained outage does not multiply the load on the API. Every request adds
+// ratio retries to a budget shared by all requests made with the client, and
+// every retry spends one, so a ratio of 0.1 allows roughly one retry for every
+// ten requests. Once the budget is spent requests fail fast with
+// ErrRetryBudgetExhausted instead of retrying. The budget only limits the
+// retries allowed by WithMaxRetries.
+func WithRetryBudget(ratio float64) ClientOption {
+	return func(client *Client) {
+		client.retryBudget = newRetryBudget(ratio)
+	}
+}
+
+// retryBudget is a token bucket that limits the share of requests that may be
+// retried.
+type retryBudget struct {
+	ratio float64
+
+	mu     sync.Mutex
+	tokens float64
+}
+
+// newRetryBudget returns a full retry budget that earns ratio tokens for each
+// request.
+func newRetryBudget(ratio float64) *retryBudget {
+	return &retryBudget{
+		ratio:  ratio,
+		tokens: retryBudgetBurst,
+	}
+}
+
+// deposit records a request, adding the budget's ratio of a retry.
+func (budget *retryBudget) deposit() {
+	budget.mu.Lock()
+	defer budget.mu.Unlock()
+
+	if budget.tokens += budget.ratio; budget.tokens > retryBudgetBurst {
+		budget.tokens = retryBudgetBurst
+	}
+}
+
+// withdraw spends a retry, reporting false if the budget has none left.
+func (budget *retryBudget) withdraw() bool {
+	budget.mu.Lock()
+	defer budget.mu.Unlock()
+
+	if budget.tokens < 1 {
+		return false
+	}
+
+	budget.tokens--
+
+	return true
+}
+
+// retryBudgetError is returned when a retry is denied by the retry budget. It
+// matches ErrRetryBudgetExhausted and unwraps to the error of the last
+// attempt.
+type retryBudgetError struct {
+	err error
+}
+
+func (err *retryBudgetError) Error() string {
+	return ErrRetryBudgetExhausted.Error() + ": " + err.err.Error()
+}
+
+func (err *retryBudgetError) Unwrap() error {
+	return err.err
+}
+
+func (err *retryBudgetError) Is(target error) bool {
+	return target == ErrRetryBudgetExhausted
+}
+
+// isRetryableStatus reports whether a request that failed with the given
+// status code may succeed if it is retried.
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// retry calls attempt until it succeeds, it fails with an error that is not
+// retryable, or the client's retry limits are reached. The attempt reports
+// whether its error is retryable. Attempts that fail with
+// ErrServiceUnavailable back off separately and more slowly than other
+// failures.
+func (client *Client) retry(ctx context.Context, attempt func() (bool, error)) error {
+	if client.retryBudget != nil {
+		client.retryBudget.deposit()
+	}
+
+	wait := client.retryWait
+	if wait <= 0 {
+		wait = defaultRetryWait
+	}
+
+	maintenanceWait := client.maintenanceRetryWait
+	if maintenanceWait <= 0 {
+		maintenanceWait = defaultMaintenanceRetryWait
+	}
+
+	for retries := 0; ; retries++ {
+		retryable, err := attempt()
+		if err == nil || !retryable || retries >= client.maxRetries || ctx.Err() != nil {
+			return err
+		}
+
+		maintenance := errors.Is(err, ErrServiceUnavailable)
+		if !maintenance && client.retryBudget != nil && !client.retryBudget.withdraw() {
+			return &retryBudgetError{err: err}
+		}
+
+		next := &wait
+		limit := maxRetryWait
+
+		if maintenance {
+			next, limit = &maintenanceWait, maxMaintenanceRetryWait
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(*next):
+		}
+
+		if *next *= 2; *next > limit {
+			*next = limit
+		}
+	}
+}
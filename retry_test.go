@@ -0,0 +1,201 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		statuses   []int
+		maxRetries int
+		attempts   int32
+		err        error
+	}{
+		{
+			name:     "no retries by default",
+			statuses: []int{http.StatusInternalServerError, http.StatusOK},
+			attempts: 1,
+			err:      ErrStatusNotOK,
+		},
+		{
+			name:       "retries server errors",
+			statuses:   []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK},
+			maxRetries: 3,
+			attempts:   3,
+		},
+		{
+			name:       "retries rate limits",
+			statuses:   []int{http.StatusTooManyRequests, http.StatusOK},
+			maxRetries: 1,
+			attempts:   2,
+		},
+		{
+			name:       "gives up after max retries",
+			statuses:   []int{http.StatusServiceUnavailable},
+			maxRetries: 2,
+			attempts:   3,
+			err:        ErrStatusNotOK,
+		},
+		{
+			name:       "does not retry client errors",
+			statuses:   []int{http.StatusBadRequest, http.StatusOK},
+			maxRetries: 3,
+			attempts:   1,
+			err:        ErrStatusNotOK,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts int32
+
+			client := &Client{
+				maxRetries:           test.maxRetries,
+				retryWait:            time.Millisecond,
+				maintenanceRetryWait: time.Millisecond,
+				httpClient: mockDoFunc(func(*http.Request) (*http.Response, error) {
+					n := int(atomic.AddInt32(&attempts, 1))
+					if n > len(test.statuses) {
+						n = len(test.statuses)
+					}
+
+					return newMockResponse(test.statuses[n-1], `{"accounts": []}`), nil
+				}),
+			}
+
+			_, err := client.Accounts(context.Background())
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if got := atomic.LoadInt32(&attempts); got != test.attempts {
+				t.Fatalf("got %d attempts, want %d", got, test.attempts)
+			}
+		})
+	}
+}
+
+func TestRetryMaintenance(t *testing.T) {
+	t.Parallel()
+
+	maintenanceWait := 50 * time.Millisecond
+
+	tests := []struct {
+		name     string
+		status   int
+		err      error
+		attempts int32
+		slowest  time.Duration
+		quickest time.Duration
+	}{
+		{
+			name:     "maintenance",
+			status:   http.StatusServiceUnavailable,
+			err:      ErrServiceUnavailable,
+			attempts: 3,
+			quickest: maintenanceWait + 2*maintenanceWait,
+		},
+		{
+			name:     "server error",
+			status:   http.StatusInternalServerError,
+			err:      ErrStatusNotOK,
+			attempts: 2,
+			slowest:  maintenanceWait,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts int32
+
+			client := &Client{
+				maxRetries:           2,
+				retryWait:            time.Millisecond,
+				maintenanceRetryWait: maintenanceWait,
+				// A budget of one retry, which maintenance retries
+				// are not charged to.
+				retryBudget: &retryBudget{tokens: 1},
+				httpClient: mockDoFunc(func(*http.Request) (*http.Response, error) {
+					atomic.AddInt32(&attempts, 1)
+
+					return newMockResponse(test.status, "down for maintenance"), nil
+				}),
+			}
+
+			start := time.Now()
+
+			_, err := client.Accounts(context.Background())
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			elapsed := time.Since(start)
+			if elapsed < test.quickest || (test.slowest > 0 && elapsed > test.slowest) {
+				t.Fatalf("got %v of backoff, want between %v and %v", elapsed, test.quickest, test.slowest)
+			}
+
+			if got := atomic.LoadInt32(&attempts); got != test.attempts {
+				t.Fatalf("got %d attempts, want %d", got, test.attempts)
+			}
+		})
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	t.Parallel()
+
+	var attempts int32
+
+	client := &Client{
+		maxRetries:  3,
+		retryWait:   time.Millisecond,
+		retryBudget: newRetryBudget(0.1),
+		httpClient: mockDoFunc(func(*http.Request) (*http.Response, error) {
+			atomic.AddInt32(&attempts, 1)
+
+			return newMockResponse(http.StatusInternalServerError, "outage"), nil
+		}),
+	}
+
+	requests := 100
+	exhausted := 0
+
+	for i := 0; i < requests; i++ {
+		_, err := client.Accounts(context.Background())
+		if !errors.Is(err, ErrStatusNotOK) {
+			t.Fatalf("got %v, want %v", err, ErrStatusNotOK)
+		}
+
+		if errors.Is(err, ErrRetryBudgetExhausted) {
+			exhausted++
+		}
+	}
+
+	// Without the budget every request would be attempted four times.
+	// With it, the retries are limited to the initial burst plus a tenth
+	// of the requests.
+	retries := int(atomic.LoadInt32(&attempts)) - requests
+	if limit := retryBudgetBurst + requests/10; retries > limit {
+		t.Fatalf("got %d retries, want at most %d", retries, limit)
+	}
+
+	if exhausted == 0 {
+		t.Fatalf("got no requests failing with %v", ErrRetryBudgetExhausted)
+	}
+}
@@ -0,0 +1,89 @@
+package coinbase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// clockSkewTTL is how long the skew between the local clock and the server's,
+// measured by Fresh, is cached for.
+const clockSkewTTL = 5 * time.Minute
+
+// ServerTime represents the current time of the Coinbase API.
+type ServerTime struct {
+	ISO          time.Time `json:"iso"`
+	EpochSeconds string    `json:"epochSeconds"`
+	EpochMillis  string    `json:"epochMillis"`
+}
+
+// GetServerTime returns the current time of the Coinbase API.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_getunixtime
+func (client *Client) GetServerTime(ctx context.Context, opts ...CallOption) (*ServerTime, error) {
+	path := []string{"brokerage", "time"}
+
+	serverTime := &ServerTime{}
+	if err := client.do(ctx, http.MethodGet, path, nil, nil, serverTime, opts); err != nil {
+		return nil, err
+	}
+
+	return serverTime, nil
+}
+
+// clockSkewCache holds the skew between the local clock and the server's last
+// measured by a client.
+type clockSkewCache struct {
+	mu       sync.Mutex
+	skew     time.Duration
+	measured time.Time
+}
+
+// serverClockSkew returns how far the server's clock is ahead of the local
+// clock, measuring it again with GetServerTime once the cached skew is older
+// than clockSkewTTL. The server time is compared against the local time halfway
+// through the request.
+func (client *Client) serverClockSkew(ctx context.Context, opts []CallOption) (time.Duration, error) {
+	client.clockSkew.mu.Lock()
+	skew, measured := client.clockSkew.skew, client.clockSkew.measured
+	client.clockSkew.mu.Unlock()
+
+	if !measured.IsZero() && time.Since(measured) < clockSkewTTL {
+		return skew, nil
+	}
+
+	sent := time.Now()
+
+	serverTime, err := client.GetServerTime(ctx, opts...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get server time: %w", err)
+	}
+
+	received := time.Now()
+	skew = serverTime.ISO.Sub(sent.Add(received.Sub(sent) / 2)) //nolint:gomnd
+
+	client.clockSkew.mu.Lock()
+	client.clockSkew.skew = skew
+	client.clockSkew.measured = received
+	client.clockSkew.mu.Unlock()
+
+	return skew, nil
+}
+
+// Fresh reports whether data stamped with the given time, such as a quote, is
+// at most maxAge old by the server's clock, so that stale data can be caught
+// during connectivity issues before it is acted on. The skew between the local
+// clock and the server's is measured with GetServerTime and cached, so that
+// most calls do not make a request.
+func (client *Client) Fresh(ctx context.Context, t time.Time, maxAge time.Duration,
+	opts ...CallOption,
+) (bool, error) {
+	skew, err := client.serverClockSkew(ctx, opts)
+	if err != nil {
+		return false, err
+	}
+
+	return time.Now().Add(skew).Sub(t) <= maxAge, nil
+}
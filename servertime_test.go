@@ -0,0 +1,106 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetServerTime(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response []byte
+		want     *ServerTime
+		err      error
+	}{
+		{
+			name: "nil",
+			err:  io.EOF, // end of file, nothing in response
+		},
+		{
+			name:     "empty",
+			response: []byte(`{}`),
+			want:     &ServerTime{},
+		},
+		{
+			name:     "time",
+			response: []byte(`{"iso": "2023-05-31T09:59:59Z", "epochSeconds": "1685527199", "epochMillis": "1685527199000"}`),
+			want: &ServerTime{
+				ISO:          time.Date(2023, 5, 31, 9, 59, 59, 0, time.UTC),
+				EpochSeconds: "1685527199",
+				EpochMillis:  "1685527199000",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{
+				httpClient: &mockClient{response: test.response, statusCode: http.StatusOK},
+			}
+
+			got, err := client.GetServerTime(context.Background())
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFresh(t *testing.T) {
+	t.Parallel()
+
+	// The server's clock is ten minutes ahead of the local clock.
+	skew := 10 * time.Minute
+
+	var requests int32
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&requests, 1)
+
+			iso := time.Now().Add(skew).UTC().Format(time.RFC3339Nano)
+
+			return newMockResponse(http.StatusOK, `{"iso": "`+iso+`"}`), nil
+		}),
+	}
+
+	tests := []struct {
+		name string
+		time time.Time
+		want bool
+	}{
+		{name: "fresh", time: time.Now().Add(skew - time.Second), want: true},
+		{name: "stale", time: time.Now().Add(-time.Second), want: false},
+	}
+
+	for _, test := range tests {
+		got, err := client.Fresh(context.Background(), test.time, time.Minute)
+		if err != nil {
+			t.Fatalf("%s: failed to check freshness: %v", test.name, err)
+		}
+
+		if got != test.want {
+			t.Fatalf("%s: got fresh %t, want %t", test.name, got, test.want)
+		}
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Fatalf("got %d server time requests, want 1", got)
+	}
+}
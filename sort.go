@@ -0,0 +1,77 @@
+package coinbase
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrInvalidSortDirection is returned when a sort direction is not one of the
+// SortDirection constants.
+var ErrInvalidSortDirection = errors.New("invalid sort direction")
+
+// SortDirection represents the order in which results are listed by time.
+//
+// The candles and market trades endpoints do not take a sort direction, so
+// GetProductCandles and GetMarketTrades sort each response client-side. The
+// zero value leaves results in the order the API returns them, which is newest
+// first for both.
+type SortDirection string
+
+const (
+	// SortAscending lists results oldest first.
+	SortAscending SortDirection = "ASC"
+
+	// SortDescending lists results newest first.
+	SortDescending SortDirection = "DESC"
+)
+
+// validate returns ErrInvalidSortDirection unless the direction is empty or
+// one of the SortDirection constants.
+func (direction SortDirection) validate() error {
+	switch direction {
+	case "", SortAscending, SortDescending:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q", ErrInvalidSortDirection, direction)
+}
+
+// sortByDirection stably sorts the items by the keys returned by key, in the
+// direction. Items are left as they are if the direction is empty.
+func sortByDirection[T any](items []T, direction SortDirection, key func(T) int64) {
+	if direction == "" {
+		return
+	}
+
+	keys := make([]int64, len(items))
+	for i, item := range items {
+		keys[i] = key(item)
+	}
+
+	sort.Stable(byKey[T]{items: items, keys: keys, descending: direction == SortDescending})
+}
+
+// byKey sorts items by precomputed keys, moving both together.
+type byKey[T any] struct {
+	items      []T
+	keys       []int64
+	descending bool
+}
+
+func (by byKey[T]) Len() int {
+	return len(by.items)
+}
+
+func (by byKey[T]) Less(i, j int) bool {
+	if by.descending {
+		return by.keys[i] > by.keys[j]
+	}
+
+	return by.keys[i] < by.keys[j]
+}
+
+func (by byKey[T]) Swap(i, j int) {
+	by.items[i], by.items[j] = by.items[j], by.items[i]
+	by.keys[i], by.keys[j] = by.keys[j], by.keys[i]
+}
@@ -0,0 +1,172 @@
+package coinbase
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// statsBuckets is the number of latency buckets, whose upper bounds double from
+// statsMinLatency, with the last bucket holding every slower request.
+const statsBuckets = 18
+
+// statsMinLatency is the upper bound of the first latency bucket.
+const statsMinLatency = time.Millisecond
+
+// Percentiles of the latency reported by Stats.
+const (
+	statsP50 = 0.50
+	statsP95 = 0.95
+	statsP99 = 0.99
+)
+
+// WithStats records the latency and outcome of each request made by the
+// client, per endpoint, which are then available through Stats. Latencies are
+// counted in buckets whose bounds double from one millisecond, so the
+// percentiles are upper bounds accurate to a factor of two. Recording uses
+// atomic counters, so it adds no lock to the request path once an endpoint
+// has been seen.
+func WithStats() ClientOption {
+	return func(client *Client) {
+		client.stats = &statsRecorder{}
+	}
+}
+
+// EndpointStats are the statistics of the requests made to an endpoint.
+type EndpointStats struct {
+	// Requests is the number of requests made, including retries, and
+	// Errors the number of those that failed to be sent or that had a
+	// non-2xx status code.
+	Requests uint64
+	Errors   uint64
+
+	// P50, P95 and P99 are the percentiles of the request latency.
+	P50 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+}
+
+// ClientStats are the statistics of the requests made by a client since it was
+// created.
+type ClientStats struct {
+	// Endpoints maps each endpoint, which is the request method and API
+	// path, such as "GET /brokerage/accounts", to its statistics. The
+	// path includes any IDs in it, so requests for different orders or
+	// products are counted separately.
+	Endpoints map[string]EndpointStats
+}
+
+// Stats returns the statistics of the requests made by the client, which are
+// empty unless the client was created WithStats.
+func (client *Client) Stats() ClientStats {
+	stats := ClientStats{Endpoints: make(map[string]EndpointStats)}
+	if client.stats == nil {
+		return stats
+	}
+
+	client.stats.endpoints.Range(func(key, value any) bool {
+		endpoint, _ := key.(string)
+		histogram, _ := value.(*latencyHistogram)
+		stats.Endpoints[endpoint] = histogram.snapshot()
+
+		return true
+	})
+
+	return stats
+}
+
+// statsRecorder records the requests made by a client.
+type statsRecorder struct {
+	// endpoints maps each endpoint to its *latencyHistogram.
+	endpoints sync.Map
+}
+
+// record records a request to the endpoint that took the given time and failed
+// if failed is set. Recording on a nil recorder does nothing.
+func (recorder *statsRecorder) record(endpoint string, latency time.Duration, failed bool) {
+	if recorder == nil {
+		return
+	}
+
+	value, ok := recorder.endpoints.Load(endpoint)
+	if !ok {
+		value, _ = recorder.endpoints.LoadOrStore(endpoint, &latencyHistogram{})
+	}
+
+	histogram, _ := value.(*latencyHistogram)
+	histogram.observe(latency, failed)
+}
+
+// latencyHistogram counts the requests made to an endpoint by latency.
+type latencyHistogram struct {
+	buckets  [statsBuckets]atomic.Uint64
+	requests atomic.Uint64
+	errors   atomic.Uint64
+}
+
+// observe counts a request.
+func (histogram *latencyHistogram) observe(latency time.Duration, failed bool) {
+	bucket, bound := 0, statsMinLatency
+	for bucket < statsBuckets-1 && latency > bound {
+		bucket++
+		bound *= 2
+	}
+
+	histogram.buckets[bucket].Add(1)
+	histogram.requests.Add(1)
+
+	if failed {
+		histogram.errors.Add(1)
+	}
+}
+
+// snapshot returns the statistics counted so far. Requests that are being
+// counted while the snapshot is taken may be left out of some of its fields.
+func (histogram *latencyHistogram) snapshot() EndpointStats {
+	counts := make([]uint64, statsBuckets)
+
+	var total uint64
+
+	for i := range histogram.buckets {
+		counts[i] = histogram.buckets[i].Load()
+		total += counts[i]
+	}
+
+	return EndpointStats{
+		Requests: histogram.requests.Load(),
+		Errors:   histogram.errors.Load(),
+		P50:      percentile(counts, total, statsP50),
+		P95:      percentile(counts, total, statsP95),
+		P99:      percentile(counts, total, statsP99),
+	}
+}
+
+// percentile returns the upper bound of the bucket holding the given
+// percentile of the counts, or zero if nothing was counted. The bound of the
+// last bucket, which has none, is reported as twice that of the one before
+// it.
+func percentile(counts []uint64, total uint64, p float64) time.Duration {
+	if total == 0 {
+		return 0
+	}
+
+	rank := uint64(p * float64(total))
+	if rank == 0 {
+		rank = 1
+	}
+
+	var seen uint64
+
+	bound := statsMinLatency
+
+	for i, count := range counts {
+		seen += count
+		if seen >= rank || i == len(counts)-1 {
+			break
+		}
+
+		bound *= 2
+	}
+
+	return bound
+}
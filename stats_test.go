@@ -0,0 +1,89 @@
+package coinbase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{
+		stats: &statsRecorder{},
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == "/api/v3/brokerage/products" {
+				return newMockResponse(http.StatusBadRequest, `{"error": "INVALID_ARGUMENT"}`), nil
+			}
+
+			return newMockResponse(http.StatusOK, `{}`), nil
+		}),
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.ListAccounts(context.Background(), ListAccountsParams{}); err != nil {
+			t.Fatalf("failed to list accounts: %v", err)
+		}
+	}
+
+	if _, err := client.ListProducts(context.Background(), ListProductsParams{}); err == nil {
+		t.Fatalf("got no error listing products, want one")
+	}
+
+	stats := client.Stats()
+
+	accounts := stats.Endpoints["GET /brokerage/accounts"]
+	if accounts.Requests != 3 || accounts.Errors != 0 {
+		t.Fatalf("got accounts stats %+v, want 3 requests without errors", accounts)
+	}
+
+	if accounts.P50 <= 0 || accounts.P50 > accounts.P99 {
+		t.Fatalf("got accounts percentiles %+v, want 0 < p50 <= p99", accounts)
+	}
+
+	products := stats.Endpoints["GET /brokerage/products"]
+	if products.Requests != 1 || products.Errors != 1 {
+		t.Fatalf("got products stats %+v, want 1 failed request", products)
+	}
+
+	if got := (&Client{}).Stats(); len(got.Endpoints) != 0 {
+		t.Fatalf("got stats %+v without WithStats, want none", got)
+	}
+}
+
+func TestLatencyHistogram(t *testing.T) {
+	t.Parallel()
+
+	histogram := &latencyHistogram{}
+
+	for i := 0; i < 90; i++ {
+		histogram.observe(500*time.Microsecond, false)
+	}
+
+	for i := 0; i < 9; i++ {
+		histogram.observe(3*time.Millisecond, false)
+	}
+
+	histogram.observe(time.Hour, true)
+
+	got := histogram.snapshot()
+	want := EndpointStats{
+		Requests: 100,
+		Errors:   1,
+		P50:      time.Millisecond,
+		P95:      4 * time.Millisecond,
+		P99:      4 * time.Millisecond,
+	}
+
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	slow := &latencyHistogram{}
+	slow.observe(time.Hour, false)
+
+	if got := slow.snapshot(); got.P99 != statsMinLatency<<(statsBuckets-1) {
+		t.Fatalf("got p99 %v, want the bound of the last bucket", got.P99)
+	}
+}
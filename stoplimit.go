@@ -0,0 +1,139 @@
+package coinbase
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/shopspring/decimal"
+)
+
+// percent is the number that a percentage is a fraction of.
+const percent = 100
+
+// NewStopLimit returns a request for a good-'til-cancelled stop-limit order of
+// the given base size that stops out of a position, with the stop and limit
+// prices set as percentages of the product's current price from GetProduct,
+// such as 2 for 2%.
+//
+// A sell order protects a long position: it stops stopPct below the current
+// price, when the price goes down, and is limited to limitOffsetPct below the
+// stop price. A buy order protects a short position the other way around,
+// stopping stopPct above the current price, when the price goes up, and being
+// limited to limitOffsetPct above the stop price. Prices are rounded to the
+// product's quote increment away from the current price, and the size is
+// rounded down to its base increment. ErrInvalidOrderConfig is returned if a
+// percentage is not positive, or if the rounded size or a rounded price is
+// not positive.
+func (client *Client) NewStopLimit(ctx context.Context, productID string, side OrderSide, size string,
+	stopPct, limitOffsetPct float64, opts ...CallOption,
+) (OrderRequest, error) {
+	if err := validatePercentage("stop", stopPct); err != nil {
+		return OrderRequest{}, err
+	}
+
+	if err := validatePercentage("limit offset", limitOffsetPct); err != nil {
+		return OrderRequest{}, err
+	}
+
+	// away moves prices away from the current price, in the direction
+	// that the stop triggers in.
+	var (
+		direction OrderStopDirection
+		away      func(decimal.Decimal, decimal.Decimal) decimal.Decimal
+		round     func(decimal.Decimal) decimal.Decimal
+	)
+
+	switch side {
+	case OrderSideBuy:
+		direction, away, round = StopDirUp, decimal.Decimal.Add, decimal.Decimal.Ceil
+	case OrderSideSell:
+		direction, away, round = StopDirDown, decimal.Decimal.Sub, decimal.Decimal.Floor
+	default:
+		return OrderRequest{}, fmt.Errorf("%w: %q", ErrInvalidOrderSide, side)
+	}
+
+	product, err := client.GetProduct(ctx, productID, opts...)
+	if err != nil {
+		return OrderRequest{}, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	price, err := decimal.NewFromString(product.Price)
+	if err != nil {
+		return OrderRequest{}, fmt.Errorf("failed to parse price %q of %s: %w", product.Price, productID, err)
+	}
+
+	baseSize, err := decimal.NewFromString(size)
+	if err != nil {
+		return OrderRequest{}, fmt.Errorf("failed to parse size %q: %w", size, err)
+	}
+
+	if baseSize, err = roundToIncrement(baseSize, product.BaseIncrement, decimal.Decimal.Floor); err != nil {
+		return OrderRequest{}, err
+	}
+
+	stopPrice := away(price, price.Mul(fraction(stopPct)))
+	if stopPrice, err = roundToIncrement(stopPrice, product.QuoteIncrement, round); err != nil {
+		return OrderRequest{}, err
+	}
+
+	limitPrice := away(stopPrice, stopPrice.Mul(fraction(limitOffsetPct)))
+	if limitPrice, err = roundToIncrement(limitPrice, product.QuoteIncrement, round); err != nil {
+		return OrderRequest{}, err
+	}
+
+	if !baseSize.IsPositive() || !stopPrice.IsPositive() || !limitPrice.IsPositive() {
+		return OrderRequest{}, fmt.Errorf("%w: size %s, stop price %s and limit price %s must be positive",
+			ErrInvalidOrderConfig, baseSize, stopPrice, limitPrice)
+	}
+
+	return NewOrderBuilder(NewClientOrderID()).
+		Product(product).
+		Side(string(side)).
+		Configuration(OrderConfig{
+			StopLimitGTC: &StopLimitGTCConfig{
+				BaseSize:      baseSize.String(),
+				LimitPrice:    limitPrice.String(),
+				StopPrice:     stopPrice.String(),
+				StopDirection: direction,
+			},
+		}).
+		Build()
+}
+
+// validatePercentage returns ErrInvalidOrderConfig unless the named percentage
+// is a positive, finite number.
+func validatePercentage(name string, pct float64) error {
+	if !(pct > 0) || math.IsInf(pct, 1) {
+		return fmt.Errorf("%w: %s percentage %v must be positive", ErrInvalidOrderConfig, name, pct)
+	}
+
+	return nil
+}
+
+// fraction returns the percentage as a fraction, such as 0.02 for 2.
+func fraction(pct float64) decimal.Decimal {
+	return decimal.NewFromFloat(pct).Div(decimal.NewFromInt(percent))
+}
+
+// roundToIncrement rounds the value to a multiple of the increment with the
+// given rounding function. An empty or non-positive increment leaves the value
+// as is.
+func roundToIncrement(value decimal.Decimal, increment string,
+	round func(decimal.Decimal) decimal.Decimal,
+) (decimal.Decimal, error) {
+	if increment == "" {
+		return value, nil
+	}
+
+	step, err := decimal.NewFromString(increment)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to parse increment %q: %w", increment, err)
+	}
+
+	if !step.IsPositive() {
+		return value, nil
+	}
+
+	return round(value.Div(step)).Mul(step), nil
+}
@@ -0,0 +1,166 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// mockStopLimitProduct returns a client whose GetProduct returns BTC-USD at the
+// given price.
+func mockStopLimitProduct(price string) *Client {
+	return &Client{
+		httpClient: &mockClient{
+			response: []byte(`{"product_id": "BTC-USD", "price": "` + price + `", "status": "online",
+				"base_increment": "0.00000001", "quote_increment": "0.01"}`),
+			statusCode: http.StatusOK,
+		},
+	}
+}
+
+func TestNewStopLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		side OrderSide
+		want StopLimitGTCConfig
+	}{
+		{
+			// 29000.55 * 0.98 = 28420.539 and 28420.53 * 0.995 =
+			// 28278.42735, rounded down.
+			name: "sell",
+			side: OrderSideSell,
+			want: StopLimitGTCConfig{
+				BaseSize:      "0.12345678",
+				StopPrice:     "28420.53",
+				LimitPrice:    "28278.42",
+				StopDirection: StopDirDown,
+			},
+		},
+		{
+			// 29000.55 * 1.02 = 29580.561 and 29580.57 * 1.005 =
+			// 29728.47285, rounded up.
+			name: "buy",
+			side: OrderSideBuy,
+			want: StopLimitGTCConfig{
+				BaseSize:      "0.12345678",
+				StopPrice:     "29580.57",
+				LimitPrice:    "29728.48",
+				StopDirection: StopDirUp,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := mockStopLimitProduct("29000.55")
+
+			req, err := client.NewStopLimit(context.Background(), "BTC-USD", test.side, "0.123456789", 2, 0.5)
+			if err != nil {
+				t.Fatalf("failed to build stop-limit order: %v", err)
+			}
+
+			if req.ProductID != "BTC-USD" || req.Side != test.side || req.ClientOrderID == "" {
+				t.Fatalf("got order request %+v", req)
+			}
+
+			want := OrderConfig{StopLimitGTC: &test.want}
+			if !reflect.DeepEqual(req.Configuration, want) {
+				t.Fatalf("got configuration %+v, want %+v", req.Configuration.StopLimitGTC, test.want)
+			}
+		})
+	}
+}
+
+func TestNewStopLimitErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		side           OrderSide
+		size           string
+		stopPct        float64
+		limitOffsetPct float64
+		err            error
+	}{
+		{
+			name:           "zero stop percentage",
+			side:           OrderSideSell,
+			stopPct:        0,
+			limitOffsetPct: 1,
+			err:            ErrInvalidOrderConfig,
+		},
+		{
+			name:           "negative limit offset percentage",
+			side:           OrderSideSell,
+			stopPct:        2,
+			limitOffsetPct: -1,
+			err:            ErrInvalidOrderConfig,
+		},
+		{
+			name:           "NaN stop percentage",
+			side:           OrderSideBuy,
+			stopPct:        math.NaN(),
+			limitOffsetPct: 1,
+			err:            ErrInvalidOrderConfig,
+		},
+		{
+			name:           "infinite limit offset percentage",
+			side:           OrderSideBuy,
+			stopPct:        2,
+			limitOffsetPct: math.Inf(1),
+			err:            ErrInvalidOrderConfig,
+		},
+		{
+			name:           "unknown side",
+			side:           OrderSideUnknown,
+			stopPct:        2,
+			limitOffsetPct: 1,
+			err:            ErrInvalidOrderSide,
+		},
+		{
+			name:           "stop below zero",
+			side:           OrderSideSell,
+			stopPct:        100,
+			limitOffsetPct: 1,
+			err:            ErrInvalidOrderConfig,
+		},
+		{
+			name:           "less than one base increment",
+			side:           OrderSideSell,
+			size:           "0.000000001",
+			stopPct:        2,
+			limitOffsetPct: 1,
+			err:            ErrInvalidOrderConfig,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			size := test.size
+			if size == "" {
+				size = "1"
+			}
+
+			client := mockStopLimitProduct("29000")
+
+			_, err := client.NewStopLimit(context.Background(), "BTC-USD", test.side, size,
+				test.stopPct, test.limitOffsetPct)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+		})
+	}
+}
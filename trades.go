@@ -0,0 +1,182 @@
+package coinbase
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Trade is a trade of a product on the exchange.
+type Trade struct {
+	TradeID   string    `json:"trade_id"`
+	ProductID string    `json:"product_id"`
+	Price     string    `json:"price"`
+	Size      string    `json:"size"`
+	Time      time.Time `json:"time"`
+	Side      OrderSide `json:"side"`
+	Bid       string    `json:"bid"`
+	Ask       string    `json:"ask"`
+}
+
+// MarketTrades represents the latest trades of a product, along with its best
+// bid and ask.
+type MarketTrades struct {
+	Data    []Trade `json:"trades"`
+	BestBid string  `json:"best_bid"`
+	BestAsk string  `json:"best_ask"`
+}
+
+// MarketTradesParams are the query parameters used to select the trades
+// returned by GetMarketTrades. Zero times are omitted from the request.
+type MarketTradesParams struct {
+	Limit int32
+	Start time.Time
+	End   time.Time
+
+	// SortDirection orders the trades by time. It is not sent to the API,
+	// the trades are sorted client-side.
+	SortDirection SortDirection
+}
+
+// query returns the URL query values for the parameters.
+func (params MarketTradesParams) query() url.Values {
+	formatBase := 10
+
+	query := url.Values{}
+	query.Set("limit", strconv.FormatInt(int64(params.Limit), formatBase))
+
+	if !params.Start.IsZero() {
+		query.Set("start", strconv.FormatInt(params.Start.Unix(), formatBase))
+	}
+
+	if !params.End.IsZero() {
+		query.Set("end", strconv.FormatInt(params.End.Unix(), formatBase))
+	}
+
+	return query
+}
+
+// GetMarketTrades returns the latest trades of a product, up to the limit and
+// between the start and end times if they are set. The trades are newest first
+// unless the parameters set a sort direction. A limit greater than 1000 is
+// handled according to the client's LimitPolicy.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_getmarkettrades
+func (client *Client) GetMarketTrades(ctx context.Context, productID string, params MarketTradesParams,
+	opts ...CallOption,
+) (*MarketTrades, error) {
+	path := []string{"brokerage", "products", productID, "ticker"}
+
+	if err := params.SortDirection.validate(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if params.Limit, err = client.checkLimit(params.Limit, maxMarketTradesLimit); err != nil {
+		return nil, err
+	}
+
+	trades := &MarketTrades{}
+	if err := client.do(ctx, http.MethodGet, path, params.query(), nil, trades, opts); err != nil {
+		return nil, err
+	}
+
+	sortByDirection(trades.Data, params.SortDirection, func(trade Trade) int64 {
+		return trade.Time.UnixNano()
+	})
+
+	return trades, nil
+}
+
+// MarketTradePager iterates back through the trades of a product returned by
+// GetMarketTrades, newest first, lazily requesting the trades before the oldest
+// one seen so far once the current page has been consumed.
+type MarketTradePager struct {
+	ctx       context.Context //nolint:containedctx
+	client    *Client
+	productID string
+	params    MarketTradesParams
+	opts      []CallOption
+
+	page  []Trade
+	trade Trade
+	seen  map[string]bool
+	done  bool
+	err   error
+}
+
+// MarketTradesPager returns a pager over the trades of a product, starting at
+// the end time, or the latest trade if it is zero, and going back to the start
+// time, or as far as the API allows if it is zero. Each request asks for up to
+// the limit of trades. Since the end time of each request is that of the oldest
+// trade of the previous page, trades at the page boundaries are de-duplicated
+// by trade ID. The pager always lists trades newest first, so the sort
+// direction of the parameters is ignored.
+func (client *Client) MarketTradesPager(ctx context.Context, productID string, params MarketTradesParams,
+	opts ...CallOption,
+) *MarketTradePager {
+	params.SortDirection = ""
+
+	return &MarketTradePager{
+		ctx:       ctx,
+		client:    client,
+		productID: productID,
+		params:    params,
+		opts:      opts,
+		seen:      make(map[string]bool),
+	}
+}
+
+// Next advances the pager to the next trade, which is then available through
+// the Trade method. It returns false when there are no more trades or when an
+// error occurred, which is available through the Err method.
+func (pager *MarketTradePager) Next() bool {
+	for len(pager.page) == 0 {
+		if pager.done || pager.err != nil {
+			return false
+		}
+
+		trades, err := pager.client.GetMarketTrades(pager.ctx, pager.productID, pager.params, pager.opts...)
+		if err != nil {
+			pager.err = err
+
+			return false
+		}
+
+		oldest := pager.params.End
+
+		for _, trade := range trades.Data {
+			if trade.Time.Before(oldest) || oldest.IsZero() {
+				oldest = trade.Time
+			}
+
+			if pager.seen[trade.TradeID] {
+				continue
+			}
+
+			pager.seen[trade.TradeID] = true
+			pager.page = append(pager.page, trade)
+		}
+
+		// Stop once a page has nothing new, which also covers a page with
+		// more trades at the same time as the limit allows.
+		pager.done = len(pager.page) == 0 || !oldest.After(pager.params.Start)
+		pager.params.End = oldest
+	}
+
+	pager.trade, pager.page = pager.page[0], pager.page[1:]
+
+	return true
+}
+
+// Trade returns the current trade.
+func (pager *MarketTradePager) Trade() Trade {
+	return pager.trade
+}
+
+// Err returns the error, if any, that stopped the pager.
+func (pager *MarketTradePager) Err() error {
+	return pager.err
+}
@@ -0,0 +1,201 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetMarketTrades(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response []byte
+		want     *MarketTrades
+		err      error
+	}{
+		{
+			name: "nil",
+			err:  io.EOF, // end of file, nothing in response
+		},
+		{
+			name:     "empty",
+			response: []byte(`{}`),
+			want:     &MarketTrades{},
+		},
+		{
+			name: "single",
+			response: []byte(`
+{
+  "trades": [
+    {
+      "trade_id": "34b080bf",
+      "product_id": "BTC-USD",
+      "price": "29000.01",
+      "size": "0.001",
+      "time": "2023-05-31T09:59:59Z",
+      "side": "BUY",
+      "bid": "29000",
+      "ask": "29000.02"
+    }
+  ],
+  "best_bid": "29000",
+  "best_ask": "29000.02"
+}`),
+			want: &MarketTrades{
+				Data: []Trade{
+					{
+						TradeID:   "34b080bf",
+						ProductID: "BTC-USD",
+						Price:     "29000.01",
+						Size:      "0.001",
+						Time:      time.Date(2023, 5, 31, 9, 59, 59, 0, time.UTC),
+						Side:      OrderSideBuy,
+						Bid:       "29000",
+						Ask:       "29000.02",
+					},
+				},
+				BestBid: "29000",
+				BestAsk: "29000.02",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{
+				httpClient: &mockClient{response: test.response, statusCode: http.StatusOK},
+			}
+
+			got, err := client.GetMarketTrades(context.Background(), "BTC-USD", MarketTradesParams{Limit: 1})
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGetMarketTradesLimit(t *testing.T) {
+	t.Parallel()
+
+	client := &Client{}
+
+	_, err := client.GetMarketTrades(context.Background(), "BTC-USD", MarketTradesParams{Limit: 1001})
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("got %v, want %v", err, ErrLimitExceeded)
+	}
+}
+
+func TestGetMarketTradesSortDirection(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		direction SortDirection
+		want      []string
+		err       error
+	}{
+		{name: "unset", want: []string{"b", "c", "a"}},
+		{name: "ascending", direction: SortAscending, want: []string{"a", "b", "c"}},
+		{name: "descending", direction: SortDescending, want: []string{"c", "b", "a"}},
+		{name: "invalid", direction: "asc", err: ErrInvalidSortDirection},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{httpClient: &mockClient{
+				response: []byte(`{"trades": [{"trade_id": "b", "time": "2023-05-31T10:00:00Z"},
+					{"trade_id": "c", "time": "2023-05-31T10:00:00.5Z"},
+					{"trade_id": "a", "time": "2023-05-31T09:59:59Z"}]}`),
+				statusCode: http.StatusOK,
+			}}
+
+			params := MarketTradesParams{Limit: 3, SortDirection: test.direction}
+
+			trades, err := client.GetMarketTrades(context.Background(), "BTC-USD", params)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if test.err != nil {
+				return
+			}
+
+			var ids []string
+			for _, trade := range trades.Data {
+				ids = append(ids, trade.TradeID)
+			}
+
+			if !reflect.DeepEqual(ids, test.want) {
+				t.Fatalf("got trade IDs %v, want %v", ids, test.want)
+			}
+		})
+	}
+}
+
+func TestMarketTradesPager(t *testing.T) {
+	t.Parallel()
+
+	// The second page ends at the time of the oldest trade of the first
+	// page, so trade "b" is returned twice.
+	pages := map[string]string{
+		"": `{"trades": [
+			{"trade_id": "a", "time": "2023-05-31T10:00:02Z"},
+			{"trade_id": "b", "time": "2023-05-31T10:00:01Z"}
+		]}`,
+		"1685527201": `{"trades": [
+			{"trade_id": "b", "time": "2023-05-31T10:00:01Z"},
+			{"trade_id": "c", "time": "2023-05-31T10:00:00Z"}
+		]}`,
+		"1685527200": `{"trades": [
+			{"trade_id": "c", "time": "2023-05-31T10:00:00Z"}
+		]}`,
+	}
+
+	var limits []string
+
+	client := &Client{
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			query := req.URL.Query()
+			limits = append(limits, query.Get("limit"))
+
+			return newMockResponse(http.StatusOK, pages[query.Get("end")]), nil
+		}),
+	}
+
+	pager := client.MarketTradesPager(context.Background(), "BTC-USD", MarketTradesParams{Limit: 2})
+
+	var got []string
+	for pager.Next() {
+		got = append(got, pager.Trade().TradeID)
+	}
+
+	if err := pager.Err(); err != nil {
+		t.Fatalf("failed to page through trades: %v", err)
+	}
+
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got trades %v, want %v", got, want)
+	}
+
+	if want := []string{"2", "2", "2"}; !reflect.DeepEqual(limits, want) {
+		t.Fatalf("got limits %v, want %v", limits, want)
+	}
+}
@@ -0,0 +1,182 @@
+package coinbase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// defaultFeeTierTTL is how long the fee tier returned by Client.FeeTier is
+// cached for by default. The tier changes at most daily.
+const defaultFeeTierTTL = time.Hour
+
+// FeeTier represents the fee tier the user is in, which depends on their
+// trading volume.
+type FeeTier struct {
+	PricingTier  string `json:"pricing_tier"`
+	USDFrom      string `json:"usd_from"`
+	USDTo        string `json:"usd_to"`
+	TakerFeeRate string `json:"taker_fee_rate"`
+	MakerFeeRate string `json:"maker_fee_rate"`
+}
+
+// EstimateFee returns the fee charged for a trade of the given notional value,
+// at the maker fee rate if maker is true and the taker fee rate otherwise.
+func (tier FeeTier) EstimateFee(notional decimal.Decimal, maker bool) (decimal.Decimal, error) {
+	raw := tier.TakerFeeRate
+	if maker {
+		raw = tier.MakerFeeRate
+	}
+
+	rate, err := decimal.NewFromString(raw)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to parse fee rate %q: %w", raw, err)
+	}
+
+	return notional.Abs().Mul(rate), nil
+}
+
+// MarginRate represents the user's margin rate.
+type MarginRate struct {
+	Value string `json:"value"`
+}
+
+// GoodsAndServicesTax represents the goods and services tax applied to the
+// user's fees.
+type GoodsAndServicesTax struct {
+	Rate string `json:"rate"`
+
+	// Type is either "INCLUSIVE" or "EXCLUSIVE", depending on whether the
+	// tax is included in the fees.
+	Type string `json:"type"`
+}
+
+// TransactionsSummary represents a summary of the user's trading volume and
+// fees.
+type TransactionsSummary struct {
+	TotalVolume         float64              `json:"total_volume"`
+	TotalFees           float64              `json:"total_fees"`
+	FeeTier             FeeTier              `json:"fee_tier"`
+	MarginRate          *MarginRate          `json:"margin_rate"`
+	GoodsAndServicesTax *GoodsAndServicesTax `json:"goods_and_services_tax"`
+
+	// AdvancedTradeOnlyVolume and AdvancedTradeOnlyFees are the volume
+	// and fees of Advanced Trade alone, while CoinbaseProVolume and
+	// CoinbaseProFees are those of the legacy Coinbase Pro exchange.
+	AdvancedTradeOnlyVolume float64 `json:"advanced_trade_only_volume"`
+	AdvancedTradeOnlyFees   float64 `json:"advanced_trade_only_fees"`
+	CoinbaseProVolume       float64 `json:"coinbase_pro_volume"`
+	CoinbaseProFees         float64 `json:"coinbase_pro_fees"`
+}
+
+// TransactionsSummaryParams are the query parameters used to filter the
+// transactions summarized by GetTransactionsSummary. Zero values are omitted
+// from the request.
+type TransactionsSummaryParams struct {
+	StartDate          time.Time
+	EndDate            time.Time
+	UserNativeCurrency string
+	ProductType        string
+}
+
+// query returns the URL query values for the parameters.
+func (params TransactionsSummaryParams) query() url.Values {
+	query := url.Values{}
+
+	if !params.StartDate.IsZero() {
+		query.Set("start_date", params.StartDate.UTC().Format(time.RFC3339Nano))
+	}
+
+	if !params.EndDate.IsZero() {
+		query.Set("end_date", params.EndDate.UTC().Format(time.RFC3339Nano))
+	}
+
+	if params.UserNativeCurrency != "" {
+		query.Set("user_native_currency", params.UserNativeCurrency)
+	}
+
+	if params.ProductType != "" {
+		query.Set("product_type", params.ProductType)
+	}
+
+	return query
+}
+
+// GetTransactionsSummary returns a summary of the user's trading volume and
+// fees, including their current fee tier.
+//
+// https://docs.cloud.coinbase.com/advanced-trade-api/reference/retailbrokerageapi_gettransactionsummary
+func (client *Client) GetTransactionsSummary(ctx context.Context, params TransactionsSummaryParams,
+	opts ...CallOption,
+) (*TransactionsSummary, error) {
+	path := []string{"brokerage", "transaction_summary"}
+
+	summary := &TransactionsSummary{}
+	if err := client.do(ctx, http.MethodGet, path, params.query(), nil, summary, opts); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// WithFeeTierTTL sets how long the fee tier returned by Client.FeeTier is
+// cached for. It defaults to an hour.
+func WithFeeTierTTL(ttl time.Duration) ClientOption {
+	return func(client *Client) {
+		client.feeTierTTL = ttl
+	}
+}
+
+// feeTierCache holds the fee tier last fetched by a client.
+type feeTierCache struct {
+	mu      sync.Mutex
+	tier    *FeeTier
+	fetched time.Time
+}
+
+// FeeTier returns the user's fee tier from GetTransactionsSummary. The tier is
+// cached, and only fetched again once it is older than the client's fee tier
+// TTL, see WithFeeTierTTL.
+func (client *Client) FeeTier(ctx context.Context, opts ...CallOption) (*FeeTier, error) {
+	ttl := client.feeTierTTL
+	if ttl <= 0 {
+		ttl = defaultFeeTierTTL
+	}
+
+	client.feeTier.mu.Lock()
+	tier, fetched := client.feeTier.tier, client.feeTier.fetched
+	client.feeTier.mu.Unlock()
+
+	if tier != nil && time.Since(fetched) < ttl {
+		cached := *tier
+
+		return &cached, nil
+	}
+
+	return client.RefreshFeeTier(ctx, opts...)
+}
+
+// RefreshFeeTier fetches the user's fee tier, replacing the tier cached by
+// FeeTier.
+func (client *Client) RefreshFeeTier(ctx context.Context, opts ...CallOption) (*FeeTier, error) {
+	summary, err := client.GetTransactionsSummary(ctx, TransactionsSummaryParams{}, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fee tier: %w", err)
+	}
+
+	tier := summary.FeeTier
+
+	client.feeTier.mu.Lock()
+	client.feeTier.tier = &tier
+	client.feeTier.fetched = time.Now()
+	client.feeTier.mu.Unlock()
+
+	refreshed := tier
+
+	return &refreshed, nil
+}
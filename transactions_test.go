@@ -0,0 +1,173 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestGetTransactionsSummary(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		response []byte
+		want     *TransactionsSummary
+		err      error
+	}{
+		{
+			name: "nil",
+			err:  io.EOF, // end of file, nothing in response
+		},
+		{
+			name:     "empty",
+			response: []byte(`{}`),
+			want:     &TransactionsSummary{},
+		},
+		{
+			name: "full",
+			response: []byte(`
+{
+  "total_volume": 1000,
+  "total_fees": 25,
+  "fee_tier": {
+    "pricing_tier": "<$10k",
+    "usd_from": "0",
+    "usd_to": "10,000",
+    "taker_fee_rate": "0.0010",
+    "maker_fee_rate": "0.0020"
+  },
+  "margin_rate": {
+    "value": "0.5"
+  },
+  "goods_and_services_tax": {
+    "rate": "0.18",
+    "type": "INCLUSIVE"
+  },
+  "advanced_trade_only_volume": 800,
+  "advanced_trade_only_fees": 20,
+  "coinbase_pro_volume": 200,
+  "coinbase_pro_fees": 5
+}`),
+			want: &TransactionsSummary{
+				TotalVolume: 1000,
+				TotalFees:   25,
+				FeeTier: FeeTier{
+					PricingTier:  "<$10k",
+					USDFrom:      "0",
+					USDTo:        "10,000",
+					TakerFeeRate: "0.0010",
+					MakerFeeRate: "0.0020",
+				},
+				MarginRate:              &MarginRate{Value: "0.5"},
+				GoodsAndServicesTax:     &GoodsAndServicesTax{Rate: "0.18", Type: "INCLUSIVE"},
+				AdvancedTradeOnlyVolume: 800,
+				AdvancedTradeOnlyFees:   20,
+				CoinbaseProVolume:       200,
+				CoinbaseProFees:         5,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &Client{
+				httpClient: &mockClient{
+					response:   test.response,
+					statusCode: http.StatusOK,
+				},
+			}
+
+			got, err := client.GetTransactionsSummary(context.Background(), TransactionsSummaryParams{})
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFeeTier(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+
+	client := &Client{
+		feeTierTTL: time.Hour,
+		httpClient: mockDoFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&requests, 1)
+
+			return newMockResponse(http.StatusOK, `{"fee_tier": {"pricing_tier": "Advanced 1", `+
+				`"taker_fee_rate": "0.006", "maker_fee_rate": "0.004"}}`), nil
+		}),
+	}
+
+	want := &FeeTier{PricingTier: "Advanced 1", TakerFeeRate: "0.006", MakerFeeRate: "0.004"}
+
+	for i := 0; i < 3; i++ {
+		got, err := client.FeeTier(context.Background())
+		if err != nil {
+			t.Fatalf("failed to get fee tier: %v", err)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %+v, want %+v", got, want)
+		}
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Fatalf("got %d requests within the TTL, want 1", got)
+	}
+
+	if _, err := client.RefreshFeeTier(context.Background()); err != nil {
+		t.Fatalf("failed to refresh fee tier: %v", err)
+	}
+
+	client.feeTier.mu.Lock()
+	client.feeTier.fetched = time.Now().Add(-2 * time.Hour)
+	client.feeTier.mu.Unlock()
+
+	if _, err := client.FeeTier(context.Background()); err != nil {
+		t.Fatalf("failed to get fee tier: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Fatalf("got %d requests after refreshing and expiring, want 3", got)
+	}
+}
+
+func TestFeeTierEstimateFee(t *testing.T) {
+	t.Parallel()
+
+	tier := FeeTier{TakerFeeRate: "0.006", MakerFeeRate: "0.004"}
+	notional := decimal.RequireFromString("-1000")
+
+	for maker, want := range map[bool]string{true: "4", false: "6"} {
+		got, err := tier.EstimateFee(notional, maker)
+		if err != nil {
+			t.Fatalf("failed to estimate fee: %v", err)
+		}
+
+		if !got.Equal(decimal.RequireFromString(want)) {
+			t.Fatalf("maker %t: got %s, want %s", maker, got, want)
+		}
+	}
+
+	if _, err := (FeeTier{}).EstimateFee(notional, false); err == nil {
+		t.Fatal("got no error for an empty fee rate")
+	}
+}
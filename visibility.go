@@ -0,0 +1,104 @@
+package coinbase
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultOrderVisibilityWindow is how long after an order is created
+	// GetOrder waits for it to become visible, unless the client sets
+	// another window.
+	defaultOrderVisibilityWindow = 5 * time.Second
+
+	// orderVisibilityRetryWait and maxOrderVisibilityRetryWait bound the
+	// exponential backoff between lookups of an order that is not visible
+	// yet.
+	orderVisibilityRetryWait    = 100 * time.Millisecond
+	maxOrderVisibilityRetryWait = time.Second
+)
+
+// WithOrderVisibilityWindow sets how long after creating an order the client
+// keeps looking it up with GetOrder while Coinbase responds that it is not
+// found, overriding the default of five seconds. Orders are not always visible
+// as soon as they have been created.
+func WithOrderVisibilityWindow(window time.Duration) ClientOption {
+	return func(client *Client) {
+		client.orderVisibilityWindow = window
+	}
+}
+
+// createdOrders holds the times at which a client created its recent orders.
+type createdOrders struct {
+	mu      sync.Mutex
+	created map[string]time.Time
+}
+
+// record remembers that the order was created at the given time, forgetting
+// the orders that were created longer than the window before it.
+func (orders *createdOrders) record(orderID string, created time.Time, window time.Duration) {
+	orders.mu.Lock()
+	defer orders.mu.Unlock()
+
+	if orders.created == nil {
+		orders.created = make(map[string]time.Time)
+	}
+
+	for id, at := range orders.created {
+		if created.Sub(at) > window {
+			delete(orders.created, id)
+		}
+	}
+
+	orders.created[orderID] = created
+}
+
+// createdAt returns the time at which the order was created and whether it is
+// one of the recent orders.
+func (orders *createdOrders) createdAt(orderID string) (time.Time, bool) {
+	orders.mu.Lock()
+	defer orders.mu.Unlock()
+
+	created, ok := orders.created[orderID]
+
+	return created, ok
+}
+
+// visibilityWindow returns how long after an order is created GetOrder waits
+// for it to become visible.
+func (client *Client) visibilityWindow() time.Duration {
+	if client.orderVisibilityWindow <= 0 {
+		return defaultOrderVisibilityWindow
+	}
+
+	return client.orderVisibilityWindow
+}
+
+// isNotFound reports whether the error is a status error with a 404 status
+// code.
+func isNotFound(err error) bool {
+	var statusErr *StatusError
+
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
+
+// orderNotFoundError is returned by GetOrder for an order that Coinbase does
+// not find. It matches ErrOrderNotFound and wraps the status error.
+type orderNotFoundError struct {
+	orderID string
+	err     error
+}
+
+func (err *orderNotFoundError) Error() string {
+	return ErrOrderNotFound.Error() + ": " + err.orderID + ": " + err.err.Error()
+}
+
+func (err *orderNotFoundError) Is(target error) bool {
+	return target == ErrOrderNotFound
+}
+
+func (err *orderNotFoundError) Unwrap() error {
+	return err.err
+}
@@ -0,0 +1,133 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// mockOrderVisibility returns a mock HTTP client that creates the order
+// "order-1" and then responds to the first notFound lookups of any order with
+// a 404 status code. Each lookup is counted.
+func mockOrderVisibility(t *testing.T, notFound int32, lookups *int32) mockDoFunc {
+	t.Helper()
+
+	return func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodPost {
+			return newMockResponse(http.StatusOK, `{"success": true, "order_id": "order-1"}`), nil
+		}
+
+		if atomic.AddInt32(lookups, 1) <= notFound {
+			return newMockResponse(http.StatusNotFound, `{"error": "NOT_FOUND"}`), nil
+		}
+
+		return newMockResponse(http.StatusOK, `{"order": {"order_id": "order-1", "status": "OPEN"}}`), nil
+	}
+}
+
+// createVisibilityOrder creates the order "order-1" with the client.
+func createVisibilityOrder(t *testing.T, client *Client) {
+	t.Helper()
+
+	orderReq := OrderRequest{
+		ClientOrderID: "0000-00000-000000",
+		ProductID:     "BTC-USD",
+		Side:          OrderSideBuy,
+		Configuration: OrderConfig{MarketIOC: &MarketIOCConfig{QuoteSize: "10.00"}},
+	}
+
+	if _, err := client.CreateOrder(context.Background(), orderReq); err != nil {
+		t.Fatalf("failed to create order: %v", err)
+	}
+}
+
+func TestGetOrderAfterCreate(t *testing.T) {
+	t.Parallel()
+
+	var lookups int32
+
+	client := &Client{httpClient: mockOrderVisibility(t, 2, &lookups)}
+	createVisibilityOrder(t, client)
+
+	order, err := client.GetOrder(context.Background(), "order-1")
+	if err != nil {
+		t.Fatalf("failed to get order: %v", err)
+	}
+
+	if order.OrderID != "order-1" {
+		t.Fatalf("got order %q, want %q", order.OrderID, "order-1")
+	}
+
+	if got := atomic.LoadInt32(&lookups); got != 3 {
+		t.Fatalf("got %d lookups, want 3", got)
+	}
+}
+
+func TestGetOrderNotFound(t *testing.T) {
+	t.Parallel()
+
+	var lookups int32
+
+	client := &Client{httpClient: mockOrderVisibility(t, 1, &lookups)}
+
+	_, err := client.GetOrder(context.Background(), "order-2")
+	if !errors.Is(err, ErrOrderNotFound) {
+		t.Fatalf("got %v, want %v", err, ErrOrderNotFound)
+	}
+
+	if !errors.Is(err, ErrStatusNotOK) {
+		t.Fatalf("got %v, want %v", err, ErrStatusNotOK)
+	}
+
+	if got := atomic.LoadInt32(&lookups); got != 1 {
+		t.Fatalf("got %d lookups, want 1", got)
+	}
+}
+
+func TestGetOrderAfterCreateWindow(t *testing.T) {
+	t.Parallel()
+
+	var lookups int32
+
+	window := 150 * time.Millisecond
+	client := &Client{
+		httpClient:            mockOrderVisibility(t, 100, &lookups),
+		orderVisibilityWindow: window,
+	}
+	createVisibilityOrder(t, client)
+
+	start := time.Now()
+
+	_, err := client.GetOrder(context.Background(), "order-1")
+	if !errors.Is(err, ErrOrderNotFound) {
+		t.Fatalf("got %v, want %v", err, ErrOrderNotFound)
+	}
+
+	if elapsed := time.Since(start); elapsed > window+time.Second {
+		t.Fatalf("got not found after %v, want about %v", elapsed, window)
+	}
+
+	if got := atomic.LoadInt32(&lookups); got < 2 {
+		t.Fatalf("got %d lookups, want at least 2", got)
+	}
+}
+
+func TestGetOrderAfterCreateCancelled(t *testing.T) {
+	t.Parallel()
+
+	var lookups int32
+
+	client := &Client{httpClient: mockOrderVisibility(t, 100, &lookups)}
+	createVisibilityOrder(t, client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetOrder(ctx, "order-1")
+	if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrOrderNotFound) {
+		t.Fatalf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
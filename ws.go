@@ -0,0 +1,834 @@
+package coinbase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const wsURL = "wss://advanced-trade-ws.coinbase.com"
+
+const (
+	// defaultWSWriteTimeout is the default deadline for writing a frame.
+	defaultWSWriteTimeout = 10 * time.Second
+
+	// wsMinReconnectWait and wsMaxReconnectWait bound the exponential
+	// backoff between reconnection attempts.
+	wsMinReconnectWait = 100 * time.Millisecond
+	wsMaxReconnectWait = 10 * time.Second
+
+	// wsMessageBuffer is the number of messages buffered for the reader.
+	wsMessageBuffer = 64
+
+	// wsErrorBuffer is the number of message errors buffered for the
+	// reader, beyond which further errors are dropped.
+	wsErrorBuffer = 16
+
+	// wsPongWaitFactor is the number of keep-alive intervals to wait for a
+	// frame before the connection is considered dead.
+	wsPongWaitFactor = 2
+
+	// wsCloseTimeout is how long Close waits for the server to acknowledge
+	// the close frame before closing the connection.
+	wsCloseTimeout = 5 * time.Second
+)
+
+// ErrWSNotConnected is returned when subscribing with a WebSocket client that
+// has not been connected.
+var ErrWSNotConnected = errors.New("websocket not connected")
+
+// ErrWSClosed is returned when connecting or subscribing with a WebSocket
+// client that has been closed, or when subscribing with one that has stopped
+// reading from the feed because the context it was connected with is done.
+var ErrWSClosed = errors.New("websocket closed")
+
+// ErrWSAlreadyConnected is returned when connecting a WebSocket client that has
+// already been connected. A client is connected at most once, even after the
+// context it was connected with is cancelled.
+var ErrWSAlreadyConnected = errors.New("websocket already connected")
+
+// ErrWSMalformedMessage is reported on the Errors channel of a WebSocket client
+// when a frame from the feed cannot be decoded.
+var ErrWSMalformedMessage = errors.New("malformed websocket message")
+
+// ErrWSUnexpectedMessage is reported on the Errors channel of a WebSocket
+// client when the feed sends a frame that is not a channel message, such as an
+// error frame.
+var ErrWSUnexpectedMessage = errors.New("unexpected websocket message")
+
+// ErrWSSequenceGap is reported on the Errors channel of a WebSocket client when
+// a message does not follow the previous message received on the connection,
+// meaning that messages were missed or reordered.
+var ErrWSSequenceGap = errors.New("websocket sequence gap")
+
+// wsMalformedMessageError is reported when a frame cannot be decoded. It is
+// ErrWSMalformedMessage and unwraps to the decoding error.
+type wsMalformedMessageError struct {
+	err error
+}
+
+// Error implements the error interface.
+func (err *wsMalformedMessageError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrWSMalformedMessage, err.err)
+}
+
+// Is reports whether the target is ErrWSMalformedMessage.
+func (err *wsMalformedMessageError) Is(target error) bool {
+	return target == ErrWSMalformedMessage
+}
+
+// Unwrap returns the decoding error.
+func (err *wsMalformedMessageError) Unwrap() error {
+	return err.err
+}
+
+// WSChannel represents a Coinbase WebSocket channel.
+type WSChannel string
+
+const (
+	// WSChannelHeartbeats sends a heartbeat every second.
+	WSChannelHeartbeats WSChannel = "heartbeats"
+
+	// WSChannelStatus sends the status of products.
+	WSChannelStatus WSChannel = "status"
+
+	// WSChannelTicker sends price updates for products as trades happen.
+	WSChannelTicker WSChannel = "ticker"
+
+	// WSChannelTickerBatch sends price updates for products in batches.
+	WSChannelTickerBatch WSChannel = "ticker_batch"
+
+	// WSChannelLevel2 sends updates to the order book of products.
+	WSChannelLevel2 WSChannel = "level2"
+
+	// WSChannelUser sends updates to the authenticated user's orders.
+	WSChannelUser WSChannel = "user"
+
+	// WSChannelMarketTrades sends the trades of products.
+	WSChannelMarketTrades WSChannel = "market_trades"
+
+	// WSChannelCandles sends five minute candle updates for products.
+	WSChannelCandles WSChannel = "candles"
+)
+
+// WSMessage is a message received from the Coinbase WebSocket feed. The
+// events are left undecoded since their shape depends on the channel.
+type WSMessage struct {
+	Channel     string          `json:"channel"`
+	ClientID    string          `json:"client_id"`
+	Timestamp   time.Time       `json:"timestamp"`
+	SequenceNum int64           `json:"sequence_num"`
+	Events      json.RawMessage `json:"events"`
+}
+
+// wsFrame is the envelope of a frame that is not a channel message, such as
+// the error frames sent by the feed.
+type wsFrame struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// wsSubscriptionsChannel is the channel on which the feed confirms the
+// client's subscriptions.
+const wsSubscriptionsChannel = "subscriptions"
+
+// wsSubscriptionsEvent is an event on the subscriptions channel, listing the
+// products subscribed to for each channel.
+type wsSubscriptionsEvent struct {
+	Subscriptions map[WSChannel][]string `json:"subscriptions"`
+}
+
+// wsConfirmation waits for the feed to confirm a subscription.
+type wsConfirmation struct {
+	channel    WSChannel
+	productIDs []string
+	confirmed  chan struct{}
+}
+
+// confirmedBy reports whether the subscriptions listed by an event include
+// every product of the awaited subscription.
+func (confirmation *wsConfirmation) confirmedBy(event wsSubscriptionsEvent) bool {
+	subscribed, ok := event.Subscriptions[confirmation.channel]
+	if !ok {
+		return false
+	}
+
+	products := make(map[string]bool, len(subscribed))
+	for _, productID := range subscribed {
+		products[productID] = true
+	}
+
+	for _, productID := range confirmation.productIDs {
+		if !products[productID] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// wsSubscribeMessage is the signed message sent to subscribe to a channel.
+type wsSubscribeMessage struct {
+	Type       string    `json:"type"`
+	ProductIDs []string  `json:"product_ids"`
+	Channel    WSChannel `json:"channel"`
+	Signature  string    `json:"signature"`
+	APIKey     string    `json:"api_key"`
+	Timestamp  string    `json:"timestamp"`
+}
+
+// WSOption configures a WSClient.
+type WSOption func(*WSClient)
+
+// WithWSKeepAlive sends a ping to the server every interval. If no frame,
+// including the server's pong, is received within two intervals the connection
+// is considered dead and is re-established.
+func WithWSKeepAlive(interval time.Duration) WSOption {
+	return func(ws *WSClient) {
+		ws.keepAlive = interval
+	}
+}
+
+// WithWSReadTimeout sets how long to wait for a frame before the connection is
+// considered dead and is re-established, overriding the wait derived from the
+// keep-alive interval.
+func WithWSReadTimeout(timeout time.Duration) WSOption {
+	return func(ws *WSClient) {
+		ws.readTimeout = timeout
+	}
+}
+
+// WithWSWriteTimeout sets the deadline for writing each frame to the server.
+func WithWSWriteTimeout(timeout time.Duration) WSOption {
+	return func(ws *WSClient) {
+		ws.writeTimeout = timeout
+	}
+}
+
+// WSClient is a client for the Coinbase Advanced Trade WebSocket feed. If the
+// connection drops, the client reconnects and re-sends its subscriptions.
+type WSClient struct {
+	key    string
+	secret string
+	url    string
+
+	keepAlive    time.Duration
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+
+	messages chan WSMessage
+	errors   chan error
+
+	// done is closed once the client has stopped reading from the feed.
+	done chan struct{}
+
+	// mu guards the fields below and serializes writes to the connection.
+	mu            sync.Mutex
+	started       bool
+	closed        bool
+	cancel        context.CancelFunc
+	conn          *websocket.Conn
+	subscriptions map[WSChannel]map[string]bool
+	confirmations []*wsConfirmation
+}
+
+// NewWSClient creates a new Coinbase WebSocket client with the provided API key
+// and secret, which are used to sign each subscription.
+func NewWSClient(key, secret string, opts ...WSOption) (*WSClient, error) {
+	if key == "" || secret == "" {
+		return nil, fmt.Errorf("failed to create websocket client: %w", errInvalidRoundTripArgs)
+	}
+
+	ws := &WSClient{
+		key:           key,
+		secret:        secret,
+		url:           wsURL,
+		writeTimeout:  defaultWSWriteTimeout,
+		messages:      make(chan WSMessage, wsMessageBuffer),
+		errors:        make(chan error, wsErrorBuffer),
+		done:          make(chan struct{}),
+		subscriptions: make(map[WSChannel]map[string]bool),
+	}
+
+	for _, opt := range opts {
+		opt(ws)
+	}
+
+	return ws, nil
+}
+
+// Connect dials the Coinbase WebSocket feed and starts reading messages from
+// it. Messages are read until the context is cancelled or the client is
+// closed, at which point the Messages and Errors channels are closed.
+// ErrWSAlreadyConnected is returned if the client has already been connected.
+func (ws *WSClient) Connect(ctx context.Context) error {
+	ws.mu.Lock()
+	err := ws.connectable()
+	ws.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, ws.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	ws.mu.Lock()
+
+	if err := ws.connectable(); err != nil {
+		ws.mu.Unlock()
+		cancel()
+
+		_ = conn.Close()
+
+		return err
+	}
+
+	ws.started = true
+	ws.cancel = cancel
+	ws.conn = conn
+	ws.mu.Unlock()
+
+	go ws.run(ctx, conn)
+
+	return nil
+}
+
+// connectable returns ErrWSClosed if the client has been closed and
+// ErrWSAlreadyConnected if it has been connected. The caller must hold the
+// client's lock.
+func (ws *WSClient) connectable() error {
+	if ws.closed {
+		return ErrWSClosed
+	}
+
+	if ws.started {
+		return ErrWSAlreadyConnected
+	}
+
+	return nil
+}
+
+// stopped reports whether the client has been closed or has stopped reading
+// from the feed. The caller must hold the client's lock.
+func (ws *WSClient) stopped() bool {
+	if ws.closed {
+		return true
+	}
+
+	select {
+	case <-ws.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// isClosed reports whether the client has been closed.
+func (ws *WSClient) isClosed() bool {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	return ws.closed
+}
+
+// Messages returns the channel on which messages from the feed are delivered.
+func (ws *WSClient) Messages() <-chan WSMessage {
+	return ws.messages
+}
+
+// Errors returns the channel on which problems with the messages from the feed
+// are reported: frames that cannot be decoded (ErrWSMalformedMessage), frames
+// that are not channel messages (ErrWSUnexpectedMessage) and messages that do
+// not follow the previous one (ErrWSSequenceGap). None of these stop the
+// client, which keeps delivering the other messages. Errors are dropped while
+// the channel's buffer is full, so a client that does not read them is not
+// slowed down. The channel is closed along with the Messages channel.
+func (ws *WSClient) Errors() <-chan error {
+	return ws.errors
+}
+
+// report delivers an error on the Errors channel, dropping it if the buffer is
+// full.
+func (ws *WSClient) report(err error) {
+	select {
+	case ws.errors <- err:
+	default:
+	}
+}
+
+// Subscribe subscribes to a channel for the given products. The subscription
+// is remembered and re-sent whenever the client reconnects, so a subscription
+// made while the client is reconnecting takes effect once it has reconnected.
+// ErrWSClosed is returned once the client has been closed or has stopped
+// reading from the feed.
+func (ws *WSClient) Subscribe(channel WSChannel, productIDs ...string) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if ws.stopped() {
+		return ErrWSClosed
+	}
+
+	if !ws.started {
+		return ErrWSNotConnected
+	}
+
+	if ws.subscriptions[channel] == nil {
+		ws.subscriptions[channel] = make(map[string]bool)
+	}
+
+	for _, productID := range productIDs {
+		ws.subscriptions[channel][productID] = true
+	}
+
+	if ws.conn == nil {
+		return nil
+	}
+
+	return ws.subscribe(ws.conn, channel, productIDs)
+}
+
+// SubscribeAndWait subscribes to a channel like Subscribe, then blocks until the
+// feed confirms the subscription on its subscriptions channel. An error is
+// returned if the confirmation does not arrive before the context is done or
+// the client stops reading from the feed.
+func (ws *WSClient) SubscribeAndWait(ctx context.Context, channel WSChannel, productIDs ...string) error {
+	confirmation := &wsConfirmation{
+		channel:    channel,
+		productIDs: productIDs,
+		confirmed:  make(chan struct{}),
+	}
+
+	ws.mu.Lock()
+	ws.confirmations = append(ws.confirmations, confirmation)
+	ws.mu.Unlock()
+
+	defer ws.removeConfirmation(confirmation)
+
+	if err := ws.Subscribe(channel, productIDs...); err != nil {
+		return err
+	}
+
+	select {
+	case <-confirmation.confirmed:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to confirm subscription to %s: %w", channel, ctx.Err())
+	case <-ws.done:
+		return fmt.Errorf("failed to confirm subscription to %s: %w", channel, ErrWSClosed)
+	}
+}
+
+// removeConfirmation stops waiting for the given confirmation.
+func (ws *WSClient) removeConfirmation(confirmation *wsConfirmation) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	for i, pending := range ws.confirmations {
+		if pending == confirmation {
+			ws.confirmations = append(ws.confirmations[:i], ws.confirmations[i+1:]...)
+
+			return
+		}
+	}
+}
+
+// confirm closes the pending confirmations that are satisfied by a message on
+// the subscriptions channel.
+func (ws *WSClient) confirm(msg WSMessage) {
+	var events []wsSubscriptionsEvent
+	if err := json.Unmarshal(msg.Events, &events); err != nil {
+		return
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	pending := ws.confirmations[:0]
+
+	for _, confirmation := range ws.confirmations {
+		confirmed := false
+
+		for _, event := range events {
+			if confirmation.confirmedBy(event) {
+				confirmed = true
+
+				break
+			}
+		}
+
+		if confirmed {
+			close(confirmation.confirmed)
+		} else {
+			pending = append(pending, confirmation)
+		}
+	}
+
+	ws.confirmations = pending
+}
+
+// Close shuts the client down. It unsubscribes from every channel, sends a
+// close frame and waits for the client to stop reading from the feed, after
+// which the Messages and Errors channels are closed. Closing a closed client
+// does nothing.
+func (ws *WSClient) Close() error {
+	ws.mu.Lock()
+
+	if ws.closed {
+		ws.mu.Unlock()
+
+		return nil
+	}
+
+	ws.closed = true
+
+	if !ws.started {
+		ws.mu.Unlock()
+		close(ws.messages)
+		close(ws.errors)
+		close(ws.done)
+
+		return nil
+	}
+
+	conn := ws.conn
+	if conn != nil {
+		// The connection is being closed, so failing to unsubscribe
+		// or to send the close frame only makes the teardown less
+		// graceful.
+		_ = ws.writeSubscriptions(conn, "unsubscribe")
+
+		closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+		_ = conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(ws.writeTimeout))
+	}
+
+	ws.cancel()
+	ws.mu.Unlock()
+
+	// Wait for the server to acknowledge the close frame, which ends the
+	// reader, before forcing the connection closed.
+	select {
+	case <-ws.done:
+	case <-time.After(wsCloseTimeout):
+		if conn != nil {
+			_ = conn.Close()
+		}
+
+		<-ws.done
+	}
+
+	return nil
+}
+
+// unsubscribe forgets the subscription to a channel for the given products and
+// unsubscribes from them if the client is connected.
+func (ws *WSClient) unsubscribe(channel WSChannel, productIDs []string) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if ws.stopped() {
+		return ErrWSClosed
+	}
+
+	for _, productID := range productIDs {
+		delete(ws.subscriptions[channel], productID)
+	}
+
+	if len(ws.subscriptions[channel]) == 0 {
+		delete(ws.subscriptions, channel)
+	}
+
+	if ws.conn == nil {
+		return nil
+	}
+
+	return ws.writeSubscription(ws.conn, "unsubscribe", channel, productIDs)
+}
+
+// subscribe writes a signed subscription message to the connection. The caller
+// must hold the client's lock.
+func (ws *WSClient) subscribe(conn *websocket.Conn, channel WSChannel, productIDs []string) error {
+	return ws.writeSubscription(conn, "subscribe", channel, productIDs)
+}
+
+// writeSubscription writes a signed message of the given type, either
+// "subscribe" or "unsubscribe", to the connection. The caller must hold the
+// client's lock.
+func (ws *WSClient) writeSubscription(conn *websocket.Conn, msgType string, channel WSChannel,
+	productIDs []string,
+) error {
+	formatBase := 10
+	unix := strconv.FormatInt(time.Now().Unix(), formatBase)
+
+	msg := wsSubscribeMessage{
+		Type:       msgType,
+		ProductIDs: productIDs,
+		Channel:    channel,
+		Signature:  sign(ws.secret, unix+string(channel)+strings.Join(productIDs, ",")),
+		APIKey:     ws.key,
+		Timestamp:  unix,
+	}
+
+	if err := conn.SetWriteDeadline(time.Now().Add(ws.writeTimeout)); err != nil {
+		return fmt.Errorf("failed to set write deadline: %w", err)
+	}
+
+	if err := conn.WriteJSON(msg); err != nil {
+		return fmt.Errorf("failed to %s to %s: %w", msgType, channel, err)
+	}
+
+	return nil
+}
+
+// resubscribe writes every remembered subscription to the connection. The
+// caller must hold the client's lock.
+func (ws *WSClient) resubscribe(conn *websocket.Conn) error {
+	return ws.writeSubscriptions(conn, "subscribe")
+}
+
+// writeSubscriptions writes messages of the given type for every remembered
+// subscription to the connection, with at most wsMaxSubscriptionProducts
+// products in each message. The caller must hold the client's lock.
+func (ws *WSClient) writeSubscriptions(conn *websocket.Conn, msgType string) error {
+	for channel, products := range ws.subscriptions {
+		productIDs := make([]string, 0, len(products))
+		for productID := range products {
+			productIDs = append(productIDs, productID)
+		}
+
+		sort.Strings(productIDs)
+
+		// A channel without products, such as heartbeats, still needs
+		// its own message.
+		if len(productIDs) == 0 {
+			if err := ws.writeSubscription(conn, msgType, channel, productIDs); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		err := forEachProductBatch(productIDs, func(batch []string) error {
+			return ws.writeSubscription(conn, msgType, channel, batch)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// run reads from the connection, reconnecting whenever it fails, until the
+// context is cancelled or the client is closed.
+func (ws *WSClient) run(ctx context.Context, conn *websocket.Conn) {
+	defer close(ws.done)
+	defer close(ws.messages)
+	defer close(ws.errors)
+
+	for conn != nil {
+		// The read error only tells us that the connection is no
+		// longer usable, so it is replaced.
+		_ = ws.read(ctx, conn)
+		_ = conn.Close()
+
+		ws.mu.Lock()
+		ws.conn = nil
+		ws.mu.Unlock()
+
+		conn = ws.reconnect(ctx)
+	}
+}
+
+// frameTimeout returns how long to wait for a frame before the connection is
+// considered dead, or zero to wait indefinitely.
+func (ws *WSClient) frameTimeout() time.Duration {
+	if ws.readTimeout > 0 {
+		return ws.readTimeout
+	}
+
+	return wsPongWaitFactor * ws.keepAlive
+}
+
+// read delivers messages from the connection until reading fails or the
+// context is cancelled.
+func (ws *WSClient) read(ctx context.Context, conn *websocket.Conn) error {
+	extendDeadline := func(string) error {
+		if timeout := ws.frameTimeout(); timeout > 0 {
+			return conn.SetReadDeadline(time.Now().Add(timeout))
+		}
+
+		return nil
+	}
+
+	if err := extendDeadline(""); err != nil {
+		return fmt.Errorf("failed to set read deadline: %w", err)
+	}
+
+	conn.SetPongHandler(extendDeadline)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go ws.ping(conn, done)
+	go ws.closeOnCancel(ctx, conn, done)
+
+	// sequenced is set once a message has been received on the
+	// connection, whose sequence number is then last.
+	var (
+		sequenced bool
+		last      int64
+	)
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("failed to read message: %w", err)
+		}
+
+		if err := extendDeadline(""); err != nil {
+			return fmt.Errorf("failed to set read deadline: %w", err)
+		}
+
+		msg := WSMessage{}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			ws.report(&wsMalformedMessageError{err: err})
+
+			continue
+		}
+
+		if msg.Channel == "" {
+			ws.report(unexpectedFrameError(data))
+
+			continue
+		}
+
+		if sequenced && msg.SequenceNum != last+1 {
+			ws.report(fmt.Errorf("%w: got sequence number %d after %d", ErrWSSequenceGap, msg.SequenceNum, last))
+		}
+
+		sequenced, last = true, msg.SequenceNum
+
+		if msg.Channel == wsSubscriptionsChannel {
+			ws.confirm(msg)
+		}
+
+		select {
+		case ws.messages <- msg:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// unexpectedFrameError returns the error reported for a frame without a
+// channel, which carries the feed's message if it is an error frame.
+func unexpectedFrameError(data []byte) error {
+	frame := wsFrame{}
+	_ = json.Unmarshal(data, &frame)
+
+	if frame.Type == "error" {
+		return fmt.Errorf("%w: error: %s", ErrWSUnexpectedMessage, frame.Message)
+	}
+
+	return fmt.Errorf("%w: type %q without a channel", ErrWSUnexpectedMessage, frame.Type)
+}
+
+// closeOnCancel closes the connection once the context is cancelled, unless
+// done is closed first, so that a reader blocked waiting for a frame returns
+// promptly. A client that is being closed is left to finish its close
+// handshake, which Close bounds with its own timeout.
+func (ws *WSClient) closeOnCancel(ctx context.Context, conn *websocket.Conn, done <-chan struct{}) {
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if !ws.isClosed() {
+			_ = conn.Close()
+		}
+	}
+}
+
+// ping sends a ping to the server every keep-alive interval until done is
+// closed. If a ping cannot be sent, the connection is closed so that the
+// reader reconnects.
+func (ws *WSClient) ping(conn *websocket.Conn, done <-chan struct{}) {
+	if ws.keepAlive <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(ws.keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(ws.writeTimeout)); err != nil {
+				_ = conn.Close()
+
+				return
+			}
+		}
+	}
+}
+
+// reconnect dials the feed and re-sends the subscriptions, retrying with
+// exponential backoff until it succeeds or the context is cancelled, in which
+// case it returns nil.
+func (ws *WSClient) reconnect(ctx context.Context) *websocket.Conn {
+	wait := wsMinReconnectWait
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+
+		if wait *= 2; wait > wsMaxReconnectWait {
+			wait = wsMaxReconnectWait
+		}
+
+		conn, _, err := websocket.DefaultDialer.DialContext(ctx, ws.url, nil)
+		if err != nil {
+			continue
+		}
+
+		ws.mu.Lock()
+
+		if ws.closed {
+			ws.mu.Unlock()
+
+			_ = conn.Close()
+
+			return nil
+		}
+
+		if err := ws.resubscribe(conn); err != nil {
+			ws.mu.Unlock()
+
+			_ = conn.Close()
+
+			continue
+		}
+
+		ws.conn = conn
+		ws.mu.Unlock()
+
+		return conn
+	}
+}
@@ -0,0 +1,565 @@
+package coinbase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newWSTestServer starts a WebSocket server that passes each accepted
+// connection, numbered from one, to the given handler.
+func newWSTestServer(t *testing.T, handle func(n int, conn *websocket.Conn)) *httptest.Server {
+	t.Helper()
+
+	var count int32
+
+	upgrader := websocket.Upgrader{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("failed to upgrade connection: %v", err)
+
+			return
+		}
+
+		defer conn.Close()
+
+		handle(int(atomic.AddInt32(&count, 1)), conn)
+	}))
+
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// newTestWSClient returns a WebSocket client for the test server.
+func newTestWSClient(t *testing.T, srv *httptest.Server, opts ...WSOption) *WSClient {
+	t.Helper()
+
+	ws, err := NewWSClient("key", "secret", opts...)
+	if err != nil {
+		t.Fatalf("failed to create websocket client: %v", err)
+	}
+
+	ws.url = "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	return ws
+}
+
+// readSubscription reads a subscription message from the connection.
+func readSubscription(t *testing.T, conn *websocket.Conn) wsSubscribeMessage {
+	t.Helper()
+
+	msg := wsSubscribeMessage{}
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Errorf("failed to read subscription: %v", err)
+	}
+
+	return msg
+}
+
+// drain reads from the connection until it fails, which lets the connection
+// process control frames.
+func drain(conn *websocket.Conn) {
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func TestNewWSClient(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewWSClient("", ""); !errors.Is(err, errInvalidRoundTripArgs) {
+		t.Fatalf("got %v, want %v", err, errInvalidRoundTripArgs)
+	}
+
+	ws, err := NewWSClient("key", "secret")
+	if err != nil {
+		t.Fatalf("failed to create websocket client: %v", err)
+	}
+
+	if err := ws.Subscribe(WSChannelTicker, "BTC-USD"); !errors.Is(err, ErrWSNotConnected) {
+		t.Fatalf("got %v, want %v", err, ErrWSNotConnected)
+	}
+}
+
+func TestWSClientSubscribe(t *testing.T) {
+	t.Parallel()
+
+	subscriptions := make(chan wsSubscribeMessage, 1)
+
+	srv := newWSTestServer(t, func(n int, conn *websocket.Conn) {
+		subscriptions <- readSubscription(t, conn)
+
+		drain(conn)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ws := newTestWSClient(t, srv)
+	if err := ws.Connect(ctx); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	if err := ws.Subscribe(WSChannelLevel2, "ETH-USD", "BTC-USD"); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	msg := <-subscriptions
+
+	if msg.Type != "subscribe" || msg.Channel != WSChannelLevel2 || msg.APIKey != "key" {
+		t.Fatalf("got subscription %+v", msg)
+	}
+
+	if want := sign("secret", msg.Timestamp+"level2ETH-USD,BTC-USD"); msg.Signature != want {
+		t.Fatalf("got signature %q, want %q", msg.Signature, want)
+	}
+}
+
+func TestWSClientKeepAliveReconnects(t *testing.T) {
+	t.Parallel()
+
+	subscribed := make(chan int, 2)
+
+	srv := newWSTestServer(t, func(n int, conn *websocket.Conn) {
+		if n == 1 {
+			// Stop responding: ignore pings rather than replying
+			// with pongs, and never send a message.
+			conn.SetPingHandler(func(string) error { return nil })
+		}
+
+		readSubscription(t, conn)
+		subscribed <- n
+
+		if n > 1 {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"channel":"heartbeats","sequence_num":1}`)); err != nil {
+				t.Errorf("failed to write message: %v", err)
+			}
+		}
+
+		drain(conn)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ws := newTestWSClient(t, srv, WithWSKeepAlive(20*time.Millisecond))
+	if err := ws.Connect(ctx); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	if err := ws.Subscribe(WSChannelHeartbeats); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	select {
+	case msg := <-ws.Messages():
+		if msg.Channel != "heartbeats" || msg.SequenceNum != 1 {
+			t.Fatalf("got message %+v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for a message after reconnecting")
+	}
+
+	if first, second := <-subscribed, <-subscribed; first != 1 || second != 2 {
+		t.Fatalf("got subscriptions on connections %d and %d, want 1 and 2", first, second)
+	}
+}
+
+func TestWSClientKeepAliveHealthy(t *testing.T) {
+	t.Parallel()
+
+	var connections int32
+
+	srv := newWSTestServer(t, func(n int, conn *websocket.Conn) {
+		atomic.AddInt32(&connections, 1)
+
+		readSubscription(t, conn)
+
+		// Keep reading so that pings are answered with pongs, while
+		// staying otherwise idle for several keep-alive intervals.
+		done := make(chan struct{})
+
+		go func() {
+			drain(conn)
+			close(done)
+		}()
+
+		time.Sleep(200 * time.Millisecond)
+
+		body, _ := json.Marshal(WSMessage{Channel: "heartbeats", SequenceNum: 1})
+		if err := conn.WriteMessage(websocket.TextMessage, body); err != nil {
+			t.Errorf("failed to write message: %v", err)
+		}
+
+		<-done
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ws := newTestWSClient(t, srv, WithWSKeepAlive(20*time.Millisecond))
+	if err := ws.Connect(ctx); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	if err := ws.Subscribe(WSChannelHeartbeats); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	select {
+	case msg := <-ws.Messages():
+		if msg.SequenceNum != 1 {
+			t.Fatalf("got message %+v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for a message")
+	}
+
+	if got := atomic.LoadInt32(&connections); got != 1 {
+		t.Fatalf("got %d connections, want 1", got)
+	}
+}
+
+func TestWSClientClose(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan wsSubscribeMessage, 2)
+	closed := make(chan int, 1)
+
+	srv := newWSTestServer(t, func(n int, conn *websocket.Conn) {
+		received <- readSubscription(t, conn)
+		received <- readSubscription(t, conn)
+
+		_, _, err := conn.ReadMessage()
+
+		closeErr := &websocket.CloseError{}
+		if errors.As(err, &closeErr) {
+			closed <- closeErr.Code
+		}
+	})
+
+	ws := newTestWSClient(t, srv)
+	if err := ws.Connect(context.Background()); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	if err := ws.Subscribe(WSChannelTicker, "BTC-USD"); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	if err := ws.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	if msg := <-received; msg.Type != "subscribe" {
+		t.Fatalf("got message type %q, want subscribe", msg.Type)
+	}
+
+	if msg := <-received; msg.Type != "unsubscribe" || msg.Channel != WSChannelTicker {
+		t.Fatalf("got message %+v, want an unsubscribe from the ticker", msg)
+	}
+
+	select {
+	case code := <-closed:
+		if code != websocket.CloseNormalClosure {
+			t.Fatalf("got close code %d, want %d", code, websocket.CloseNormalClosure)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for the close frame")
+	}
+
+	select {
+	case <-ws.done:
+	default:
+		t.Fatalf("reader still running after close")
+	}
+
+	if _, ok := <-ws.Messages(); ok {
+		t.Fatalf("messages channel still open after close")
+	}
+
+	if err := ws.Close(); err != nil {
+		t.Fatalf("failed to close twice: %v", err)
+	}
+
+	if err := ws.Subscribe(WSChannelTicker); !errors.Is(err, ErrWSClosed) {
+		t.Fatalf("got %v, want %v", err, ErrWSClosed)
+	}
+}
+
+func TestWSClientContextCancel(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name   string
+		stream bool
+	}{
+		{name: "streaming", stream: true},
+		{name: "idle", stream: false},
+	} {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := newWSTestServer(t, func(n int, conn *websocket.Conn) {
+				msg := []byte(`{"channel":"ticker","events":[]}`)
+
+				for test.stream {
+					if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+						return
+					}
+				}
+
+				drain(conn)
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ws := newTestWSClient(t, srv)
+			if err := ws.Connect(ctx); err != nil {
+				t.Fatalf("failed to connect: %v", err)
+			}
+
+			if test.stream {
+				<-ws.Messages()
+			}
+
+			cancel()
+
+			select {
+			case <-ws.done:
+			case <-time.After(time.Second):
+				t.Fatalf("reader still running after the context was cancelled")
+			}
+
+			for range ws.Messages() {
+			}
+
+			if err := ws.Close(); err != nil {
+				t.Fatalf("failed to close: %v", err)
+			}
+		})
+	}
+}
+
+func TestWSClientSubscribeAfterContextCancel(t *testing.T) {
+	t.Parallel()
+
+	srv := newWSTestServer(t, func(n int, conn *websocket.Conn) {
+		drain(conn)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ws := newTestWSClient(t, srv)
+	if err := ws.Connect(ctx); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	defer func() { _ = ws.Close() }()
+
+	cancel()
+
+	for range ws.Messages() {
+	}
+
+	if err := ws.Subscribe(WSChannelTicker, "BTC-USD"); !errors.Is(err, ErrWSClosed) {
+		t.Fatalf("got %v, want %v", err, ErrWSClosed)
+	}
+
+	if err := ws.unsubscribe(WSChannelTicker, []string{"BTC-USD"}); !errors.Is(err, ErrWSClosed) {
+		t.Fatalf("got %v, want %v", err, ErrWSClosed)
+	}
+
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer waitCancel()
+
+	err := ws.SubscribeAndWait(waitCtx, WSChannelTicker, "BTC-USD")
+	if !errors.Is(err, ErrWSClosed) {
+		t.Fatalf("got %v, want %v", err, ErrWSClosed)
+	}
+}
+
+func TestWSClientErrors(t *testing.T) {
+	t.Parallel()
+
+	srv := newWSTestServer(t, func(n int, conn *websocket.Conn) {
+		for _, frame := range []string{
+			`{"channel":"heartbeats","sequence_num":0}`,
+			`{"channel":"heartbeats",`,
+			`{"type":"error","message":"failure to subscribe"}`,
+			`{"channel":"heartbeats","sequence_num":1}`,
+			`{"channel":"heartbeats","sequence_num":3}`,
+		} {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(frame)); err != nil {
+				t.Errorf("failed to write message: %v", err)
+			}
+		}
+
+		drain(conn)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ws := newTestWSClient(t, srv)
+	if err := ws.Connect(ctx); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	defer func() { _ = ws.Close() }()
+
+	for _, want := range []int64{0, 1, 3} {
+		select {
+		case msg := <-ws.Messages():
+			if msg.SequenceNum != want {
+				t.Fatalf("got message %+v, want sequence number %d", msg, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for message %d", want)
+		}
+	}
+
+	for _, want := range []error{ErrWSMalformedMessage, ErrWSUnexpectedMessage, ErrWSSequenceGap} {
+		select {
+		case err := <-ws.Errors():
+			if !errors.Is(err, want) {
+				t.Fatalf("got error %v, want %v", err, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for error %v", want)
+		}
+	}
+
+	select {
+	case err := <-ws.Errors():
+		t.Fatalf("got unexpected error %v", err)
+	default:
+	}
+}
+
+func TestWSClientCloseUnconnected(t *testing.T) {
+	t.Parallel()
+
+	ws, err := NewWSClient("key", "secret")
+	if err != nil {
+		t.Fatalf("failed to create websocket client: %v", err)
+	}
+
+	if err := ws.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	if _, ok := <-ws.Messages(); ok {
+		t.Fatalf("messages channel still open after close")
+	}
+
+	if _, ok := <-ws.Errors(); ok {
+		t.Fatalf("errors channel still open after close")
+	}
+
+	if err := ws.Connect(context.Background()); !errors.Is(err, ErrWSClosed) {
+		t.Fatalf("got %v, want %v", err, ErrWSClosed)
+	}
+}
+
+func TestWSClientConnectTwice(t *testing.T) {
+	t.Parallel()
+
+	srv := newWSTestServer(t, func(n int, conn *websocket.Conn) {
+		drain(conn)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ws := newTestWSClient(t, srv)
+	if err := ws.Connect(ctx); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	defer func() { _ = ws.Close() }()
+
+	if err := ws.Connect(context.Background()); !errors.Is(err, ErrWSAlreadyConnected) {
+		t.Fatalf("got %v, want %v", err, ErrWSAlreadyConnected)
+	}
+
+	// Once the first context is cancelled the client stops, and it still
+	// cannot be connected again.
+	cancel()
+
+	for range ws.Messages() {
+	}
+
+	if err := ws.Connect(context.Background()); !errors.Is(err, ErrWSAlreadyConnected) {
+		t.Fatalf("got %v, want %v", err, ErrWSAlreadyConnected)
+	}
+}
+
+func TestWSClientSubscribeAndWait(t *testing.T) {
+	t.Parallel()
+
+	srv := newWSTestServer(t, func(n int, conn *websocket.Conn) {
+		for {
+			msg := wsSubscribeMessage{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+
+			// Only confirm the ticker channel, and list the products
+			// in a different order than they were subscribed in.
+			if msg.Channel != WSChannelTicker {
+				continue
+			}
+
+			confirmation := `{"channel": "subscriptions", "events": [{"subscriptions": {"ticker": ["ETH-USD", "BTC-USD"]}}]}`
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(confirmation)); err != nil {
+				t.Errorf("failed to write confirmation: %v", err)
+			}
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ws := newTestWSClient(t, srv)
+	if err := ws.Connect(ctx); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	// Read the messages so that the reader is never blocked delivering the
+	// confirmation.
+	go func() {
+		for range ws.Messages() {
+		}
+	}()
+
+	waitCtx, waitCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer waitCancel()
+
+	if err := ws.SubscribeAndWait(waitCtx, WSChannelTicker, "BTC-USD", "ETH-USD"); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	timeoutCtx, timeoutCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer timeoutCancel()
+
+	err := ws.SubscribeAndWait(timeoutCtx, WSChannelLevel2, "BTC-USD")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
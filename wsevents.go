@@ -0,0 +1,420 @@
+package coinbase
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// wsStringTimeLayout is the layout of times formatted by Go's time.String, as
+// used by the heartbeats channel, without the monotonic clock reading.
+const wsStringTimeLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+// parseWSTime parses a time from the WebSocket feed, which depending on the
+// channel is an RFC 3339 time, a Unix time in seconds or a time formatted by Go's
+// time.String. The time is returned in UTC, and an empty value is the zero
+// time.
+func parseWSTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	formatBase, bitSize := 10, 64
+	if unix, err := strconv.ParseInt(value, formatBase, bitSize); err == nil {
+		return time.Unix(unix, 0).UTC(), nil
+	}
+
+	if parsed, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return parsed.UTC(), nil
+	}
+
+	// Drop the monotonic clock reading, such as "m=+91717.525857105".
+	if i := strings.Index(value, " m="); i >= 0 {
+		value = value[:i]
+	}
+
+	parsed, err := time.Parse(wsStringTimeLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse time %q: %w", value, err)
+	}
+
+	return parsed.UTC(), nil
+}
+
+// unmarshalTimed decodes the data into "out", which should ignore its Time
+// field, and returns the time parsed from the field with the given key.
+func unmarshalTimed(data []byte, out any, key string) (time.Time, error) {
+	if err := json.Unmarshal(data, out); err != nil {
+		return time.Time{}, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return time.Time{}, err
+	}
+
+	raw, ok := fields[key]
+	if !ok {
+		return time.Time{}, nil
+	}
+
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode %s: %w", key, err)
+	}
+
+	return parseWSTime(value)
+}
+
+// decodeEvents decodes the events of a message.
+func decodeEvents[T any](msg WSMessage) ([]T, error) {
+	var events []T
+	if err := json.Unmarshal(msg.Events, &events); err != nil {
+		return nil, fmt.Errorf("failed to decode %s events: %w", msg.Channel, err)
+	}
+
+	return events, nil
+}
+
+// orTime returns the time, or the fallback if the time is zero.
+func orTime(t, fallback time.Time) time.Time {
+	if t.IsZero() {
+		return fallback
+	}
+
+	return t
+}
+
+// HeartbeatEvent is an event on the heartbeats channel.
+type HeartbeatEvent struct {
+	HeartbeatCounter json.Number `json:"heartbeat_counter"`
+
+	// Time is the time the heartbeat was sent.
+	Time time.Time `json:"-"`
+}
+
+// UnmarshalJSON decodes the event, parsing its current_time.
+func (event *HeartbeatEvent) UnmarshalJSON(data []byte) error {
+	type plain HeartbeatEvent
+
+	var err error
+
+	event.Time, err = unmarshalTimed(data, (*plain)(event), "current_time")
+
+	return err
+}
+
+// HeartbeatEvents decodes the events of a message on the heartbeats channel.
+func (msg WSMessage) HeartbeatEvents() ([]HeartbeatEvent, error) {
+	events, err := decodeEvents[HeartbeatEvent](msg)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range events {
+		events[i].Time = orTime(events[i].Time, msg.Timestamp)
+	}
+
+	return events, nil
+}
+
+// Ticker is the price of a product on the ticker and ticker_batch channels.
+type Ticker struct {
+	Type               string `json:"type"`
+	ProductID          string `json:"product_id"`
+	Price              string `json:"price"`
+	Volume24H          string `json:"volume_24_h"`
+	Low24H             string `json:"low_24_h"`
+	High24H            string `json:"high_24_h"`
+	Low52W             string `json:"low_52_w"`
+	High52W            string `json:"high_52_w"`
+	PricePercentChg24H string `json:"price_percent_chg_24_h"`
+
+	// Time is the time of the message the ticker was sent in, since
+	// tickers are not stamped themselves.
+	Time time.Time `json:"-"`
+
+	// ContractExpiry is the expiry of the contract of a futures product,
+	// if the feed provides it, and is otherwise zero.
+	ContractExpiry time.Time `json:"-"`
+}
+
+// UnmarshalJSON decodes the ticker, parsing its contract_expiry.
+func (ticker *Ticker) UnmarshalJSON(data []byte) error {
+	type plain Ticker
+
+	var err error
+
+	ticker.ContractExpiry, err = unmarshalTimed(data, (*plain)(ticker), "contract_expiry")
+
+	return err
+}
+
+// TickerEvent is an event on the ticker and ticker_batch channels.
+type TickerEvent struct {
+	Type    string   `json:"type"`
+	Tickers []Ticker `json:"tickers"`
+}
+
+// TickerEvents decodes the events of a message on the ticker or ticker_batch
+// channel, for spot and futures products alike.
+func (msg WSMessage) TickerEvents() ([]TickerEvent, error) {
+	events, err := decodeEvents[TickerEvent](msg)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range events {
+		for j := range events[i].Tickers {
+			events[i].Tickers[j].Time = msg.Timestamp
+		}
+	}
+
+	return events, nil
+}
+
+// ProductStatus is the status of a product on the status channel.
+type ProductStatus struct {
+	ProductType    ProductType `json:"product_type"`
+	ID             string      `json:"id"`
+	BaseCurrency   string      `json:"base_currency"`
+	QuoteCurrency  string      `json:"quote_currency"`
+	BaseIncrement  string      `json:"base_increment"`
+	QuoteIncrement string      `json:"quote_increment"`
+	DisplayName    string      `json:"display_name"`
+	Status         string      `json:"status"`
+	StatusMessage  string      `json:"status_message"`
+	MinMarketFunds string      `json:"min_market_funds"`
+
+	// FCMTradingSessionDetails is only set for futures products, if the
+	// feed provides it.
+	FCMTradingSessionDetails *FCMTradingSessionDetails `json:"fcm_trading_session_details,omitempty"`
+
+	// Time is the time of the message the status was sent in, since
+	// statuses are not stamped themselves.
+	Time time.Time `json:"-"`
+}
+
+// IsTradingSessionOpen reports whether the product can be traded: its status is
+// online and, for a futures product whose trading session is given, its
+// session is open. A status event for which it is false surfaces the close of
+// the product's trading session.
+func (status ProductStatus) IsTradingSessionOpen() bool {
+	if !strings.EqualFold(status.Status, productStatusOnline) {
+		return false
+	}
+
+	return status.FCMTradingSessionDetails == nil || status.FCMTradingSessionDetails.IsSessionOpen
+}
+
+// StatusEvent is an event on the status channel.
+type StatusEvent struct {
+	Type     string          `json:"type"`
+	Products []ProductStatus `json:"products"`
+}
+
+// StatusEvents decodes the events of a message on the status channel.
+func (msg WSMessage) StatusEvents() ([]StatusEvent, error) {
+	events, err := decodeEvents[StatusEvent](msg)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range events {
+		for j := range events[i].Products {
+			events[i].Products[j].Time = msg.Timestamp
+		}
+	}
+
+	return events, nil
+}
+
+// Level2Update is a change to a price level of an order book.
+type Level2Update struct {
+	Side        string `json:"side"`
+	PriceLevel  string `json:"price_level"`
+	NewQuantity string `json:"new_quantity"`
+
+	// Time is the time of the update.
+	Time time.Time `json:"-"`
+}
+
+// UnmarshalJSON decodes the update, parsing its event_time.
+func (update *Level2Update) UnmarshalJSON(data []byte) error {
+	type plain Level2Update
+
+	var err error
+
+	update.Time, err = unmarshalTimed(data, (*plain)(update), "event_time")
+
+	return err
+}
+
+// Level2Event is an event on the level2 channel, whose messages are sent on
+// the "l2_data" channel.
+type Level2Event struct {
+	Type      string         `json:"type"`
+	ProductID string         `json:"product_id"`
+	Updates   []Level2Update `json:"updates"`
+}
+
+// Level2Events decodes the events of a message on the level2 channel.
+func (msg WSMessage) Level2Events() ([]Level2Event, error) {
+	events, err := decodeEvents[Level2Event](msg)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range events {
+		for j := range events[i].Updates {
+			events[i].Updates[j].Time = orTime(events[i].Updates[j].Time, msg.Timestamp)
+		}
+	}
+
+	return events, nil
+}
+
+// MarketTrade is a trade on the market_trades channel.
+type MarketTrade struct {
+	TradeID   string    `json:"trade_id"`
+	ProductID string    `json:"product_id"`
+	Price     string    `json:"price"`
+	Size      string    `json:"size"`
+	Side      OrderSide `json:"side"`
+
+	// Time is the time of the trade.
+	Time time.Time `json:"-"`
+}
+
+// UnmarshalJSON decodes the trade, parsing its time.
+func (trade *MarketTrade) UnmarshalJSON(data []byte) error {
+	type plain MarketTrade
+
+	var err error
+
+	trade.Time, err = unmarshalTimed(data, (*plain)(trade), "time")
+
+	return err
+}
+
+// MarketTradesEvent is an event on the market_trades channel.
+type MarketTradesEvent struct {
+	Type   string        `json:"type"`
+	Trades []MarketTrade `json:"trades"`
+}
+
+// MarketTradesEvents decodes the events of a message on the market_trades
+// channel.
+func (msg WSMessage) MarketTradesEvents() ([]MarketTradesEvent, error) {
+	events, err := decodeEvents[MarketTradesEvent](msg)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range events {
+		for j := range events[i].Trades {
+			events[i].Trades[j].Time = orTime(events[i].Trades[j].Time, msg.Timestamp)
+		}
+	}
+
+	return events, nil
+}
+
+// WSCandle is a candle on the candles channel.
+type WSCandle struct {
+	ProductID string `json:"product_id"`
+	Start     string `json:"start"`
+	Low       string `json:"low"`
+	High      string `json:"high"`
+	Open      string `json:"open"`
+	Close     string `json:"close"`
+	Volume    string `json:"volume"`
+
+	// Time is the start of the candle.
+	Time time.Time `json:"-"`
+}
+
+// UnmarshalJSON decodes the candle, parsing its start.
+func (candle *WSCandle) UnmarshalJSON(data []byte) error {
+	type plain WSCandle
+
+	var err error
+
+	candle.Time, err = unmarshalTimed(data, (*plain)(candle), "start")
+
+	return err
+}
+
+// CandlesEvent is an event on the candles channel.
+type CandlesEvent struct {
+	Type    string     `json:"type"`
+	Candles []WSCandle `json:"candles"`
+}
+
+// CandlesEvents decodes the events of a message on the candles channel.
+func (msg WSMessage) CandlesEvents() ([]CandlesEvent, error) {
+	events, err := decodeEvents[CandlesEvent](msg)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range events {
+		for j := range events[i].Candles {
+			events[i].Candles[j].Time = orTime(events[i].Candles[j].Time, msg.Timestamp)
+		}
+	}
+
+	return events, nil
+}
+
+// UserOrder is an update to one of the user's orders on the user channel.
+type UserOrder struct {
+	OrderID            string      `json:"order_id"`
+	ClientOrderID      string      `json:"client_order_id"`
+	CumulativeQuantity string      `json:"cumulative_quantity"`
+	LeavesQuantity     string      `json:"leaves_quantity"`
+	AvgPrice           string      `json:"avg_price"`
+	TotalFees          string      `json:"total_fees"`
+	Status             OrderStatus `json:"status"`
+	ProductID          string      `json:"product_id"`
+	OrderSide          OrderSide   `json:"order_side"`
+	OrderType          string      `json:"order_type"`
+
+	// Time is the time the order was created.
+	Time time.Time `json:"-"`
+}
+
+// UnmarshalJSON decodes the order, parsing its creation_time.
+func (order *UserOrder) UnmarshalJSON(data []byte) error {
+	type plain UserOrder
+
+	var err error
+
+	order.Time, err = unmarshalTimed(data, (*plain)(order), "creation_time")
+
+	return err
+}
+
+// UserEvent is an event on the user channel.
+type UserEvent struct {
+	Type   string      `json:"type"`
+	Orders []UserOrder `json:"orders"`
+}
+
+// UserEvents decodes the events of a message on the user channel.
+func (msg WSMessage) UserEvents() ([]UserEvent, error) {
+	events, err := decodeEvents[UserEvent](msg)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range events {
+		for j := range events[i].Orders {
+			events[i].Orders[j].Time = orTime(events[i].Orders[j].Time, msg.Timestamp)
+		}
+	}
+
+	return events, nil
+}
@@ -0,0 +1,338 @@
+package coinbase
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// decodeWSMessage decodes a message from the WebSocket feed.
+func decodeWSMessage(t *testing.T, data string) WSMessage {
+	t.Helper()
+
+	msg := WSMessage{}
+	if err := json.Unmarshal([]byte(data), &msg); err != nil {
+		t.Fatalf("failed to decode message: %v", err)
+	}
+
+	return msg
+}
+
+func TestParseWSTime(t *testing.T) {
+	t.Parallel()
+
+	want := time.Date(2023, time.June, 23, 20, 31, 56, 121961769, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Time
+	}{
+		{name: "empty", value: "", want: time.Time{}},
+		{name: "rfc3339", value: "2023-06-23T20:31:56.121961769Z", want: want},
+		{name: "rfc3339 offset", value: "2023-06-23T22:31:56.121961769+02:00", want: want},
+		{name: "go string", value: "2023-06-23 20:31:56.121961769 +0000 UTC m=+91717.525857105", want: want},
+		{name: "unix", value: "1687552316", want: want.Truncate(time.Second)},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseWSTime(test.value)
+			if err != nil {
+				t.Fatalf("failed to parse time: %v", err)
+			}
+
+			if !got.Equal(test.want) || got.Location() != time.UTC {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	if _, err := parseWSTime("yesterday"); err == nil {
+		t.Fatalf("parsed an invalid time")
+	}
+}
+
+func TestWSEventTimes(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2023, time.February, 9, 20, 30, 37, 167359596, time.UTC)
+
+	tests := []struct {
+		name   string
+		frame  string
+		decode func(WSMessage) (time.Time, error)
+		want   time.Time
+	}{
+		{
+			name: "heartbeats",
+			frame: `{"channel": "heartbeats", "timestamp": "2023-02-09T20:30:37.167359596Z", "events": [{
+				"current_time": "2023-06-23 20:31:56.121961769 +0000 UTC m=+91717.525857105",
+				"heartbeat_counter": 3049
+			}]}`,
+			decode: func(msg WSMessage) (time.Time, error) {
+				events, err := msg.HeartbeatEvents()
+				if err != nil {
+					return time.Time{}, err
+				}
+
+				if events[0].HeartbeatCounter != "3049" {
+					t.Errorf("got heartbeat counter %q, want 3049", events[0].HeartbeatCounter)
+				}
+
+				return events[0].Time, nil
+			},
+			want: time.Date(2023, time.June, 23, 20, 31, 56, 121961769, time.UTC),
+		},
+		{
+			name: "ticker",
+			frame: `{"channel": "ticker", "timestamp": "2023-02-09T20:30:37.167359596Z", "events": [{
+				"type": "snapshot",
+				"tickers": [{"type": "ticker", "product_id": "BTC-USD", "price": "21932.98"}]
+			}]}`,
+			decode: func(msg WSMessage) (time.Time, error) {
+				events, err := msg.TickerEvents()
+				if err != nil {
+					return time.Time{}, err
+				}
+
+				return events[0].Tickers[0].Time, nil
+			},
+			want: timestamp,
+		},
+		{
+			name: "status",
+			frame: `{"channel": "status", "timestamp": "2023-02-09T20:30:37.167359596Z", "events": [{
+				"type": "snapshot",
+				"products": [{"product_type": "SPOT", "id": "BTC-USD", "status": "online"}]
+			}]}`,
+			decode: func(msg WSMessage) (time.Time, error) {
+				events, err := msg.StatusEvents()
+				if err != nil {
+					return time.Time{}, err
+				}
+
+				return events[0].Products[0].Time, nil
+			},
+			want: timestamp,
+		},
+		{
+			name: "level2",
+			frame: `{"channel": "l2_data", "timestamp": "2023-02-09T20:30:37.167359596Z", "events": [{
+				"type": "update",
+				"product_id": "BTC-USD",
+				"updates": [{
+					"side": "bid",
+					"event_time": "2023-02-09T20:30:37.046745Z",
+					"price_level": "21921.73",
+					"new_quantity": "0.06317902"
+				}]
+			}]}`,
+			decode: func(msg WSMessage) (time.Time, error) {
+				events, err := msg.Level2Events()
+				if err != nil {
+					return time.Time{}, err
+				}
+
+				return events[0].Updates[0].Time, nil
+			},
+			want: time.Date(2023, time.February, 9, 20, 30, 37, 46745000, time.UTC),
+		},
+		{
+			name: "market trades",
+			frame: `{"channel": "market_trades", "timestamp": "2023-02-09T20:30:37.167359596Z", "events": [{
+				"type": "update",
+				"trades": [{
+					"trade_id": "000000000",
+					"product_id": "ETH-USD",
+					"price": "1260.01",
+					"size": "0.3",
+					"side": "BUY",
+					"time": "2019-08-14T20:42:27.265Z"
+				}]
+			}]}`,
+			decode: func(msg WSMessage) (time.Time, error) {
+				events, err := msg.MarketTradesEvents()
+				if err != nil {
+					return time.Time{}, err
+				}
+
+				return events[0].Trades[0].Time, nil
+			},
+			want: time.Date(2019, time.August, 14, 20, 42, 27, 265000000, time.UTC),
+		},
+		{
+			name: "candles",
+			frame: `{"channel": "candles", "timestamp": "2023-02-09T20:30:37.167359596Z", "events": [{
+				"type": "snapshot",
+				"candles": [{
+					"start": "1688998200",
+					"high": "1867.72",
+					"low": "1865.63",
+					"open": "1867.38",
+					"close": "1866.81",
+					"volume": "0.20269406",
+					"product_id": "ETH-USD"
+				}]
+			}]}`,
+			decode: func(msg WSMessage) (time.Time, error) {
+				events, err := msg.CandlesEvents()
+				if err != nil {
+					return time.Time{}, err
+				}
+
+				return events[0].Candles[0].Time, nil
+			},
+			want: time.Unix(1688998200, 0).UTC(),
+		},
+		{
+			name: "user",
+			frame: `{"channel": "user", "timestamp": "2023-02-09T20:30:37.167359596Z", "events": [{
+				"type": "snapshot",
+				"orders": [{
+					"order_id": "XXX",
+					"client_order_id": "YYY",
+					"status": "OPEN",
+					"product_id": "BTC-USD",
+					"creation_time": "2022-12-07T19:42:18.719312Z",
+					"order_side": "BUY",
+					"order_type": "Limit"
+				}]
+			}]}`,
+			decode: func(msg WSMessage) (time.Time, error) {
+				events, err := msg.UserEvents()
+				if err != nil {
+					return time.Time{}, err
+				}
+
+				return events[0].Orders[0].Time, nil
+			},
+			want: time.Date(2022, time.December, 7, 19, 42, 18, 719312000, time.UTC),
+		},
+		{
+			name: "user without creation time",
+			frame: `{"channel": "user", "timestamp": "2023-02-09T20:30:37.167359596Z", "events": [{
+				"type": "update",
+				"orders": [{"order_id": "XXX", "status": "FILLED"}]
+			}]}`,
+			decode: func(msg WSMessage) (time.Time, error) {
+				events, err := msg.UserEvents()
+				if err != nil {
+					return time.Time{}, err
+				}
+
+				return events[0].Orders[0].Time, nil
+			},
+			want: timestamp,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := test.decode(decodeWSMessage(t, test.frame))
+			if err != nil {
+				t.Fatalf("failed to decode events: %v", err)
+			}
+
+			if !got.Equal(test.want) {
+				t.Fatalf("got time %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWSFuturesTicker(t *testing.T) {
+	t.Parallel()
+
+	msg := decodeWSMessage(t, `{"channel": "ticker", "timestamp": "2023-07-20T14:00:00.5Z", "events": [{
+		"type": "update",
+		"tickers": [{
+			"type": "ticker",
+			"product_id": "BIT-28JUL23-CDE",
+			"price": "29950",
+			"volume_24_h": "1200",
+			"contract_expiry": "2023-07-28T15:00:00Z"
+		}]
+	}]}`)
+
+	events, err := msg.TickerEvents()
+	if err != nil {
+		t.Fatalf("failed to decode ticker events: %v", err)
+	}
+
+	ticker := events[0].Tickers[0]
+	if ticker.ProductID != "BIT-28JUL23-CDE" || ticker.Price != "29950" {
+		t.Fatalf("got ticker %+v, want the futures ticker", ticker)
+	}
+
+	if want := time.Date(2023, time.July, 28, 15, 0, 0, 0, time.UTC); !ticker.ContractExpiry.Equal(want) {
+		t.Fatalf("got contract expiry %v, want %v", ticker.ContractExpiry, want)
+	}
+
+	if want := time.Date(2023, time.July, 20, 14, 0, 0, 500000000, time.UTC); !ticker.Time.Equal(want) {
+		t.Fatalf("got time %v, want %v", ticker.Time, want)
+	}
+}
+
+func TestWSStatusEvents(t *testing.T) {
+	t.Parallel()
+
+	msg := decodeWSMessage(t, `{"channel": "status", "timestamp": "2023-07-20T14:00:00Z", "events": [{
+		"type": "update",
+		"products": [
+			{"product_type": "SPOT", "id": "BTC-USD", "status": "online"},
+			{"product_type": "SPOT", "id": "XYZ-USD", "status": "delisted"},
+			{
+				"product_type": "FUTURE",
+				"id": "BIT-28JUL23-CDE",
+				"status": "online",
+				"fcm_trading_session_details": {
+					"is_session_open": false,
+					"open_time": "2023-07-20T22:00:00Z",
+					"close_time": "2023-07-21T21:00:00Z"
+				}
+			},
+			{
+				"product_type": "FUTURE",
+				"id": "ET-28JUL23-CDE",
+				"status": "online",
+				"fcm_trading_session_details": {"is_session_open": true}
+			}
+		]
+	}]}`)
+
+	events, err := msg.StatusEvents()
+	if err != nil {
+		t.Fatalf("failed to decode status events: %v", err)
+	}
+
+	want := map[string]bool{
+		"BTC-USD":         true,
+		"XYZ-USD":         false,
+		"BIT-28JUL23-CDE": false,
+		"ET-28JUL23-CDE":  true,
+	}
+
+	products := events[0].Products
+	if len(products) != len(want) {
+		t.Fatalf("got %d products, want %d", len(products), len(want))
+	}
+
+	for _, product := range products {
+		if got := product.IsTradingSessionOpen(); got != want[product.ID] {
+			t.Errorf("got open %v for %s, want %v", got, product.ID, want[product.ID])
+		}
+	}
+
+	if products[2].ProductType != ProductTypeFuture {
+		t.Fatalf("got product type %q, want %q", products[2].ProductType, ProductTypeFuture)
+	}
+}
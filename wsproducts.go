@@ -0,0 +1,175 @@
+package coinbase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+const (
+	// wsMaxSubscriptionProducts is the number of products subscribed to by
+	// each subscription message that SubscribeAllProducts sends, and that
+	// is re-sent on reconnecting, which keeps the messages small however
+	// many products are listed.
+	wsMaxSubscriptionProducts = 100
+
+	// defaultWSProductRefreshInterval is the refresh interval used by
+	// SubscribeAllProducts when a non-positive interval is given.
+	defaultWSProductRefreshInterval = 5 * time.Minute
+)
+
+// SubscribeAllProducts subscribes to a channel for every tradable product
+// quoted in the currency, as listed by ListProductsByQuote and checked by
+// Product.CanTrade, with at most 100 products in each subscription message.
+// The products are listed before SubscribeAllProducts returns, which fails if
+// they cannot be.
+//
+// The products are listed again every refresh interval, or every five minutes
+// if the interval is not positive, until the context is cancelled or the
+// client is closed. Products that have become tradable are subscribed to and
+// products that are no longer listed or tradable are unsubscribed from, even
+// if they were also subscribed to with Subscribe. A refresh that fails is
+// skipped.
+func (ws *WSClient) SubscribeAllProducts(ctx context.Context, client *Client, channel WSChannel, quote string,
+	refreshInterval time.Duration, opts ...CallOption,
+) error {
+	if refreshInterval <= 0 {
+		refreshInterval = defaultWSProductRefreshInterval
+	}
+
+	productIDs, err := tradableProductIDs(ctx, client, quote, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := ws.subscribeBatches(channel, productIDs); err != nil {
+		return err
+	}
+
+	go ws.refreshProducts(ctx, client, channel, quote, refreshInterval, productIDs, opts)
+
+	return nil
+}
+
+// refreshProducts lists the tradable products quoted in the currency every
+// interval and updates the channel's subscriptions to match, until the context
+// is cancelled or the client stops reading from the feed.
+func (ws *WSClient) refreshProducts(ctx context.Context, client *Client, channel WSChannel, quote string,
+	refreshInterval time.Duration, productIDs []string, opts []CallOption,
+) {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	subscribed := make(map[string]bool, len(productIDs))
+	for _, productID := range productIDs {
+		subscribed[productID] = true
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ws.done:
+			return
+		case <-ticker.C:
+		}
+
+		productIDs, err := tradableProductIDs(ctx, client, quote, opts)
+		if err != nil {
+			continue
+		}
+
+		listed := make(map[string]bool, len(productIDs))
+
+		var added, removed []string
+
+		for _, productID := range productIDs {
+			listed[productID] = true
+
+			if !subscribed[productID] {
+				added = append(added, productID)
+			}
+		}
+
+		for productID := range subscribed {
+			if !listed[productID] {
+				removed = append(removed, productID)
+			}
+		}
+
+		sort.Strings(removed)
+
+		err = ws.subscribeBatches(channel, added)
+		if err == nil {
+			err = ws.unsubscribeBatches(channel, removed)
+		}
+
+		if errors.Is(err, ErrWSClosed) {
+			return
+		}
+
+		// Subscribing and unsubscribing again are harmless, so the
+		// products are compared with the same ones at the next refresh
+		// if any batch failed.
+		if err == nil {
+			subscribed = listed
+		}
+	}
+}
+
+// tradableProductIDs returns the sorted IDs of the tradable products quoted in
+// the currency.
+func tradableProductIDs(ctx context.Context, client *Client, quote string, opts []CallOption) ([]string, error) {
+	products, err := client.ListProductsByQuote(ctx, quote, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var productIDs []string
+
+	for _, product := range products {
+		if product.CanTrade() == nil {
+			productIDs = append(productIDs, product.ProductID)
+		}
+	}
+
+	sort.Strings(productIDs)
+
+	return productIDs, nil
+}
+
+// subscribeBatches subscribes to the channel for the products, at most
+// wsMaxSubscriptionProducts at a time.
+func (ws *WSClient) subscribeBatches(channel WSChannel, productIDs []string) error {
+	return forEachProductBatch(productIDs, func(batch []string) error {
+		return ws.Subscribe(channel, batch...)
+	})
+}
+
+// unsubscribeBatches unsubscribes from the channel for the products, at most
+// wsMaxSubscriptionProducts at a time.
+func (ws *WSClient) unsubscribeBatches(channel WSChannel, productIDs []string) error {
+	return forEachProductBatch(productIDs, func(batch []string) error {
+		return ws.unsubscribe(channel, batch)
+	})
+}
+
+// forEachProductBatch calls fn with consecutive batches of at most
+// wsMaxSubscriptionProducts products, stopping at the first error.
+func forEachProductBatch(productIDs []string, fn func(batch []string) error) error {
+	for len(productIDs) > 0 {
+		size := wsMaxSubscriptionProducts
+		if len(productIDs) < size {
+			size = len(productIDs)
+		}
+
+		if err := fn(productIDs[:size]); err != nil {
+			return err
+		}
+
+		productIDs = productIDs[size:]
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package coinbase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// mockProductLists returns a mock HTTP client that responds to the first
+// product listing with the first list of products and to every later one with
+// the second.
+func mockProductLists(t *testing.T, first, second []Product) mockDoFunc {
+	t.Helper()
+
+	var calls int32
+
+	return func(*http.Request) (*http.Response, error) {
+		data := second
+		if atomic.AddInt32(&calls, 1) == 1 {
+			data = first
+		}
+
+		body, err := json.Marshal(map[string]any{"products": data, "num_products": len(data)})
+		if err != nil {
+			return nil, err
+		}
+
+		return newMockResponse(http.StatusOK, string(body)), nil
+	}
+}
+
+func TestWSClientSubscribeAllProducts(t *testing.T) {
+	t.Parallel()
+
+	var first []Product
+
+	for i := 0; i < wsMaxSubscriptionProducts+1; i++ {
+		first = append(first, Product{ProductID: fmt.Sprintf("P%03d-USD", i), Status: productStatusOnline})
+	}
+
+	first = append(first,
+		Product{ProductID: "BTC-EUR", Status: productStatusOnline},
+		Product{ProductID: "OFF-USD", Status: productStatusOnline, TradingDisabled: true},
+	)
+
+	second := append([]Product{{ProductID: "NEW-USD", Status: productStatusOnline}}, first[1:]...)
+
+	subscriptions := make(chan wsSubscribeMessage, 8)
+
+	srv := newWSTestServer(t, func(n int, conn *websocket.Conn) {
+		for {
+			msg := wsSubscribeMessage{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+
+			subscriptions <- msg
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ws := newTestWSClient(t, srv)
+	if err := ws.Connect(ctx); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	defer func() { _ = ws.Close() }()
+
+	client := &Client{httpClient: mockProductLists(t, first, second)}
+
+	if err := ws.SubscribeAllProducts(ctx, client, WSChannelTicker, "usd", 10*time.Millisecond); err != nil {
+		t.Fatalf("failed to subscribe to all products: %v", err)
+	}
+
+	want := []struct {
+		msgType    string
+		productIDs []string
+	}{
+		{msgType: "subscribe", productIDs: productIDs(first[:wsMaxSubscriptionProducts])},
+		{msgType: "subscribe", productIDs: []string{first[wsMaxSubscriptionProducts].ProductID}},
+		{msgType: "subscribe", productIDs: []string{"NEW-USD"}},
+		{msgType: "unsubscribe", productIDs: []string{"P000-USD"}},
+	}
+
+	for _, want := range want {
+		select {
+		case msg := <-subscriptions:
+			if msg.Type != want.msgType || msg.Channel != WSChannelTicker ||
+				!reflect.DeepEqual(msg.ProductIDs, want.productIDs) {
+				t.Fatalf("got %s to %s for %v, want %s for %v",
+					msg.Type, msg.Channel, msg.ProductIDs, want.msgType, want.productIDs)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting to %s for %v", want.msgType, want.productIDs)
+		}
+	}
+
+	// Later refreshes list the same products, so nothing more is sent.
+	select {
+	case msg := <-subscriptions:
+		t.Fatalf("got unexpected %s for %v", msg.Type, msg.ProductIDs)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// productIDs returns the IDs of the products.
+func productIDs(products []Product) []string {
+	ids := make([]string, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ProductID)
+	}
+
+	return ids
+}
+
+func TestWSClientSubscribeAllProductsNotConnected(t *testing.T) {
+	t.Parallel()
+
+	ws, err := NewWSClient("key", "secret")
+	if err != nil {
+		t.Fatalf("failed to create websocket client: %v", err)
+	}
+
+	products := []Product{{ProductID: "BTC-USD", Status: productStatusOnline}}
+	client := &Client{httpClient: mockProductLists(t, products, products)}
+
+	err = ws.SubscribeAllProducts(context.Background(), client, WSChannelTicker, "USD", time.Minute)
+	if !errors.Is(err, ErrWSNotConnected) {
+		t.Fatalf("got %v, want %v", err, ErrWSNotConnected)
+	}
+}
+
+func TestWSClientResubscribeBatches(t *testing.T) {
+	t.Parallel()
+
+	productIDs := make([]string, 0, 2*wsMaxSubscriptionProducts+50)
+	for i := 0; i < cap(productIDs); i++ {
+		productIDs = append(productIDs, fmt.Sprintf("P%03d-USD", i))
+	}
+
+	resubscriptions := make(chan []string, 8)
+
+	srv := newWSTestServer(t, func(n int, conn *websocket.Conn) {
+		if n == 1 {
+			// Drop the first connection once the subscription has been
+			// read, so that the client reconnects.
+			readSubscription(t, conn)
+
+			return
+		}
+
+		for {
+			msg := wsSubscribeMessage{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+
+			if msg.Type == "subscribe" {
+				resubscriptions <- msg.ProductIDs
+			}
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ws := newTestWSClient(t, srv)
+	if err := ws.Connect(ctx); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+
+	defer func() { _ = ws.Close() }()
+
+	if err := ws.Subscribe(WSChannelTicker, productIDs...); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	want := [][]string{
+		productIDs[:wsMaxSubscriptionProducts],
+		productIDs[wsMaxSubscriptionProducts : 2*wsMaxSubscriptionProducts],
+		productIDs[2*wsMaxSubscriptionProducts:],
+	}
+
+	for i, want := range want {
+		select {
+		case got := <-resubscriptions:
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("got frame %d with %d products, want %d", i, len(got), len(want))
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for frame %d after reconnecting", i)
+		}
+	}
+
+	select {
+	case got := <-resubscriptions:
+		t.Fatalf("got unexpected frame with %d products", len(got))
+	case <-time.After(50 * time.Millisecond):
+	}
+}